@@ -0,0 +1,113 @@
+package lyra
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// AdaptiveLimiter limits the number of tasks executing at the same time,
+// adjusting the limit to the observed task latency. It starts at
+// runtime.GOMAXPROCS and raises the limit while tasks run as fast as their
+// fastest recorded execution, which is typical of IO-bound tasks, and lowers
+// it when tasks slow down under load, which is typical of CPU-bound tasks
+// competing for processors.
+//
+// Share one AdaptiveLimiter between runs of a DAG so every run starts from
+// what earlier runs learned. It is safe for concurrent use.
+type AdaptiveLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	maxLimit  int
+	inFlight  int
+	baselines map[string]time.Duration
+	// gradient is a moving average of the fastest recorded duration of a
+	// task divided by its latest duration; 1 means tasks are not slowed down.
+	gradient float64
+	// wake is closed and replaced whenever a slot frees up or the limit
+	// grows.
+	wake chan struct{}
+}
+
+const (
+	// adaptiveBackoff is the gradient below which the limit is lowered.
+	adaptiveBackoff = 0.5
+	// adaptiveGrowth is the gradient above which a saturated limit grows.
+	adaptiveGrowth = 0.8
+	// adaptiveSmoothing is the weight of the latest sample in the gradient.
+	adaptiveSmoothing = 0.2
+)
+
+// NewAdaptiveLimiter creates an AdaptiveLimiter that never allows more than
+// maxLimit tasks at once. A non-positive maxLimit defaults to 16 times
+// runtime.GOMAXPROCS.
+func NewAdaptiveLimiter(maxLimit int) *AdaptiveLimiter {
+	procs := runtime.GOMAXPROCS(0)
+	if maxLimit <= 0 {
+		maxLimit = 16 * procs
+	}
+	return &AdaptiveLimiter{
+		limit:     min(procs, maxLimit),
+		maxLimit:  maxLimit,
+		baselines: make(map[string]time.Duration),
+		gradient:  1,
+		wake:      make(chan struct{}),
+	}
+}
+
+// Limit returns the current number of tasks allowed to execute at once.
+func (a *AdaptiveLimiter) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.limit
+}
+
+// acquire blocks until a task may execute or ctx is done.
+func (a *AdaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		if a.inFlight < a.limit {
+			a.inFlight++
+			a.mu.Unlock()
+			return nil
+		}
+		wake := a.wake
+		a.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// release records that the task finished after d and adjusts the limit.
+func (a *AdaptiveLimiter) release(taskID string, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	saturated := a.inFlight >= a.limit
+	a.inFlight--
+
+	baseline, ok := a.baselines[taskID]
+	if !ok || d < baseline {
+		baseline = d
+		a.baselines[taskID] = d
+	}
+	if d > 0 {
+		sample := float64(baseline) / float64(d)
+		a.gradient = (1-adaptiveSmoothing)*a.gradient + adaptiveSmoothing*sample
+	}
+
+	switch {
+	case a.gradient < adaptiveBackoff:
+		a.limit = max(1, a.limit*3/4)
+	case saturated && a.gradient > adaptiveGrowth:
+		a.limit = min(a.maxLimit, a.limit+1)
+	}
+
+	close(a.wake)
+	a.wake = make(chan struct{})
+}
@@ -0,0 +1,151 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name      string
+		limit     int
+		durations []time.Duration
+		expected  int
+	}{
+		{
+			name:      "grows while saturated and latency is flat",
+			limit:     2,
+			durations: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond},
+			expected:  5,
+		},
+		{
+			name:      "stops at the maximum",
+			limit:     2,
+			durations: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			expected:  5,
+		},
+		{
+			name:      "backs off when latency grows",
+			limit:     4,
+			durations: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+			expected:  2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := NewAdaptiveLimiter(5)
+			limiter.limit = tc.limit
+			for _, d := range tc.durations {
+				// Saturate the limit, then finish every task after d.
+				slots := limiter.Limit()
+				for range slots {
+					require.NoError(t, limiter.acquire(context.Background()))
+				}
+				for range slots {
+					limiter.release("task", d)
+				}
+			}
+
+			require.Equal(t, tc.expected, limiter.Limit())
+		})
+	}
+}
+
+func TestAdaptiveLimiterDefaults(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewAdaptiveLimiter(0)
+
+	require.Equal(t, runtime.GOMAXPROCS(0), limiter.Limit())
+	require.Equal(t, 16*runtime.GOMAXPROCS(0), limiter.maxLimit)
+}
+
+func TestAdaptiveLimiterAcquireCanceled(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewAdaptiveLimiter(1)
+	require.NoError(t, limiter.acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, limiter.acquire(ctx), context.DeadlineExceeded)
+}
+
+func TestRunWithAdaptiveConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+	)
+	l := New()
+	for i := range 50 {
+		l.Do(fmt.Sprintf("task%d", i), func(ctx context.Context) (int, error) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return i, nil
+		})
+	}
+	limiter := NewAdaptiveLimiter(3)
+
+	result, err := l.Run(context.Background(), nil, WithAdaptiveConcurrency(limiter))
+
+	require.NoError(t, err)
+	require.Len(t, result.Redacted(), 50)
+	require.LessOrEqual(t, peak, 3)
+	require.Zero(t, limiter.inFlight)
+}
+
+func TestRunWithAdaptiveConcurrencyCanceled(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 4)
+	l := New()
+	for i := range 4 {
+		l.Do(fmt.Sprintf("task%d", i), func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+
+	limiter := NewAdaptiveLimiter(4)
+	limiter.limit = 1 // the other tasks queue behind the limiter
+
+	run := l.RunAsync(context.Background(), nil, WithAdaptiveConcurrency(limiter))
+	<-started
+	require.Eventually(t, func() bool { return l.Metrics().QueuedTasks == 3 }, time.Second, time.Millisecond)
+	run.Cancel()
+
+	_, err := run.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+	var canceledErr *errors.RunCanceledError
+	require.ErrorAs(t, err, &canceledErr)
+	require.Len(t, canceledErr.Canceled, 3)
+	for _, taskID := range canceledErr.Canceled {
+		status, _ := run.TaskStatus(taskID)
+		require.Equal(t, TaskCanceled, status, taskID)
+	}
+	require.Zero(t, l.Metrics().QueuedTasks)
+}
@@ -0,0 +1,71 @@
+package lyra
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithAllowedFailures lets up to n tasks fail without failing the run, for
+// pipelines with best-effort branches. The tasks depending on a failed task
+// are canceled while the rest of the run continues; the failures are
+// reported by Result.Failures. The failure of a critical task (see
+// WithCritical), or of one more task, fails the run as usual.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs, lyra.WithAllowedFailures(2))
+func WithAllowedFailures(n int) RunOption {
+	return func(cfg *runConfig) {
+		cfg.allowedFailures = n
+	}
+}
+
+// WithCritical marks the task as critical: its failure fails the run even
+// when failures are allowed with WithAllowedFailures.
+func WithCritical() TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Critical = true
+	})
+}
+
+// Failures returns the errors of the tasks whose failure did not fail the
+// run, keyed by task ID: failures allowed with WithAllowedFailures, the
+// failures of task groups (see WithGroup), of tasks read only by tasks with
+// a quorum (see WithQuorum) and of tasks with a fallback (see WithFallback),
+// and panics of tasks with the PanicSkipDependents policy.
+func (r *Result) Failures() map[string]error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return maps.Clone(r.failures)
+}
+
+// setFailure records that the task failed with err without failing the
+// run. Callers must hold r.mu.
+func (r *Result) setFailure(taskID string, err error) {
+	if r.failures == nil {
+		r.failures = make(map[string]error)
+	}
+	r.failures[taskID] = err
+}
+
+// allowFailure reports whether the failure of the task with err is within
+// the failures allowed for the run, in which case the tasks depending on it
+// are canceled.
+func (s *runState) allowFailure(task *compiledTask, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if task.GetOptions().Critical || s.allowedFailures >= s.cfg.allowedFailures {
+		return false
+	}
+	s.allowedFailures++
+	s.cancelTasks(slices.Clone(s.strictDependents()[task.GetID()]))
+
+	s.result.mu.Lock()
+	defer s.result.mu.Unlock()
+	s.result.setFailure(task.GetID(), err)
+	return true
+}
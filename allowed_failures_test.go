@@ -0,0 +1,67 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newBestEffortLyra(opts ...TaskOption) *Lyra {
+	return New().
+		Do("base", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("geo", func(ctx context.Context, _ int) (int, error) { return 0, errTaskFailed }, Use("base")).
+		Do("weather", func(ctx context.Context, _ int) (int, error) {
+			return 0, errTaskFailed
+		}, append([]TaskOption{Use("base")}, opts...)...).
+		Do("forecast", func(ctx context.Context, v int) (int, error) { return v, nil }, Use("weather")).
+		Do("profile", func(ctx context.Context, v int) (int, error) { return v, nil }, Use("base"))
+}
+
+func TestWithAllowedFailures(t *testing.T) {
+	t.Parallel()
+
+	l := newBestEffortLyra()
+	result, err := l.Run(context.Background(), nil, WithAllowedFailures(2))
+	require.NoError(t, err)
+
+	failures := result.Failures()
+	require.Len(t, failures, 2)
+	require.ErrorIs(t, failures["geo"], errTaskFailed)
+	require.ErrorIs(t, failures["weather"], errTaskFailed)
+
+	value, err := result.Get("profile")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	statuses := make(map[string]TaskStatus)
+	for _, task := range l.RecentRuns()[0].Tasks {
+		statuses[task.ID] = task.Status
+	}
+	require.Equal(t, TaskFailed, statuses["geo"])
+	require.Equal(t, TaskCanceled, statuses["forecast"])
+	require.Equal(t, TaskSucceeded, statuses["profile"])
+}
+
+func TestWithAllowedFailuresExceeded(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		allowed int
+		opts    []TaskOption
+	}{
+		{name: "no failures allowed", allowed: 0},
+		{name: "too many failures", allowed: 1},
+		{name: "critical task", allowed: 2, opts: []TaskOption{WithCritical()}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newBestEffortLyra(tc.opts...).Run(context.Background(), nil, WithAllowedFailures(tc.allowed))
+			require.ErrorIs(t, err, errTaskFailed)
+		})
+	}
+}
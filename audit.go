@@ -0,0 +1,73 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// AuditRecord describes one execution of a task, including its retries.
+type AuditRecord struct {
+	RunID  string
+	TaskID string
+	Start  time.Time
+	// Duration covers every attempt of the task.
+	Duration time.Duration
+	// Inputs holds the task's inputs in parameter order, or nil if they
+	// could not be resolved. Sensitive values are replaced by Redacted.
+	Inputs []errors.InputSnapshot
+	// Output is the task result, nil if the task failed or only returns an
+	// error. A sensitive result is replaced by Redacted.
+	Output any
+	// Err is the error returned by the task, if any.
+	Err error
+}
+
+// AuditSink receives an AuditRecord for every task that runs.
+type AuditSink interface {
+	// Audit stores the record. An error fails the task, so that no output
+	// is used without being audited.
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditFunc adapts a function to the AuditSink interface.
+type AuditFunc func(ctx context.Context, record AuditRecord) error
+
+// Audit calls f.
+func (f AuditFunc) Audit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// auditTask hands the record of a finished task to the audit sink and
+// returns the task error joined with the sink's error, if any. The sink's
+// context is not canceled with the task, so canceled tasks are audited too.
+func auditTask(
+	ctx context.Context,
+	task *compiledTask,
+	state *runState,
+	start time.Time,
+	output any,
+	err error,
+) error {
+	record := AuditRecord{
+		RunID:    state.cfg.runID,
+		TaskID:   task.GetID(),
+		Start:    start,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	record.Inputs, _ = inputSnapshots(ctx, task, state.result)
+	if err == nil {
+		record.Output = output
+		if task.GetOptions().SecretOutput {
+			record.Output = Redacted
+		}
+	}
+
+	if auditErr := state.cfg.audit.Audit(context.WithoutCancel(ctx), record); auditErr != nil {
+		return stderr.Join(err, errors.Wrapf(auditErr, "audit failed"))
+	}
+	return err
+}
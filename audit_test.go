@@ -0,0 +1,85 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestRunWithAudit(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	records := make(map[string]AuditRecord)
+	sink := AuditFunc(func(ctx context.Context, r AuditRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records[r.TaskID] = r
+		return nil
+	})
+
+	l := New().
+		Do("token", func(ctx context.Context, password string) (string, error) {
+			return "tok-" + password, nil
+		}, Secret(UseRun("password")), WithSecretOutput()).
+		Do("charge", func(ctx context.Context, token string, amount int) (int, error) {
+			return amount * 2, nil
+		}, Use("token"), UseRun("amount")).
+		Do("notify", func(ctx context.Context, charged int) error {
+			return errTaskFailed
+		}, Use("charge"))
+
+	_, err := l.Run(context.Background(), map[string]any{"password": "hunter2", "amount": 5},
+		WithRunID("run-1"), WithAudit(sink))
+	require.ErrorIs(t, err, errTaskFailed)
+
+	require.Len(t, records, 3)
+
+	token := records["token"]
+	require.Equal(t, "run-1", token.RunID)
+	require.Equal(t, []errors.InputSnapshot{{Param: 2, Source: "password", Value: Redacted}}, token.Inputs)
+	require.Equal(t, Redacted, token.Output)
+
+	charge := records["charge"]
+	require.Equal(t, []errors.InputSnapshot{
+		{Param: 2, Source: "token", Value: Redacted},
+		{Param: 3, Source: "amount", Value: 5},
+	}, charge.Inputs)
+	require.Equal(t, 10, charge.Output)
+	require.NoError(t, charge.Err)
+	require.False(t, charge.Start.IsZero())
+
+	notify := records["notify"]
+	require.ErrorIs(t, notify.Err, errTaskFailed)
+	require.Nil(t, notify.Output)
+}
+
+func TestRunWithAuditFailure(t *testing.T) {
+	t.Parallel()
+
+	//nolint:err113 // test case.
+	errSink := stderr.New("audit log unavailable")
+	called := false
+
+	l := New().
+		Do("charge", func(ctx context.Context) (int, error) {
+			return 10, nil
+		}).
+		Do("notify", func(ctx context.Context, charged int) error {
+			called = true
+			return nil
+		}, Use("charge"))
+
+	_, err := l.Run(context.Background(), nil, WithAudit(AuditFunc(func(ctx context.Context, r AuditRecord) error {
+		return errSink
+	})))
+
+	require.ErrorIs(t, err, errSink)
+	require.Contains(t, err.Error(), `task "charge" failed`)
+	require.False(t, called, "unaudited outputs are not used")
+}
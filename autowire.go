@@ -0,0 +1,117 @@
+package lyra
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// AutoWire enables auto-wiring for the tasks added to the DAG afterwards:
+// parameters left without an input spec take the result of the one task
+// whose output type is exactly the parameter type, as if bound with
+// UseAuto. Only trailing parameters can be left out; bind a parameter
+// followed by explicit specs with UseAuto.
+//
+// A parameter whose type no task produces, or more than one task produces,
+// is an error returned by Run, so ambiguities are never resolved silently.
+//
+// Returns the same Lyra instance for method chaining.
+//
+// Example:
+//
+//	l := lyra.New().AutoWire()
+//	l.Do("fetchUser", fetchUser, lyra.UseRun("userID")) // returns User
+//	l.Do("sendEmail", func(ctx context.Context, u User) error { ... })
+func (l *Lyra) AutoWire() *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to enable auto-wiring")
+		return l
+	}
+	l.autoWire = true
+	return l
+}
+
+// UseAuto creates an InputSpec binding the parameter to the result of the
+// one task whose output type is exactly the parameter type. It works with
+// or without AutoWire.
+//
+// Example:
+//
+//	l.Do("sendEmail", sendEmail, lyra.UseAuto(), lyra.UseRun("template"))
+func UseAuto() internal.InputSpec {
+	return internal.InputSpec{Type: internal.AutoInputSpec}
+}
+
+// wireTasks returns a copy of tasks with every auto-wired parameter bound
+// to the task producing its type.
+func wireTasks(tasks map[string]*internal.Task) (map[string]*internal.Task, error) {
+	wired := maps.Clone(tasks)
+	var producers map[reflect.Type][]string
+	for _, taskID := range slices.Sorted(maps.Keys(tasks)) {
+		task := tasks[taskID]
+		specs, types := task.GetInputParams()
+		if !slices.ContainsFunc(specs, isAutoSpec) {
+			continue
+		}
+		if producers == nil {
+			producers = producersByType(tasks)
+		}
+
+		specs = slices.Clone(specs)
+		for i, spec := range specs {
+			if !isAutoSpec(spec) {
+				continue
+			}
+			paramType := types[i+1] // +1 to skip context
+			candidates := slices.DeleteFunc(slices.Clone(producers[paramType]), func(id string) bool {
+				return id == taskID
+			})
+			switch len(candidates) {
+			case 0:
+				return nil, errors.Wrapf(
+					errors.ErrMissingDependency,
+					"no task produces %s for parameter %d of task %q",
+					paramType,
+					i+2,
+					taskID,
+				)
+			case 1:
+				specs[i].Type = internal.TaskResultInputSpec
+				specs[i].Source = candidates[0]
+			default:
+				return nil, errors.Wrapf(
+					errors.ErrAmbiguousDependency,
+					"tasks %q all produce %s for parameter %d of task %q",
+					candidates,
+					paramType,
+					i+2,
+					taskID,
+				)
+			}
+		}
+		wired[taskID] = task.WithInputSpecs(specs)
+	}
+	return wired, nil
+}
+
+// producersByType returns the output types of tasks mapped to the sorted
+// IDs of the tasks producing them.
+func producersByType(tasks map[string]*internal.Task) map[reflect.Type][]string {
+	producers := make(map[reflect.Type][]string)
+	for _, taskID := range slices.Sorted(maps.Keys(tasks)) {
+		if typ := tasks[taskID].GetOutputParams(); typ != nil {
+			producers[typ] = append(producers[typ], taskID)
+		}
+	}
+	return producers
+}
+
+func isAutoSpec(spec internal.InputSpec) bool {
+	return spec.Type == internal.AutoInputSpec
+}
@@ -0,0 +1,115 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestAutoWire(t *testing.T) {
+	t.Parallel()
+
+	fetchUser := func(ctx context.Context, id int) (User, error) {
+		return User{ID: id, Name: "Alice"}, nil
+	}
+	greet := func(ctx context.Context, u User, greeting string) (string, error) {
+		return greeting + " " + u.Name, nil
+	}
+
+	tcs := []struct {
+		name     string
+		build    func() *Lyra
+		expected any
+		err      error
+	}{
+		{
+			name: "trailing parameter",
+			build: func() *Lyra {
+				return New().AutoWire().
+					Do("fetchUser", fetchUser, UseRun("userID")).
+					Do("greet", func(ctx context.Context, greeting string, u User) (string, error) {
+						return greet(ctx, u, greeting)
+					}, UseRun("greeting"))
+			},
+			expected: "Hello Alice",
+		},
+		{
+			name: "explicit UseAuto without auto-wiring",
+			build: func() *Lyra {
+				return New().
+					Do("greet", greet, UseAuto(), UseRun("greeting")).
+					Do("fetchUser", fetchUser, UseRun("userID"))
+			},
+			expected: "Hello Alice",
+		},
+		{
+			name: "no producer",
+			build: func() *Lyra {
+				return New().Do("greet", greet, UseAuto(), UseRun("greeting"))
+			},
+			err: errors.ErrMissingDependency,
+		},
+		{
+			name: "ambiguous producers",
+			build: func() *Lyra {
+				return New().
+					Do("fetchUser", fetchUser, UseRun("userID")).
+					Do("fetchAdmin", fetchUser, UseRun("userID")).
+					Do("greet", greet, UseAuto(), UseRun("greeting"))
+			},
+			err: errors.ErrAmbiguousDependency,
+		},
+		{
+			name: "missing specs without auto-wiring",
+			build: func() *Lyra {
+				return New().Do("greet", greet, UseRun("greeting"))
+			},
+			err: errors.ErrTaskParamCountMismatch,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := tc.build().Run(context.Background(), map[string]any{"userID": 1, "greeting": "Hello"})
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				greeting, err := result.Get("greet")
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, greeting)
+			}
+		})
+	}
+}
+
+func TestAutoWireGraph(t *testing.T) {
+	t.Parallel()
+
+	l := New().AutoWire().
+		Do("fetchUser", func(ctx context.Context) (User, error) { return User{}, nil }).
+		Do("notify", func(ctx context.Context, u User) error { return nil })
+
+	graph, err := l.Graph()
+
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 2)
+	require.Equal(t, "notify", graph.Nodes[1].ID)
+	require.Equal(t, []string{"fetchUser"}, graph.Nodes[1].Dependencies)
+}
+
+func TestAutoWireFrozen(t *testing.T) {
+	t.Parallel()
+
+	l := New()
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	_, err = l.AutoWire().Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errors.ErrDAGFrozen)
+}
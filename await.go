@@ -0,0 +1,87 @@
+package lyra
+
+import (
+	"context"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// Await blocks until the task has finished in this run and returns its
+// output, so callers can respond as soon as the part of the DAG they need
+// is done while the rest of the run continues. The output is nil for tasks
+// returning only an error and for outputs evicted by WithRetainedOutputs.
+//
+// Returns the error of the task if it failed, ErrTaskNotCompleted if it was
+// canceled or skipped, ErrTaskNotFound for unknown tasks, the error of the
+// run if it could not start, and the cause of ctx if ctx is done first.
+//
+// Example:
+//
+//	run := l.RunAsync(ctx, inputs)
+//	user, err := run.Await(ctx, "fetchUser")
+func (r *Run) Await(ctx context.Context, taskID string) (any, error) {
+	if r.state == nil {
+		return nil, r.err
+	}
+	finished, err := r.state.awaitTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+	return r.state.outcome(taskID)
+}
+
+// awaitTask returns a channel closed once the task has finished.
+func (s *runState) awaitTask(taskID string) (<-chan struct{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.statuses[taskID]
+	if !ok {
+		return nil, errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
+	}
+	if status.isFinal() {
+		finished := make(chan struct{})
+		close(finished)
+		return finished, nil
+	}
+	if s.waiters == nil {
+		s.waiters = make(map[string]chan struct{})
+	}
+	finished, ok := s.waiters[taskID]
+	if !ok {
+		finished = make(chan struct{})
+		s.waiters[taskID] = finished
+	}
+	return finished, nil
+}
+
+// notifyFinished wakes the callers awaiting the task. Callers must hold
+// s.mu.
+func (s *runState) notifyFinished(taskID string) {
+	if finished, ok := s.waiters[taskID]; ok {
+		close(finished)
+		delete(s.waiters, taskID)
+	}
+}
+
+// outcome returns the output or error of a finished task.
+func (s *runState) outcome(taskID string) (any, error) {
+	s.mu.Lock()
+	status, err := s.statuses[taskID], s.failures[taskID]
+	s.mu.Unlock()
+
+	switch status {
+	case TaskSucceeded:
+		output, _ := s.result.Get(taskID) // missing for tasks without output
+		return output, nil
+	case TaskFailed:
+		return nil, err
+	default:
+		return nil, errors.Wrapf(errors.ErrTaskNotCompleted, "task %q is %s", taskID, status)
+	}
+}
@@ -0,0 +1,76 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestRunAwait(t *testing.T) {
+	t.Parallel()
+
+	errFailed := stderr.New("failed") //nolint:err113 // test case.
+	release := make(chan struct{})
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (string, error) { return "Alice", nil }).
+		Do("slow", func(ctx context.Context) (int, error) {
+			<-release
+			return 1, nil
+		}).
+		Do("broken", func(ctx context.Context, _ string) error { return errFailed }, Use("fetchUser")).
+		Do("double", func(ctx context.Context, n int) (int, error) { return 2 * n, nil }, Use("slow")).
+		Do("after", func(ctx context.Context, _ int) error { return nil }, Use("double"))
+
+	run := l.RunAsync(context.Background(), nil)
+
+	// fetchUser is awaited while slow still holds the run.
+	user, err := run.Await(context.Background(), "fetchUser")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = run.Await(ctx, "slow")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = run.Await(context.Background(), "unknown")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+
+	close(release)
+	_, err = run.Await(context.Background(), "broken")
+	require.ErrorIs(t, err, errFailed)
+
+	_, err = run.Await(context.Background(), "after")
+	require.ErrorIs(t, err, errors.ErrTaskNotCompleted)
+
+	_, err = run.Wait()
+	require.ErrorIs(t, err, errFailed)
+}
+
+func TestRunAwaitWithoutOutput(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("notify", func(ctx context.Context) error { return nil })
+
+	run := l.RunAsync(context.Background(), nil)
+	value, err := run.Await(context.Background(), "notify")
+
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestRunAwaitInvalidRun(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("greet", func(ctx context.Context, name string) error { return nil }, UseRun("name"))
+
+	run := l.RunAsync(context.Background(), nil)
+	_, err := run.Await(context.Background(), "greet")
+
+	require.ErrorIs(t, err, errors.ErrMissingRunInput)
+}
@@ -0,0 +1,118 @@
+package lyra
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Backoff decides how long a failed task waits before it is attempted
+// again (see WithRetry and WithBackoff).
+//
+// Implementations must be safe for concurrent use.
+type Backoff interface {
+	// Next returns the delay after the given failed attempt, starting
+	// at 1.
+	Next(attempt int) time.Duration
+}
+
+// BackoffFunc is an adapter to use an ordinary function as a Backoff.
+type BackoffFunc func(attempt int) time.Duration
+
+// Next returns f(attempt).
+func (f BackoffFunc) Next(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// WithBackoff waits between the attempts of the task for the delays of b,
+// instead of attempting it again right away. Waiting counts against the
+// task's context, so run timeouts and cancellation still apply.
+//
+// Example:
+//
+//	l.Do("fetchUser", fetchUser, lyra.UseRun("userID"),
+//		lyra.WithRetry(5),
+//		lyra.WithBackoff(lyra.ExponentialBackoff(100*time.Millisecond, 5*time.Second)))
+func WithBackoff(b Backoff) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Backoff = nil
+		if b != nil {
+			o.Backoff = b.Next
+		}
+	})
+}
+
+// ConstantBackoff waits d between attempts.
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration { return d })
+}
+
+// ExponentialBackoff doubles the delay after every failed attempt, starting
+// at base and capped at maxDelay; a non-positive maxDelay means no cap. Each
+// delay is randomized between half and all of its value, so tasks failing
+// together do not retry in lockstep.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && !capped(d, maxDelay); i++ {
+			d *= 2
+		}
+		d = limit(d, maxDelay)
+		if d <= 1 {
+			return d
+		}
+		half := d / 2
+		return half + rand.N(d-half+1) //nolint:gosec // jitter does not need a secure source.
+	})
+}
+
+// FibonacciBackoff grows the delay after every failed attempt along the
+// Fibonacci sequence, base, base, 2*base, 3*base, 5*base and so on, capped
+// at maxDelay; a non-positive maxDelay means no cap. It grows slower than
+// ExponentialBackoff.
+func FibonacciBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(attempt int) time.Duration {
+		prev, d := time.Duration(0), base
+		for i := 1; i < attempt && !capped(d, maxDelay); i++ {
+			prev, d = d, prev+d
+		}
+		return limit(d, maxDelay)
+	})
+}
+
+// capped reports whether d reached maxDelay, or grew so much that it could
+// overflow if it kept growing.
+func capped(d, maxDelay time.Duration) bool {
+	return (maxDelay > 0 && d >= maxDelay) || d > time.Duration(1<<62)
+}
+
+// limit returns d capped at maxDelay, unless maxDelay is not positive.
+func limit(d, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+// waitBackoff waits the backoff delay of the task after the failed attempt.
+// It returns false if ctx is done first.
+func waitBackoff(ctx context.Context, task *compiledTask, attempt int) bool {
+	backoff := task.GetOptions().Backoff
+	if backoff == nil {
+		return true
+	}
+	delay := backoff(attempt)
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package lyra
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestBackoffDelays(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{
+			name:    "constant",
+			backoff: ConstantBackoff(time.Second),
+			want:    []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:    "fibonacci",
+			backoff: FibonacciBackoff(time.Second, 0),
+			want:    []time.Duration{1 * time.Second, 1 * time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second},
+		},
+		{
+			name:    "capped fibonacci",
+			backoff: FibonacciBackoff(time.Second, 4*time.Second),
+			want:    []time.Duration{1 * time.Second, 1 * time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i, want := range tc.want {
+				require.Equal(t, want, tc.backoff.Next(i+1), "attempt %d", i+1)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+	for attempt, ceiling := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  8 * time.Second,
+		5:  10 * time.Second,
+		70: 10 * time.Second,
+	} {
+		for range 20 {
+			d := backoff.Next(attempt)
+			require.GreaterOrEqual(t, d, ceiling/2, "attempt %d", attempt)
+			require.LessOrEqual(t, d, ceiling, "attempt %d", attempt)
+		}
+	}
+
+	uncapped := ExponentialBackoff(time.Second, 0)
+	require.Positive(t, uncapped.Next(200))
+}
+
+func TestRunWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	var delays []int
+	var calls atomic.Int32
+	l := New().Do("flaky", func(ctx context.Context) error {
+		if calls.Add(1) < 3 {
+			return errTaskFailed
+		}
+		return nil
+	}, WithRetry(2), WithBackoff(BackoffFunc(func(attempt int) time.Duration {
+		delays = append(delays, attempt)
+		return time.Millisecond
+	})))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, delays)
+}
+
+func TestRunWithBackoffCanceled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	l := New().Do("broken", func(ctx context.Context) error {
+		calls.Add(1)
+		return errTaskFailed
+	}, WithRetry(2), WithBackoff(ConstantBackoff(time.Hour)))
+
+	_, err := l.Run(context.Background(), nil, WithRunTimeout(20*time.Millisecond))
+
+	require.ErrorIs(t, err, errors.ErrRunTimeout)
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,244 @@
+// Package benchmark runs a DAG repeatedly and reports its throughput,
+// latency percentiles and allocations, so that scheduling changes and
+// variants of a DAG can be compared on real DAG shapes.
+//
+// Synthetic builds a DAG with the shape of another one whose tasks only
+// wait for an injected latency, to measure the overhead of the executor
+// apart from the work of the tasks.
+//
+// Example:
+//
+//	graph, _ := l.Graph()
+//	synthetic, _ := benchmark.Synthetic(graph, benchmark.ConstantLatency(time.Millisecond))
+//	report, err := benchmark.Run(ctx, synthetic, 1000, benchmark.WithParallelism(8))
+//	fmt.Println(report)
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// Option configures Run.
+type Option func(*config)
+
+type config struct {
+	parallelism int
+	inputs      map[string]any
+	runOpts     []lyra.RunOption
+}
+
+// WithParallelism sets how many runs execute at the same time. The default
+// is 1, running one after the other.
+func WithParallelism(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.parallelism = n
+		}
+	}
+}
+
+// WithInputs sets the runtime inputs of every run.
+func WithInputs(inputs map[string]any) Option {
+	return func(c *config) {
+		c.inputs = inputs
+	}
+}
+
+// WithRunOptions sets the options every run is started with.
+func WithRunOptions(opts ...lyra.RunOption) Option {
+	return func(c *config) {
+		c.runOpts = opts
+	}
+}
+
+// Report holds the measurements of Run.
+type Report struct {
+	// Runs is the number of runs executed, and Failures how many of them
+	// returned an error.
+	Runs     int
+	Failures int
+	// Duration is the wall time of all the runs.
+	Duration time.Duration
+	// Throughput is the number of runs completed per second.
+	Throughput float64
+	// P50, P99 and Max are percentiles of the latency of a run.
+	P50 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// AllocsPerRun and BytesPerRun are the heap allocations of the process
+	// during the benchmark divided by the number of runs.
+	AllocsPerRun uint64
+	BytesPerRun  uint64
+}
+
+// String returns the report on one line.
+func (r Report) String() string {
+	return fmt.Sprintf(
+		"%d runs (%d failed) in %s: %.1f runs/s, p50 %s, p99 %s, max %s, %d allocs/run, %d B/run",
+		r.Runs,
+		r.Failures,
+		r.Duration,
+		r.Throughput,
+		r.P50,
+		r.P99,
+		r.Max,
+		r.AllocsPerRun,
+		r.BytesPerRun,
+	)
+}
+
+// Run executes l n times and reports the measurements. Failed runs are
+// counted, not reported as errors; Run returns an error only when ctx is
+// done before every run executed, along with the measurements so far.
+func Run(ctx context.Context, l *lyra.Lyra, n int, opts ...Option) (Report, error) {
+	cfg := config{parallelism: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, n)
+	failures := 0
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	next := make(chan struct{})
+	for range min(cfg.parallelism, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range next {
+				runStart := time.Now()
+				_, err := l.Run(ctx, cfg.inputs, cfg.runOpts...)
+				latency := time.Since(runStart)
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				if err != nil {
+					failures++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for range n {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- struct{}{}
+	}
+	close(next)
+	wg.Wait()
+
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+	report := newReport(latencies, failures, duration, &before, &after)
+	if err := ctx.Err(); err != nil {
+		return report, fmt.Errorf("benchmark interrupted after %d runs: %w", report.Runs, err)
+	}
+	return report, nil
+}
+
+// newReport computes the report of the runs that took latencies.
+func newReport(
+	latencies []time.Duration,
+	failures int,
+	duration time.Duration,
+	before, after *runtime.MemStats,
+) Report {
+	report := Report{Runs: len(latencies), Failures: failures, Duration: duration}
+	if report.Runs == 0 {
+		return report
+	}
+	slices.Sort(latencies)
+	report.Throughput = float64(report.Runs) / duration.Seconds()
+	report.P50 = percentile(latencies, 50)
+	report.P99 = percentile(latencies, 99)
+	report.Max = latencies[len(latencies)-1]
+	report.AllocsPerRun = (after.Mallocs - before.Mallocs) / uint64(report.Runs)
+	report.BytesPerRun = (after.TotalAlloc - before.TotalAlloc) / uint64(report.Runs)
+	return report
+}
+
+// percentile returns the p-th percentile of the sorted latencies, using
+// the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank, 1)-1]
+}
+
+// Latency returns how long a synthetic task waits before returning.
+type Latency func(taskID string) time.Duration
+
+// ConstantLatency returns a Latency of d for every task.
+func ConstantLatency(d time.Duration) Latency {
+	return func(string) time.Duration { return d }
+}
+
+// token is the result passed between synthetic tasks.
+type token struct{}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	tokenType   = reflect.TypeOf(token{})
+)
+
+// Synthetic returns a DAG with the tasks and dependencies of graph, whose
+// tasks wait for the latency of their task ID, or until their context is
+// done, and pass an empty result on. A nil latency returns right away.
+func Synthetic(graph lyra.Graph, latency Latency) (*lyra.Lyra, error) {
+	l := lyra.New()
+	for _, node := range graph.Nodes {
+		var wait time.Duration
+		if latency != nil {
+			wait = latency(node.ID)
+		}
+		specs := make([]lyra.TaskOption, len(node.Dependencies))
+		params := []reflect.Type{contextType}
+		for i, dep := range node.Dependencies {
+			specs[i] = lyra.Use(dep)
+			params = append(params, tokenType)
+		}
+		l.Do(node.ID, syntheticTask(params, wait), specs...)
+	}
+	if _, err := l.Graph(); err != nil {
+		return nil, fmt.Errorf("synthetic graph: %w", err)
+	}
+	return l, nil
+}
+
+// syntheticTask returns a function with the params waiting for wait.
+func syntheticTask(params []reflect.Type, wait time.Duration) any {
+	fnType := reflect.FuncOf(params, []reflect.Type{tokenType, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		err := sleep(args[0].Interface().(context.Context), wait) //nolint:forcetypeassert // always a context.
+		return []reflect.Value{reflect.ValueOf(token{}), reflect.ValueOf(&err).Elem()}
+	}).Interface()
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
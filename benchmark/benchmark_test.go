@@ -0,0 +1,119 @@
+package benchmark
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+func newGraph(t *testing.T) lyra.Graph {
+	t.Helper()
+
+	graph, err := lyra.New().
+		Do("fetchUser", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetchOrders", func(ctx context.Context) (int, error) { return 2, nil }).
+		Do("report", func(ctx context.Context, _, _ int) error {
+			return nil
+		}, lyra.Use("fetchUser"), lyra.Use("fetchOrders")).
+		Graph()
+	require.NoError(t, err)
+	return graph
+}
+
+func TestSynthetic(t *testing.T) {
+	t.Parallel()
+
+	graph := newGraph(t)
+	l, err := Synthetic(graph, func(taskID string) time.Duration {
+		if taskID == "report" {
+			return 5 * time.Millisecond
+		}
+		return 0
+	})
+	require.NoError(t, err)
+
+	syntheticGraph, err := l.Graph()
+	require.NoError(t, err)
+	require.Len(t, syntheticGraph.Nodes, len(graph.Nodes))
+	for i, node := range syntheticGraph.Nodes {
+		require.Equal(t, graph.Nodes[i].ID, node.ID)
+		require.Equal(t, graph.Nodes[i].Stage, node.Stage)
+		require.Equal(t, graph.Nodes[i].Dependencies, node.Dependencies)
+	}
+
+	start := time.Now()
+	_, err = l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+}
+
+func TestSyntheticInvalidGraph(t *testing.T) {
+	t.Parallel()
+
+	_, err := Synthetic(lyra.Graph{Nodes: []lyra.GraphNode{
+		{ID: "report", Dependencies: []string{"missing"}},
+	}}, nil)
+	require.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	l, err := Synthetic(newGraph(t), ConstantLatency(time.Millisecond))
+	require.NoError(t, err)
+
+	report, err := Run(context.Background(), l, 20, WithParallelism(4))
+	require.NoError(t, err)
+	require.Equal(t, 20, report.Runs)
+	require.Zero(t, report.Failures)
+	require.Positive(t, report.Throughput)
+	require.GreaterOrEqual(t, report.P50, time.Millisecond)
+	require.LessOrEqual(t, report.P50, report.P99)
+	require.LessOrEqual(t, report.P99, report.Max)
+	require.Positive(t, report.AllocsPerRun)
+	require.Contains(t, report.String(), "20 runs (0 failed)")
+}
+
+func TestRunFailures(t *testing.T) {
+	t.Parallel()
+
+	l := lyra.New().Do("fail", func(ctx context.Context, fail bool) error {
+		if fail {
+			return context.Canceled
+		}
+		return nil
+	}, lyra.UseRun("fail"))
+
+	report, err := Run(context.Background(), l, 3, WithInputs(map[string]any{"fail": true}))
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Failures)
+}
+
+func TestRunCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l, err := Synthetic(newGraph(t), nil)
+	require.NoError(t, err)
+	report, err := Run(ctx, l, 10)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Zero(t, report.Runs)
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	require.Equal(t, time.Duration(50), percentile(sorted, 50))
+	require.Equal(t, time.Duration(99), percentile(sorted, 99))
+	require.Equal(t, time.Duration(1), percentile(sorted[:1], 99))
+}
@@ -0,0 +1,119 @@
+package lyra
+
+import (
+	"context"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithBulkhead limits the executions of the task running at the same time
+// across every concurrent run of the DAG in the process to n, so a burst
+// of runs cannot flood the resource the task depends on, such as a
+// database. A non-positive n means no limit, which is the default.
+//
+// Waiting for a slot counts against the task's context, so run timeouts and
+// cancellation still apply. A task that cannot get a slot fails. Use
+// LimitTag to share a limit between several tasks.
+//
+// Example:
+//
+//	l.Do("report", buildReport, lyra.UseRun("accountID"), lyra.WithBulkhead(4))
+func WithBulkhead(n int) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Bulkhead = n
+	})
+}
+
+// LimitTag limits the tasks tagged with tag (see WithTag) running at the
+// same time across every concurrent run of the DAG in the process to n, as
+// WithBulkhead does for a single task. A non-positive n removes the limit.
+//
+// Returns the same Lyra instance for method chaining.
+//
+// Example:
+//
+//	l := lyra.New().LimitTag("db:reporting", 8)
+func (l *Lyra) LimitTag(tag string, n int) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to limit tag %q", tag)
+		return l
+	}
+	if n <= 0 {
+		delete(l.tagLimits, tag)
+		return l
+	}
+	if l.tagLimits == nil {
+		l.tagLimits = make(map[string]int)
+	}
+	l.tagLimits[tag] = n
+	return l
+}
+
+// bulkheadSlots returns the semaphores every task must hold while it runs:
+// those of its limited tags, in tag order, followed by its own. Runs of the
+// same snapshot share them.
+func bulkheadSlots(tasks map[string]*internal.Task, tagLimits map[string]int) map[string][]chan struct{} {
+	tagSlots := make(map[string]chan struct{}, len(tagLimits))
+	for tag, n := range tagLimits {
+		tagSlots[tag] = make(chan struct{}, n)
+	}
+
+	slots := make(map[string][]chan struct{})
+	for taskID, task := range tasks {
+		opts := task.GetOptions()
+		tags := slices.Clone(opts.Tags)
+		slices.Sort(tags)
+		for _, tag := range slices.Compact(tags) {
+			if slot, ok := tagSlots[tag]; ok {
+				slots[taskID] = append(slots[taskID], slot)
+			}
+		}
+		if opts.Bulkhead > 0 {
+			slots[taskID] = append(slots[taskID], make(chan struct{}, opts.Bulkhead))
+		}
+	}
+	return slots
+}
+
+// enterBulkheads acquires the bulkhead slots of the task and returns the
+// function releasing them.
+func enterBulkheads(ctx context.Context, taskID string, state *runState) (func(), error) {
+	slots := state.bulkheads[taskID]
+	release := func(held int) {
+		for _, slot := range slices.Backward(slots[:held]) {
+			<-slot
+		}
+	}
+	for i, slot := range slots {
+		select {
+		case slot <- struct{}{}:
+		case <-ctx.Done():
+			release(i)
+			return nil, errors.Wrapf(context.Cause(ctx), "failed to enter bulkhead")
+		}
+	}
+	return func() { release(len(slots)) }, nil
+}
+
+// acquireTask enters the bulkheads of the task, then acquires its exclusive
+// lock, and returns the function releasing both.
+func acquireTask(ctx context.Context, task *compiledTask, state *runState) (func(), error) {
+	leave, err := enterBulkheads(ctx, task.GetID(), state)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockTask(ctx, task, state)
+	if err != nil {
+		leave()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		leave()
+	}, nil
+}
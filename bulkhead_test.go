@@ -0,0 +1,123 @@
+package lyra
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// concurrencyProbe records the largest number of callers inside it at once.
+type concurrencyProbe struct {
+	active atomic.Int32
+	peak   atomic.Int32
+}
+
+func (p *concurrencyProbe) enter() {
+	n := p.active.Add(1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	p.active.Add(-1)
+}
+
+func TestBulkheadAcrossRuns(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name  string
+		build func(probe *concurrencyProbe) *Lyra
+		want  int32
+	}{
+		{
+			name: "task bulkhead",
+			build: func(probe *concurrencyProbe) *Lyra {
+				return New().Do("query", func(ctx context.Context) error {
+					probe.enter()
+					return nil
+				}, WithBulkhead(2))
+			},
+			want: 2,
+		},
+		{
+			name: "tag limit shared by tasks",
+			build: func(probe *concurrencyProbe) *Lyra {
+				query := func(ctx context.Context) error {
+					probe.enter()
+					return nil
+				}
+				return New().
+					LimitTag("db", 1).
+					Do("users", query, WithTag("db")).
+					Do("orders", query, WithTag("db", "db"))
+			},
+			want: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			probe := &concurrencyProbe{}
+			l := tc.build(probe)
+
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := l.Run(context.Background(), nil)
+					require.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, tc.want, probe.peak.Load())
+		})
+	}
+}
+
+func TestBulkheadWaitCanceled(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	l := New().Do("slow", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithBulkhead(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := l.RunAsync(ctx, nil)
+	<-started
+
+	_, err := l.Run(context.Background(), nil, WithRunTimeout(20*time.Millisecond))
+	require.ErrorIs(t, err, errors.ErrRunTimeout)
+
+	cancel()
+	_, err = first.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLimitTagFrozen(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("task", func(ctx context.Context) error { return nil })
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	l.LimitTag("db", 1)
+	_, err = l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrDAGFrozen)
+}
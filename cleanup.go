@@ -0,0 +1,65 @@
+package lyra
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithCleanupTimeout gives the task up to d, once its context is canceled,
+// to flush or roll back what it was doing instead of dying mid-write. The
+// task gets the grace period by doing its cleanup with the context returned
+// by CleanupContext, which outlives the task's context by d. The run still
+// waits for the task to return.
+//
+// Example:
+//
+//	l.Do("export", func(ctx context.Context, rows []Row) error {
+//		tx := db.Begin()
+//		if err := write(ctx, tx, rows); err != nil {
+//			cleanupCtx, cancel := lyra.CleanupContext(ctx)
+//			defer cancel()
+//			return errors.Join(err, tx.Rollback(cleanupCtx))
+//		}
+//		return tx.Commit()
+//	}, lyra.Use("rows"), lyra.WithCleanupTimeout(5*time.Second))
+func WithCleanupTimeout(d time.Duration) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.CleanupTimeout = d
+	})
+}
+
+// CleanupContext returns a context for cleaning up after ctx is canceled:
+// it keeps the values of ctx but is canceled only once the cleanup timeout
+// of the task that owns ctx (see WithCleanupTimeout) has passed since ctx
+// was canceled, or when cancel is called.
+//
+// Without a cleanup timeout, for example when ctx was not passed to a task
+// by Lyra, the returned context is canceled together with ctx.
+func CleanupContext(ctx context.Context) (cleanupCtx context.Context, cancel context.CancelFunc) {
+	grace, ok := ctx.Value(cleanupKey).(time.Duration)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	cleanupCtx, cancelCleanup := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.AfterFunc(grace, cancelCleanup)
+		context.AfterFunc(cleanupCtx, func() { timer.Stop() })
+	})
+	return cleanupCtx, func() {
+		stop()
+		cancelCleanup()
+	}
+}
+
+// contextWithCleanup records the cleanup timeout of the task, if it has one,
+// for CleanupContext.
+func contextWithCleanup(ctx context.Context, task *compiledTask) context.Context {
+	grace := task.GetOptions().CleanupTimeout
+	if grace <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, cleanupKey, grace)
+}
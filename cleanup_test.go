@@ -0,0 +1,62 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCleanupTimeout(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	l := New().Do("export", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+
+		cleanupCtx, cancel := CleanupContext(ctx)
+		defer cancel()
+		assert.NoError(t, cleanupCtx.Err())
+		taskID, _ := TaskIDFromContext(cleanupCtx)
+		assert.Equal(t, "export", taskID)
+
+		select {
+		case <-cleanupCtx.Done():
+		case <-time.After(time.Second):
+			assert.Fail(t, "cleanup context outlived its timeout")
+		}
+		return ctx.Err()
+	}, WithCleanupTimeout(20*time.Millisecond))
+
+	run := l.RunAsync(context.Background(), nil)
+	<-started
+	run.Cancel()
+
+	_, err := run.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCleanupContextWithoutTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cleanupCtx, cancelCleanup := CleanupContext(ctx)
+	defer cancelCleanup()
+	require.ErrorIs(t, cleanupCtx.Err(), context.Canceled)
+}
+
+func TestCleanupContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), cleanupKey, time.Hour)
+	cleanupCtx, cancel := CleanupContext(ctx)
+	require.NoError(t, cleanupCtx.Err())
+
+	cancel()
+	require.ErrorIs(t, cleanupCtx.Err(), context.Canceled)
+}
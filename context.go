@@ -0,0 +1,81 @@
+package lyra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type contextKey int
+
+const (
+	runIDKey contextKey = iota
+	taskKey
+	reporterKey
+	cleanupKey
+)
+
+// taskInfo identifies the task execution that owns a context.
+type taskInfo struct {
+	id      string
+	attempt int
+}
+
+// runIDBytes is the number of random bytes used for generated run IDs.
+const runIDBytes = 16
+
+// RunIDFromContext returns the ID of the run executing the task that owns ctx.
+//
+// Every call to Lyra.Run generates a unique run ID (or uses the one supplied
+// with WithRunID) and injects it into the context passed to each task, so task
+// code can correlate logs and metrics with a specific execution.
+//
+// Returns false if ctx was not created by a Lyra run.
+//
+// Example:
+//
+//	func fetchUser(ctx context.Context, userID int) (User, error) {
+//		runID, _ := lyra.RunIDFromContext(ctx)
+//		log.Printf("run=%s fetching user %d", runID, userID)
+//		...
+//	}
+func RunIDFromContext(ctx context.Context) (string, bool) {
+	runID, ok := ctx.Value(runIDKey).(string)
+	return runID, ok
+}
+
+func contextWithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey, runID)
+}
+
+// TaskIDFromContext returns the ID of the task that owns ctx.
+//
+// Lyra injects the executing task's ID into the context passed to the task
+// function, so shared helper code can label logs and metrics without taking
+// the task ID as an extra parameter.
+//
+// Returns false if ctx was not passed to a task by Lyra.
+func TaskIDFromContext(ctx context.Context) (string, bool) {
+	info, ok := ctx.Value(taskKey).(taskInfo)
+	return info.id, ok
+}
+
+// AttemptFromContext returns the 1-based attempt number of the task execution
+// that owns ctx. The first execution of a task is attempt 1.
+//
+// Returns false if ctx was not passed to a task by Lyra.
+func AttemptFromContext(ctx context.Context) (int, bool) {
+	info, ok := ctx.Value(taskKey).(taskInfo)
+	return info.attempt, ok
+}
+
+func contextWithTask(ctx context.Context, taskID string, attempt int) context.Context {
+	return context.WithValue(ctx, taskKey, taskInfo{id: taskID, attempt: attempt})
+}
+
+// newRunID returns a random 128-bit identifier encoded as hex.
+func NewRunID() string {
+	b := make([]byte, runIDBytes)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error.
+	return hex.EncodeToString(b)
+}
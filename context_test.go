@@ -0,0 +1,165 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+		found    bool
+	}{
+		{
+			name: "no run id",
+			ctx:  context.Background(),
+		},
+		{
+			name:     "run id present",
+			ctx:      contextWithRunID(context.Background(), "run-1"),
+			expected: "run-1",
+			found:    true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			runID, ok := RunIDFromContext(tc.ctx)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.expected, runID)
+		})
+	}
+}
+
+func TestNewRunIDUnique(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]struct{})
+	for range 100 {
+		id := NewRunID()
+		require.Len(t, id, 2*runIDBytes)
+		require.NotContains(t, seen, id)
+		seen[id] = struct{}{}
+	}
+}
+
+func TestRunInjectsRunID(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("first", func(ctx context.Context) (string, error) {
+			runID, _ := RunIDFromContext(ctx)
+			return runID, nil
+		}).
+		Do("second", func(ctx context.Context) (string, error) {
+			runID, _ := RunIDFromContext(ctx)
+			return runID, nil
+		})
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	first, err := result.Get("first")
+	require.NoError(t, err)
+	second, err := result.Get("second")
+	require.NoError(t, err)
+	require.NotEmpty(t, first)
+	require.Equal(t, first, second, "all tasks in a run share the run id")
+
+	other, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	otherFirst, err := other.Get("first")
+	require.NoError(t, err)
+	require.NotEqual(t, first, otherFirst, "each run gets a new run id")
+}
+
+func TestRunWithRunID(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("echo", func(ctx context.Context) (string, error) {
+			runID, _ := RunIDFromContext(ctx)
+			return runID, nil
+		})
+
+	result, err := l.Run(context.Background(), nil, WithRunID("req-42"))
+	require.NoError(t, err)
+	runID, err := result.Get("echo")
+	require.NoError(t, err)
+	require.Equal(t, "req-42", runID)
+
+	_, err = New().
+		Do("fail", func(ctx context.Context) error {
+			return errTaskFailed
+		}).
+		Run(context.Background(), nil, WithRunID("req-43"))
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Contains(t, err.Error(), "req-43")
+}
+
+func TestTaskIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name            string
+		ctx             context.Context
+		expectedID      string
+		expectedAttempt int
+		found           bool
+	}{
+		{
+			name: "no task",
+			ctx:  context.Background(),
+		},
+		{
+			name:            "task present",
+			ctx:             contextWithTask(context.Background(), "fetchUser", 2),
+			expectedID:      "fetchUser",
+			expectedAttempt: 2,
+			found:           true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			taskID, ok := TaskIDFromContext(tc.ctx)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.expectedID, taskID)
+
+			attempt, ok := AttemptFromContext(tc.ctx)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.expectedAttempt, attempt)
+		})
+	}
+}
+
+func TestRunInjectsTaskID(t *testing.T) {
+	t.Parallel()
+
+	label := func(ctx context.Context) (string, error) {
+		taskID, _ := TaskIDFromContext(ctx)
+		attempt, _ := AttemptFromContext(ctx)
+		return fmt.Sprintf("%s#%d", taskID, attempt), nil
+	}
+
+	l := New().
+		Do("first", label).
+		Do("second", label).
+		Do("third", func(ctx context.Context, _ string) (string, error) {
+			return label(ctx)
+		}, Use("first"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	for _, taskID := range []string{"first", "second", "third"} {
+		got, err := result.Get(taskID)
+		require.NoError(t, err)
+		require.Equal(t, taskID+"#1", got)
+	}
+}
@@ -0,0 +1,92 @@
+package lyra
+
+import "reflect"
+
+// deepCopy returns a copy of v sharing no pointers, slices or maps with it.
+// Unexported struct fields, channels and functions are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	c := copier{seen: make(map[copyKey]reflect.Value)}
+	return c.copy(v)
+}
+
+// copyKey identifies a pointer or map already copied, so shared and cyclic
+// references are preserved in the copy.
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type copier struct {
+	seen map[copyKey]reflect.Value
+}
+
+func (c copier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{ptr: v.Pointer(), typ: v.Type()}
+		if dup, ok := c.seen[key]; ok {
+			return dup
+		}
+		dup := reflect.New(v.Type().Elem())
+		c.seen[key] = dup
+		dup.Elem().Set(c.copy(v.Elem()))
+		return dup
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{ptr: v.Pointer(), typ: v.Type()}
+		if dup, ok := c.seen[key]; ok {
+			return dup
+		}
+		dup := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.seen[key] = dup
+		iter := v.MapRange()
+		for iter.Next() {
+			dup.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return dup
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		dup := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			dup.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return dup
+
+	case reflect.Array:
+		dup := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			dup.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return dup
+
+	case reflect.Struct:
+		dup := reflect.New(v.Type()).Elem()
+		dup.Set(v)
+		for i := range v.NumField() {
+			if field := dup.Field(i); field.CanSet() {
+				field.Set(c.copy(v.Field(i)))
+			}
+		}
+		return dup
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		dup := reflect.New(v.Type()).Elem()
+		dup.Set(c.copy(v.Elem()))
+		return dup
+
+	default:
+		return v
+	}
+}
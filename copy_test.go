@@ -0,0 +1,67 @@
+package lyra
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type node struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]any
+	Next     *node
+	internal []int
+}
+
+func TestDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	cyclic := &node{Name: "a"}
+	cyclic.Next = cyclic
+
+	tcs := []struct {
+		name  string
+		value any
+	}{
+		{name: "nil slice", value: []int(nil)},
+		{name: "slice", value: []int{1, 2, 3}},
+		{name: "array", value: [2][]int{{1}, {2}}},
+		{name: "nested map", value: map[string]any{"ids": []int{1, 2}, "nil": nil}},
+		{name: "struct", value: node{Name: "a", Tags: []string{"x"}, Attrs: map[string]any{"k": 1}, Next: &node{Name: "b"}}},
+		{name: "cyclic pointer", value: cyclic},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dup := deepCopy(reflect.ValueOf(tc.value)).Interface()
+
+			require.Equal(t, tc.value, dup)
+		})
+	}
+}
+
+func TestDeepCopyIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	original := &node{
+		Tags:     []string{"x"},
+		Attrs:    map[string]any{"ids": []int{1}},
+		internal: []int{1},
+	}
+	original.Next = original
+
+	dup, _ := deepCopy(reflect.ValueOf(original)).Interface().(*node)
+	dup.Tags[0] = "y"
+	dup.Attrs["ids"].([]int)[0] = 2 //nolint:forcetypeassert // test case.
+	dup.internal[0] = 2
+
+	require.NotSame(t, original, dup)
+	require.Same(t, dup, dup.Next)
+	require.Equal(t, []string{"x"}, original.Tags)
+	require.Equal(t, []int{1}, original.Attrs["ids"])
+	require.Equal(t, []int{2}, original.internal, "unexported fields are shared")
+}
@@ -0,0 +1,191 @@
+package lyra
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// DAGDiff describes how a DAG differs from another one, for example to
+// report in CI how a change alters the topology of a pipeline.
+type DAGDiff struct {
+	// Added and Removed hold the sorted IDs of the tasks only present in
+	// the new and old DAG respectively.
+	Added   []string
+	Removed []string
+	// Changed holds the tasks present in both DAGs that differ, sorted by
+	// task ID.
+	Changed []TaskDiff
+}
+
+// TaskDiff describes how a task differs between two DAGs.
+type TaskDiff struct {
+	TaskID string
+	// AddedDeps and RemovedDeps hold the sorted IDs of the tasks the task
+	// started and stopped reading.
+	AddedDeps   []string
+	RemovedDeps []string
+	// Before and After hold the input specs of the task, formatted like the
+	// calls creating them, when they changed.
+	Before []string
+	After  []string
+	// BeforeSignature and AfterSignature hold the type of the task function
+	// when it changed.
+	BeforeSignature string
+	AfterSignature  string
+}
+
+// Diff compares the DAG built by b against the one built by a. Task options
+// are not compared.
+//
+// Example:
+//
+//	if diff := lyra.Diff(mainPipeline(), prPipeline()); !diff.Empty() {
+//		fmt.Print(diff)
+//	}
+func Diff(a, b *Lyra) DAGDiff {
+	before, after := a.taskSnapshot(), b.taskSnapshot()
+
+	diff := DAGDiff{Added: []string{}, Removed: []string{}, Changed: []TaskDiff{}}
+	for _, taskID := range slices.Sorted(maps.Keys(after)) {
+		old, ok := before[taskID]
+		if !ok {
+			diff.Added = append(diff.Added, taskID)
+			continue
+		}
+		if change, changed := diffTask(taskID, old, after[taskID], before, after); changed {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, taskID := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[taskID]; !ok {
+			diff.Removed = append(diff.Removed, taskID)
+		}
+	}
+	return diff
+}
+
+// Empty reports whether both DAGs are the same.
+func (d DAGDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String renders the diff one change per line, prefixed with + for
+// additions, - for removals and ~ for changed tasks.
+func (d DAGDiff) String() string {
+	var b strings.Builder
+	for _, taskID := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", taskID)
+	}
+	for _, taskID := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", taskID)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n", change.TaskID)
+		for _, dep := range change.AddedDeps {
+			fmt.Fprintf(&b, "    + depends on %s\n", dep)
+		}
+		for _, dep := range change.RemovedDeps {
+			fmt.Fprintf(&b, "    - depends on %s\n", dep)
+		}
+		if change.Before != nil {
+			fmt.Fprintf(&b, "    inputs: %s -> %s\n", strings.Join(change.Before, ", "), strings.Join(change.After, ", "))
+		}
+		if change.BeforeSignature != "" {
+			fmt.Fprintf(&b, "    signature: %s -> %s\n", change.BeforeSignature, change.AfterSignature)
+		}
+	}
+	return b.String()
+}
+
+// taskSnapshot returns the tasks registered so far.
+func (l *Lyra) taskSnapshot() map[string]*internal.Task {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return maps.Clone(l.tasks)
+}
+
+func diffTask(taskID string, before, after *internal.Task, beforeTasks, afterTasks map[string]*internal.Task) (TaskDiff, bool) {
+	change := TaskDiff{TaskID: taskID}
+
+	oldDeps := distinct(taskDependencies(before, beforeTasks))
+	newDeps := distinct(taskDependencies(after, afterTasks))
+	for _, dep := range newDeps {
+		if !slices.Contains(oldDeps, dep) {
+			change.AddedDeps = append(change.AddedDeps, dep)
+		}
+	}
+	for _, dep := range oldDeps {
+		if !slices.Contains(newDeps, dep) {
+			change.RemovedDeps = append(change.RemovedDeps, dep)
+		}
+	}
+	slices.Sort(change.AddedDeps)
+	slices.Sort(change.RemovedDeps)
+
+	oldSpecs, newSpecs := formatSpecs(before), formatSpecs(after)
+	if !slices.Equal(oldSpecs, newSpecs) {
+		change.Before, change.After = oldSpecs, newSpecs
+	}
+
+	oldSig := reflect.TypeOf(before.GetFunction()).String()
+	newSig := reflect.TypeOf(after.GetFunction()).String()
+	if oldSig != newSig {
+		change.BeforeSignature, change.AfterSignature = oldSig, newSig
+	}
+
+	changed := change.AddedDeps != nil || change.RemovedDeps != nil || change.Before != nil || oldSig != newSig
+	return change, changed
+}
+
+// formatSpecs formats the input specs of the task like the calls creating
+// them, for example Use("fetchUser", "Name").
+func formatSpecs(task *internal.Task) []string {
+	specs, _ := task.GetInputParams()
+	formatted := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		switch spec.Type {
+		case internal.TemplateInputSpec:
+			formatted = append(formatted, "UseTemplate("+strconv.Quote(spec.Source)+")")
+			continue
+		case internal.AutoInputSpec:
+			formatted = append(formatted, "UseAuto()")
+			continue
+		case internal.TaggedInputSpec:
+			formatted = append(formatted, "UseTags()")
+			continue
+		case internal.FieldsInputSpec:
+			fields := make([]string, 0, len(spec.Projection))
+			for _, name := range slices.Sorted(maps.Keys(spec.Projection)) {
+				fields = append(fields, strconv.Quote(name)+": "+strconv.Quote(strings.Join(spec.Projection[name], ".")))
+			}
+			formatted = append(formatted, fmt.Sprintf("UseFields(%q, {%s})", spec.Source, strings.Join(fields, ", ")))
+			continue
+		}
+		args := make([]string, 0, len(spec.Field)+1)
+		for _, arg := range append([]string{spec.Source}, spec.Field...) {
+			args = append(args, strconv.Quote(arg))
+		}
+		call := "Use"
+		if spec.Type == internal.RuntimeInputSpec {
+			call = "UseRun"
+		}
+		s := call + "(" + strings.Join(args, ", ") + ")"
+		if spec.Secret {
+			s = "Secret(" + s + ")"
+		}
+		switch {
+		case spec.Default != nil:
+			s = fmt.Sprintf("Default(%s, %#v)", s, spec.Default)
+		case spec.Optional:
+			s = "Optional(" + s + ")"
+		}
+		formatted = append(formatted, s)
+	}
+	return formatted
+}
@@ -0,0 +1,65 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	fetch := func(ctx context.Context, id int) (User, error) { return User{ID: id}, nil }
+	base := func() *Lyra {
+		return New().
+			Do("fetchUser", fetch, UseRun("userID")).
+			Do("fetchOrders", func(ctx context.Context, id int) ([]string, error) { return nil, nil }, UseRun("userID")).
+			Do("report", func(ctx context.Context, name string, orders []string) error {
+				return nil
+			}, Use("fetchUser", "Name"), Use("fetchOrders"))
+	}
+
+	require.True(t, Diff(base(), base()).Empty())
+
+	changed := New().
+		Do("fetchUser", fetch, Secret(UseRun("userID"))).
+		Do("fetchInvoices", func(ctx context.Context, id int) ([]int, error) { return nil, nil }, UseRun("userID")).
+		Do("report", func(ctx context.Context, name string, invoices []int) error {
+			return nil
+		}, Use("fetchUser", "Name"), Use("fetchInvoices"))
+
+	diff := Diff(base(), changed)
+
+	require.False(t, diff.Empty())
+	require.Equal(t, DAGDiff{
+		Added:   []string{"fetchInvoices"},
+		Removed: []string{"fetchOrders"},
+		Changed: []TaskDiff{
+			{
+				TaskID: "fetchUser",
+				Before: []string{`UseRun("userID")`},
+				After:  []string{`Secret(UseRun("userID"))`},
+			},
+			{
+				TaskID:          "report",
+				AddedDeps:       []string{"fetchInvoices"},
+				RemovedDeps:     []string{"fetchOrders"},
+				Before:          []string{`Use("fetchUser", "Name")`, `Use("fetchOrders")`},
+				After:           []string{`Use("fetchUser", "Name")`, `Use("fetchInvoices")`},
+				BeforeSignature: "func(context.Context, string, []string) error",
+				AfterSignature:  "func(context.Context, string, []int) error",
+			},
+		},
+	}, diff)
+	require.Equal(t, `+ fetchInvoices
+- fetchOrders
+~ fetchUser
+    inputs: UseRun("userID") -> Secret(UseRun("userID"))
+~ report
+    + depends on fetchInvoices
+    - depends on fetchOrders
+    inputs: Use("fetchUser", "Name"), Use("fetchOrders") -> Use("fetchUser", "Name"), Use("fetchInvoices")
+    signature: func(context.Context, string, []string) error -> func(context.Context, string, []int) error
+`, diff.String())
+}
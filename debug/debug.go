@@ -0,0 +1,179 @@
+// Package debug serves the structure of a DAG and the state of its recent
+// runs over HTTP, like expvar but for orchestration.
+//
+// Mount the handler under a prefix of a debug server:
+//
+//	mux.Handle("/debug/lyra/", http.StripPrefix("/debug/lyra", debug.Handler(l)))
+//
+// Endpoints:
+//
+//	GET /dag.json    the DAG as JSON (see lyra.Graph)
+//	GET /dag.dot     the DAG in the Graphviz DOT language
+//	GET /dag.mmd     the DAG as a Mermaid flowchart
+//	                 (query for both: reduce=true; see lyra.Graph.TransitiveReduction)
+//	GET /runs        summaries of the recent runs, most recent first
+//	                 (query: failed=true, task=<id>, limit=<n>; see lyra.HistoryQuery)
+//	GET /runs/last   per-task statuses and durations of the last run
+//	GET /running     the tasks executing now, longest running first
+//
+// The goroutines executing tasks carry the lyra.RunLabel and lyra.TaskLabel
+// profiler labels, shown by /debug/pprof/goroutine?debug=1.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// Handler returns an http.Handler exposing l.
+func Handler(l *lyra.Lyra) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dag.json", func(w http.ResponseWriter, _ *http.Request) {
+		graph, err := l.Graph()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, graph)
+	})
+	mux.HandleFunc("GET /dag.dot", func(w http.ResponseWriter, r *http.Request) {
+		graph, err := exportGraph(l, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_, _ = w.Write([]byte(graph.DOT()))
+	})
+	mux.HandleFunc("GET /dag.mmd", func(w http.ResponseWriter, r *http.Request) {
+		graph, err := exportGraph(l, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/vnd.mermaid; charset=utf-8")
+		_, _ = w.Write([]byte(graph.Mermaid()))
+	})
+	mux.HandleFunc("GET /runs", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		q := lyra.HistoryQuery{Failed: query.Get("failed") == "true", TaskID: query.Get("task")}
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			q.Limit = n
+		}
+		runs, err := l.History(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		views := make([]runView, 0, len(runs))
+		for _, run := range runs {
+			views = append(views, newRunView(run, false))
+		}
+		writeJSON(w, views)
+	})
+	mux.HandleFunc("GET /runs/last", func(w http.ResponseWriter, _ *http.Request) {
+		runs := l.RecentRuns()
+		if len(runs) == 0 {
+			http.Error(w, "no runs yet", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, newRunView(runs[0], true))
+	})
+	mux.HandleFunc("GET /running", func(w http.ResponseWriter, _ *http.Request) {
+		now := time.Now()
+		tasks := l.RunningTasks()
+		views := make([]runningView, 0, len(tasks))
+		for _, task := range tasks {
+			views = append(views, runningView{
+				RunID:      task.RunID,
+				TaskID:     task.TaskID,
+				Stage:      task.Stage,
+				Started:    task.Started,
+				RunningFor: now.Sub(task.Started).String(),
+			})
+		}
+		writeJSON(w, views)
+	})
+	return mux
+}
+
+type runningView struct {
+	RunID      string    `json:"run_id"`
+	TaskID     string    `json:"task_id"`
+	Stage      int       `json:"stage"`
+	Started    time.Time `json:"started"`
+	RunningFor string    `json:"running_for"`
+}
+
+type runView struct {
+	ID       string     `json:"id"`
+	Start    time.Time  `json:"start"`
+	Duration string     `json:"duration"`
+	Inputs   string     `json:"inputs_hash"`
+	Error    string     `json:"error,omitempty"`
+	Tasks    []taskView `json:"tasks,omitempty"`
+}
+
+type taskView struct {
+	ID       string            `json:"id"`
+	Stage    int               `json:"stage"`
+	Status   string            `json:"status"`
+	Duration string            `json:"duration"`
+	Tags     []string          `json:"tags,omitempty"`
+	Meta     map[string]string `json:"meta,omitempty"`
+}
+
+func newRunView(run lyra.RunSummary, withTasks bool) runView {
+	view := runView{
+		ID:       run.ID,
+		Start:    run.Start,
+		Duration: run.Duration.String(),
+		Inputs:   run.InputsHash,
+	}
+	if run.Err != nil {
+		view.Error = run.Err.Error()
+	}
+	if !withTasks {
+		return view
+	}
+	view.Tasks = make([]taskView, 0, len(run.Tasks))
+	for _, task := range run.Tasks {
+		view.Tasks = append(view.Tasks, taskView{
+			ID:       task.ID,
+			Stage:    task.Stage,
+			Status:   task.Status.String(),
+			Duration: task.Duration.String(),
+			Tags:     task.Tags,
+			Meta:     task.Meta,
+		})
+	}
+	return view
+}
+
+// exportGraph returns the graph of l, transitively reduced when the request
+// asks for it.
+func exportGraph(l *lyra.Lyra, r *http.Request) (lyra.Graph, error) {
+	graph, err := l.Graph()
+	if err != nil || r.URL.Query().Get("reduce") != "true" {
+		return graph, err
+	}
+	return graph.TransitiveReduction(), nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
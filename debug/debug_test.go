@@ -0,0 +1,196 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	stderr "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+var errTaskFailed = stderr.New("task failed")
+
+func newTestLyra() *lyra.Lyra {
+	return lyra.New().
+		Do("fetchUser", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}, lyra.WithDescription("fetches the user")).
+		Do("report", func(ctx context.Context, _ int) error {
+			return errTaskFailed
+		}, lyra.Use("fetchUser"))
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestHandlerDAG(t *testing.T) {
+	t.Parallel()
+
+	h := Handler(newTestLyra())
+
+	rec := get(t, h, "/dag.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var graph lyra.Graph
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &graph))
+	require.Equal(t, lyra.Graph{Nodes: []lyra.GraphNode{
+		{ID: "fetchUser", Stage: 0, Description: "fetches the user", Dependencies: []string{}},
+		{ID: "report", Stage: 1, Dependencies: []string{"fetchUser"}},
+	}}, graph)
+
+	rec = get(t, h, "/dag.dot")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"fetchUser" -> "report";`)
+
+	rec = get(t, h, "/dag.mmd")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "n0 --> n1")
+}
+
+func TestHandlerDAGReduced(t *testing.T) {
+	t.Parallel()
+
+	l := lyra.New().
+		Do("a", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("b", func(ctx context.Context, v int) (int, error) { return v, nil }, lyra.Use("a")).
+		Do("c", func(ctx context.Context, x, y int) error { return nil }, lyra.Use("a"), lyra.Use("b"))
+	h := Handler(l)
+
+	rec := get(t, h, "/dag.dot")
+	require.Contains(t, rec.Body.String(), `"a" -> "c";`)
+
+	rec = get(t, h, "/dag.dot?reduce=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), `"a" -> "c";`)
+	require.Contains(t, rec.Body.String(), `"b" -> "c";`)
+
+	rec = get(t, h, "/dag.mmd?reduce=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "n0 --> n2")
+}
+
+func TestHandlerDAGError(t *testing.T) {
+	t.Parallel()
+
+	l := lyra.New().Do("report", func(ctx context.Context, _ int) error {
+		return nil
+	}, lyra.Use("missing"))
+
+	rec := get(t, Handler(l), "/dag.json")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandlerRuns(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLyra()
+	h := Handler(l)
+
+	rec := get(t, h, "/runs/last")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	_, err := l.Run(context.Background(), nil, lyra.WithRunID("run-1"))
+	require.ErrorIs(t, err, errTaskFailed)
+
+	rec = get(t, h, "/runs")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var runs []runView
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &runs))
+	require.Len(t, runs, 1)
+	require.Equal(t, "run-1", runs[0].ID)
+	require.Contains(t, runs[0].Error, "task failed")
+	require.Empty(t, runs[0].Tasks)
+
+	rec = get(t, h, "/runs/last")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var last runView
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &last))
+	require.Len(t, last.Tasks, 2)
+	require.Equal(t, "fetchUser", last.Tasks[0].ID)
+	require.Equal(t, "succeeded", last.Tasks[0].Status)
+	require.Equal(t, "report", last.Tasks[1].ID)
+	require.Equal(t, "failed", last.Tasks[1].Status)
+}
+
+func TestHandlerRunsQuery(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLyra()
+	h := Handler(l)
+	for _, id := range []string{"run-1", "run-2"} {
+		_, err := l.Run(context.Background(), nil, lyra.WithRunID(id))
+		require.ErrorIs(t, err, errTaskFailed)
+	}
+
+	tcs := []struct {
+		query string
+		code  int
+		ids   []string
+	}{
+		{query: "", code: http.StatusOK, ids: []string{"run-2", "run-1"}},
+		{query: "?failed=true&limit=1", code: http.StatusOK, ids: []string{"run-2"}},
+		{query: "?task=report", code: http.StatusOK, ids: []string{"run-2", "run-1"}},
+		{query: "?task=missing", code: http.StatusOK, ids: []string{}},
+		{query: "?limit=many", code: http.StatusBadRequest},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			rec := get(t, h, "/runs"+tc.query)
+
+			require.Equal(t, tc.code, rec.Code)
+			if tc.code != http.StatusOK {
+				return
+			}
+			var runs []runView
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &runs))
+			ids := make([]string, 0, len(runs))
+			for _, run := range runs {
+				ids = append(ids, run.ID)
+				require.NotEmpty(t, run.Inputs)
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestHandlerRunning(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	l := lyra.New().Do("slow", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	h := Handler(l)
+
+	run := l.RunAsync(context.Background(), nil, lyra.WithRunID("run-1"))
+	<-started
+
+	rec := get(t, h, "/running")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var views []runningView
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &views))
+	require.Len(t, views, 1)
+	require.Equal(t, "run-1", views[0].RunID)
+	require.Equal(t, "slow", views[0].TaskID)
+
+	close(release)
+	_, err := run.Wait()
+	require.NoError(t, err)
+
+	rec = get(t, h, "/running")
+	require.JSONEq(t, "[]", rec.Body.String())
+}
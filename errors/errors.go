@@ -43,6 +43,9 @@ var ErrCyclicDependency = errors.New("cyclic dependency detected")
 // ErrMissingDependency is returned when referenced dependency doesn't exist.
 var ErrMissingDependency = errors.New("dependency not found")
 
+// ErrAmbiguousDependency is returned when more than one task produces the type of an auto-wired parameter.
+var ErrAmbiguousDependency = errors.New("ambiguous dependency")
+
 // ErrInvalidParamType is returned when parameter types don't match between tasks.
 var ErrInvalidParamType = errors.New("invalid parameter type received")
 
@@ -52,6 +55,48 @@ var ErrDuplicateTask = errors.New("duplicate task")
 // ErrTaskNotFound is returned when task is not found in results.
 var ErrTaskNotFound = errors.New("task not found")
 
+// ErrRunTimeout is returned when a run exceeds its overall time budget.
+var ErrRunTimeout = errors.New("run timeout exceeded")
+
+// ErrTaskTimeout is returned when an attempt of a task exceeds its time budget.
+var ErrTaskTimeout = errors.New("task timeout exceeded")
+
+// ErrTaskPanicked is returned when a task panics.
+var ErrTaskPanicked = errors.New("task panicked")
+
+// ErrQuorumNotMet is returned when too few of the sources of a task with a quorum succeeded.
+var ErrQuorumNotMet = errors.New("quorum not met")
+
+// ErrInvalidQuorum is returned when a task has a quorum it can never meet.
+var ErrInvalidQuorum = errors.New("invalid quorum")
+
+// ErrOutputTooLarge is returned when the result of a task exceeds the output size limit.
+var ErrOutputTooLarge = errors.New("task output too large")
+
+// ErrTaskAlreadyFinished is returned when an operation requires a task that has not finished yet.
+var ErrTaskAlreadyFinished = errors.New("task already finished")
+
+// ErrTaskNotCompleted is returned when the outcome of a task that was canceled or skipped is requested.
+var ErrTaskNotCompleted = errors.New("task did not complete")
+
+// ErrInputCollidesWithTask is returned when a runtime input key is also a task ID.
+var ErrInputCollidesWithTask = errors.New("runtime input key collides with task id")
+
+// ErrMissingRunInput is returned when a required runtime input is not provided to Run.
+var ErrMissingRunInput = errors.New("missing runtime input")
+
+// ErrInvalidRunInputs is returned when runtime inputs cannot be taken from the given value.
+var ErrInvalidRunInputs = errors.New("invalid runtime inputs")
+
+// ErrRetryBudgetExhausted is returned when a task could not be retried because the run's retry budget is used up.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// ErrDAGFrozen is returned when the DAG is changed after it was first run.
+var ErrDAGFrozen = errors.New("dag is frozen after the first run")
+
+// ErrShutdown is returned by runs started after Lyra.Shutdown, and is the cause of runs canceled by it.
+var ErrShutdown = errors.New("lyra is shut down")
+
 // Wrapf returns a formatted wrapped error with context.
 // If err is nil, returns a new formatted error.
 // Otherwise, wraps the error with additional context.
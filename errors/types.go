@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RunTimeoutError is returned by Lyra.Run when the run exceeds the budget set
+// with lyra.WithRunTimeout.
+//
+// It matches ErrRunTimeout and context.DeadlineExceeded with errors.Is.
+type RunTimeoutError struct {
+	// Timeout is the configured run budget.
+	Timeout time.Duration
+	// Pending lists, in sorted order, the tasks that had not completed when
+	// the budget was exhausted.
+	Pending []string
+}
+
+// Error returns the timeout and the pending task IDs.
+func (e *RunTimeoutError) Error() string {
+	return fmt.Sprintf(
+		"%s after %s, pending tasks: [%s]",
+		ErrRunTimeout,
+		e.Timeout,
+		strings.Join(e.Pending, ", "),
+	)
+}
+
+// Unwrap returns ErrRunTimeout and context.DeadlineExceeded.
+func (*RunTimeoutError) Unwrap() []error {
+	return []error{ErrRunTimeout, context.DeadlineExceeded}
+}
+
+// RunCanceledError is returned by Lyra.Run when the run's context is
+// canceled before every task completed. The tasks that had not started are
+// marked as canceled and never run.
+//
+// It matches Cause with errors.Is and errors.As.
+type RunCanceledError struct {
+	// Cause is the cause of the cancellation, as returned by
+	// context.Cause.
+	Cause error
+	// Canceled lists, in sorted order, the tasks that had not started.
+	Canceled []string
+}
+
+// Error returns the cause and the canceled task IDs.
+func (e *RunCanceledError) Error() string {
+	return fmt.Sprintf("run canceled: %s, canceled tasks: [%s]", e.Cause, strings.Join(e.Canceled, ", "))
+}
+
+// Unwrap returns Cause.
+func (e *RunCanceledError) Unwrap() error {
+	return e.Cause
+}
+
+// PanicError is the error of a task that panicked.
+//
+// It matches ErrTaskPanicked with errors.Is, and Value when it is an error.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+// Error returns the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrTaskPanicked, e.Value)
+}
+
+// Unwrap returns ErrTaskPanicked and Value if it is an error.
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrTaskPanicked, err}
+	}
+	return []error{ErrTaskPanicked}
+}
+
+// MultiTaskError is returned by Lyra.Run when several tasks of the same
+// stage fail.
+//
+// It matches every task error with errors.Is and errors.As.
+type MultiTaskError struct {
+	errs map[string]error
+}
+
+// NewMultiTaskError returns a MultiTaskError holding the errors of the
+// failed tasks keyed by task ID.
+func NewMultiTaskError(errs map[string]error) *MultiTaskError {
+	return &MultiTaskError{errs: maps.Clone(errs)}
+}
+
+// Errors returns a copy of the task errors keyed by task ID.
+func (e *MultiTaskError) Errors() map[string]error {
+	return maps.Clone(e.errs)
+}
+
+// TaskIDs returns the sorted IDs of the failed tasks.
+func (e *MultiTaskError) TaskIDs() []string {
+	return slices.Sorted(maps.Keys(e.errs))
+}
+
+// Error returns the task errors on separate lines, ordered by task ID.
+func (e *MultiTaskError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, taskID := range e.TaskIDs() {
+		msgs = append(msgs, e.errs[taskID].Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the task errors, ordered by task ID.
+func (e *MultiTaskError) Unwrap() []error {
+	errs := make([]error, 0, len(e.errs))
+	for _, taskID := range e.TaskIDs() {
+		errs = append(errs, e.errs[taskID])
+	}
+	return errs
+}
+
+// InputSnapshot is the value a task received for one of its parameters.
+type InputSnapshot struct {
+	// Param is the position of the parameter, starting at 2 after the context.
+	Param int
+	// Source is the task ID or runtime input key the value was taken from.
+	Source string
+	// Field is the nested field path within Source, if any.
+	Field []string
+	// Value is the value passed to the task.
+	Value any
+}
+
+// TaskInputsError wraps the error of a failed task together with the inputs
+// it was called with. It is only returned when enabled with
+// lyra.WithInputSnapshots.
+type TaskInputsError struct {
+	// TaskID is the ID of the failed task.
+	TaskID string
+	// Inputs holds the task's inputs in parameter order.
+	Inputs []InputSnapshot
+	// Err is the error returned by the task.
+	Err error
+}
+
+// Error returns the task error; the inputs are left out so that they never
+// end up in logs by accident.
+func (e *TaskInputsError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the task error.
+func (e *TaskInputsError) Unwrap() error {
+	return e.Err
+}
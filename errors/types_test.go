@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTimeoutError(t *testing.T) {
+	t.Parallel()
+
+	err := &RunTimeoutError{
+		Timeout: 2 * time.Second,
+		Pending: []string{"taskA", "taskB"},
+	}
+
+	require.Equal(t, "run timeout exceeded after 2s, pending tasks: [taskA, taskB]", err.Error())
+	require.ErrorIs(t, err, ErrRunTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var target *RunTimeoutError
+	require.True(t, errors.As(Wrapf(err, "run %s", "abc"), &target))
+	require.Equal(t, err.Pending, target.Pending)
+}
+
+func TestRunCanceledError(t *testing.T) {
+	t.Parallel()
+
+	err := &RunCanceledError{
+		Cause:    context.Canceled,
+		Canceled: []string{"taskA", "taskB"},
+	}
+
+	require.Equal(t, "run canceled: context canceled, canceled tasks: [taskA, taskB]", err.Error())
+	require.ErrorIs(t, err, context.Canceled)
+
+	var target *RunCanceledError
+	require.True(t, errors.As(Wrapf(err, "run %s", "abc"), &target))
+	require.Equal(t, err.Canceled, target.Canceled)
+}
+
+func TestPanicError(t *testing.T) {
+	t.Parallel()
+
+	err := &PanicError{Value: "boom"}
+	require.Equal(t, "task panicked: boom", err.Error())
+	require.ErrorIs(t, err, ErrTaskPanicked)
+
+	cause := errors.New("index out of range")
+	err = &PanicError{Value: cause}
+	require.ErrorIs(t, err, ErrTaskPanicked)
+	require.ErrorIs(t, err, cause)
+}
+
+func TestMultiTaskError(t *testing.T) {
+	t.Parallel()
+
+	errA := errors.New("a failed")
+	errB := Wrapf(ErrTaskNotFound, "b")
+	errs := map[string]error{"taskB": errB, "taskA": errA}
+
+	err := NewMultiTaskError(errs)
+	errs["taskC"] = errors.New("added later")
+
+	require.Equal(t, "a failed\nb: task not found", err.Error())
+	require.Equal(t, []string{"taskA", "taskB"}, err.TaskIDs())
+	require.Equal(t, []error{errA, errB}, err.Unwrap())
+	require.Equal(t, map[string]error{"taskA": errA, "taskB": errB}, err.Errors())
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	var target *MultiTaskError
+	require.True(t, errors.As(Wrapf(err, "run %s", "abc"), &target))
+	require.Len(t, target.Errors(), 2)
+}
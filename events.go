@@ -0,0 +1,175 @@
+package lyra
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of an execution Event.
+type EventType int
+
+const (
+	// EventStageStarted is emitted when a stage begins executing.
+	EventStageStarted EventType = iota + 1
+	// EventStageFinished is emitted when every task of a stage has returned.
+	EventStageFinished
+	// EventTaskScheduled is emitted for each task of a stage as the stage starts.
+	EventTaskScheduled
+	// EventTaskStarted is emitted right before a task's inputs are resolved.
+	EventTaskStarted
+	// EventTaskFinished is emitted when a task completes successfully.
+	EventTaskFinished
+	// EventTaskFailed is emitted when a task returns an error.
+	EventTaskFailed
+	// EventTaskSkipped is emitted for tasks that never started because the
+	// run failed or timed out first.
+	EventTaskSkipped
+	// EventTaskCanceled is emitted for each task canceled with Run.CancelTask,
+	// including the dependents of the task, and for tasks that never started
+	// because the run was canceled first.
+	EventTaskCanceled
+	// EventTaskRetrying is emitted when a failed task is about to be
+	// attempted again (see WithRetry).
+	EventTaskRetrying
+	// EventTaskHedged is emitted when a second execution of a slow task is
+	// started (see WithHedge).
+	EventTaskHedged
+	// EventTaskOverdue is emitted when a task runs past its soft deadline
+	// (see WithSoftDeadline).
+	EventTaskOverdue
+	// EventTaskProgress is emitted when a task reports an intermediate
+	// value with Report.
+	EventTaskProgress
+	// EventTaskSeeded is emitted instead of EventTaskStarted and
+	// EventTaskFinished for tasks whose output was seeded or precomputed
+	// (see WithSeed and WithPrecomputed).
+	EventTaskSeeded
+	// EventTaskDeduplicated is emitted when a task is not executed because
+	// an execution was already recorded for its idempotency key (see
+	// WithIdempotencyKey).
+	EventTaskDeduplicated
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventStageStarted:
+		return "stage_started"
+	case EventStageFinished:
+		return "stage_finished"
+	case EventTaskScheduled:
+		return "task_scheduled"
+	case EventTaskStarted:
+		return "task_started"
+	case EventTaskFinished:
+		return "task_finished"
+	case EventTaskFailed:
+		return "task_failed"
+	case EventTaskSkipped:
+		return "task_skipped"
+	case EventTaskCanceled:
+		return "task_canceled"
+	case EventTaskRetrying:
+		return "task_retrying"
+	case EventTaskHedged:
+		return "task_hedged"
+	case EventTaskOverdue:
+		return "task_overdue"
+	case EventTaskProgress:
+		return "task_progress"
+	case EventTaskSeeded:
+		return "task_seeded"
+	case EventTaskDeduplicated:
+		return "task_deduplicated"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes something that happened during a run.
+type Event struct {
+	Type  EventType
+	RunID string
+	Time  time.Time
+	// Stage is the index of the stage the event belongs to.
+	Stage int
+	// TaskID is the task the event is about; empty for stage events.
+	TaskID string
+	// TaskIDs lists the tasks of the stage for stage events.
+	TaskIDs []string
+	// Tags and Meta are those of the task (see WithTag and WithMeta); they
+	// are shared between events and must not be modified.
+	Tags []string
+	Meta map[string]string
+	// Err is the task error for EventTaskFailed, the error of the failed
+	// attempt for EventTaskRetrying and the stage error, if any, for
+	// EventStageFinished.
+	Err error
+	// Value is the value reported for EventTaskProgress.
+	Value any
+}
+
+// eventLog records the events of a run and replays them to any number of
+// subscribers. Publishing never blocks on subscribers.
+type eventLog struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	events []Event
+	closed bool
+}
+
+func newEventLog() *eventLog {
+	log := &eventLog{}
+	log.cond = sync.NewCond(&log.mu)
+	return log
+}
+
+// publish appends e to the log. It is a no-op on a nil log.
+func (q *eventLog) publish(e Event) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.events = append(q.events, e)
+	q.cond.Broadcast()
+}
+
+// close marks the end of the run; subscriber channels are closed once they
+// have delivered every event.
+func (q *eventLog) close() {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// subscribe returns a channel that delivers every event of the run, from the
+// first one, and is closed after the last one.
+func (q *eventLog) subscribe() <-chan Event {
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		for next := 0; ; next++ {
+			q.mu.Lock()
+			for next >= len(q.events) && !q.closed {
+				q.cond.Wait()
+			}
+			if next >= len(q.events) {
+				q.mu.Unlock()
+				return
+			}
+			e := q.events[next]
+			q.mu.Unlock()
+
+			ch <- e
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,70 @@
+package lyra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTypeString(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		eventType EventType
+		expected  string
+	}{
+		{EventStageStarted, "stage_started"},
+		{EventStageFinished, "stage_finished"},
+		{EventTaskScheduled, "task_scheduled"},
+		{EventTaskStarted, "task_started"},
+		{EventTaskFinished, "task_finished"},
+		{EventTaskFailed, "task_failed"},
+		{EventTaskSkipped, "task_skipped"},
+		{EventTaskCanceled, "task_canceled"},
+		{EventTaskRetrying, "task_retrying"},
+		{EventTaskHedged, "task_hedged"},
+		{EventTaskOverdue, "task_overdue"},
+		{EventTaskProgress, "task_progress"},
+		{EventTaskSeeded, "task_seeded"},
+		{EventTaskDeduplicated, "task_deduplicated"},
+		{EventType(0), "unknown"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.eventType.String())
+		})
+	}
+}
+
+func TestEventLogReplaysToEverySubscriber(t *testing.T) {
+	t.Parallel()
+
+	log := newEventLog()
+	log.publish(Event{Type: EventTaskStarted, TaskID: "a"})
+
+	early := log.subscribe()
+
+	log.publish(Event{Type: EventTaskFinished, TaskID: "a"})
+	log.close()
+	log.publish(Event{Type: EventTaskStarted, TaskID: "ignored"})
+
+	late := log.subscribe()
+
+	for _, ch := range []<-chan Event{early, late} {
+		var got []EventType
+		for e := range ch {
+			got = append(got, e.Type)
+		}
+		require.Equal(t, []EventType{EventTaskStarted, EventTaskFinished}, got)
+	}
+}
+
+func TestEventLogNil(t *testing.T) {
+	t.Parallel()
+
+	var log *eventLog
+	require.NotPanics(t, func() {
+		log.publish(Event{Type: EventTaskStarted})
+		log.close()
+	})
+}
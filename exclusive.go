@@ -0,0 +1,73 @@
+package lyra
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Locker serializes exclusive tasks (see WithExclusive). Implementations
+// backed by Redis, etcd or a database lock serialize them across every
+// process sharing the store.
+type Locker interface {
+	// Lock blocks until key is held or ctx is done, and returns the function
+	// releasing it.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// WithExclusive holds the lock lockKey while the task runs, including its
+// retries, so no other task holding the same key runs at the same time.
+//
+// Locks come from the run's Locker (see WithLocker) and default to a
+// process-wide one. Waiting for the lock counts against the task's context,
+// so run timeouts and cancellation still apply. A task that cannot acquire
+// the lock fails.
+//
+// Example:
+//
+//	l.Do("migrate", migrate, lyra.WithExclusive("db-migrations"))
+//	l.Run(ctx, inputs, lyra.WithLocker(redisLocker))
+func WithExclusive(lockKey string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.LockKey = lockKey
+	})
+}
+
+// processLocker is the Locker used by runs without WithLocker.
+var processLocker Locker = &localLocker{}
+
+// localLocker holds locks in memory, serializing tasks within the process.
+type localLocker struct {
+	locks sync.Map // key -> chan struct{} holding a token while locked
+}
+
+func (ll *localLocker) Lock(ctx context.Context, key string) (func(), error) {
+	v, _ := ll.locks.LoadOrStore(key, make(chan struct{}, 1))
+	lock, _ := v.(chan struct{})
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+// lockTask acquires the task's exclusive lock, if any, and returns the
+// function releasing it.
+func lockTask(ctx context.Context, task *compiledTask, state *runState) (func(), error) {
+	key := task.GetOptions().LockKey
+	if key == "" {
+		return func() {}, nil
+	}
+	locker := state.cfg.locker
+	if locker == nil {
+		locker = processLocker
+	}
+	unlock, err := locker.Lock(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to acquire lock %q", key)
+	}
+	return unlock, nil
+}
@@ -0,0 +1,118 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExclusiveSerializesTasks(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+	exclusive := func(ctx context.Context) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	key := "exclusive-" + NewRunID()
+	l := New()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		l.Do(id, exclusive, WithExclusive(key))
+	}
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, int32(1), maxRunning.Load())
+}
+
+type recordingLocker struct {
+	mu   sync.Mutex
+	keys []string
+	err  error
+}
+
+func (rl *recordingLocker) Lock(ctx context.Context, key string) (func(), error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.keys = append(rl.keys, key)
+	if rl.err != nil {
+		return nil, rl.err
+	}
+	return func() {
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+		rl.keys = append(rl.keys, "unlock "+key)
+	}, nil
+}
+
+func TestWithLocker(t *testing.T) {
+	t.Parallel()
+
+	//nolint:err113 // test case.
+	errUnavailable := stderr.New("lock store unavailable")
+
+	tcs := []struct {
+		name    string
+		lockErr error
+		keys    []string
+	}{
+		{name: "lock acquired", keys: []string{"migrations", "unlock migrations"}},
+		{name: "lock failed", lockErr: errUnavailable, keys: []string{"migrations"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			locker := &recordingLocker{err: tc.lockErr}
+			l := New().
+				Do("migrate", func(ctx context.Context) error {
+					called = true
+					return nil
+				}, WithExclusive("migrations")).
+				Do("serve", func(ctx context.Context) error {
+					return nil
+				})
+
+			_, err := l.Run(context.Background(), nil, WithLocker(locker))
+
+			require.ErrorIs(t, err, tc.lockErr)
+			require.Equal(t, tc.lockErr == nil, called)
+			require.Equal(t, tc.keys, locker.keys)
+		})
+	}
+}
+
+func TestLocalLockerCanceled(t *testing.T) {
+	t.Parallel()
+
+	locker := &localLocker{}
+	unlock, err := locker.Lock(context.Background(), "key")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locker.Lock(ctx, "key")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+	unlock, err = locker.Lock(context.Background(), "key")
+	require.NoError(t, err)
+	unlock()
+}
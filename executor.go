@@ -0,0 +1,90 @@
+package lyra
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// TaskCall is a task execution handed to an Executor.
+type TaskCall struct {
+	// TaskID is the ID of the task.
+	TaskID string
+	// Args holds the resolved inputs of the task, in parameter order and
+	// without the context.
+	Args []any
+	// OutputType is the result type of the task function, or nil if it
+	// only returns an error.
+	OutputType reflect.Type
+}
+
+// Executor executes tasks outside the process, for example on workers
+// reached over HTTP, gRPC or a queue. Lyra still resolves the inputs,
+// orders the tasks and aggregates the results; only the call itself is
+// delegated.
+type Executor interface {
+	// Execute runs the task and returns its result, which must be
+	// assignable to call.OutputType; nil stands for the zero value.
+	Execute(ctx context.Context, call TaskCall) (any, error)
+}
+
+// ExecutorFunc adapts a function to the Executor interface.
+type ExecutorFunc func(ctx context.Context, call TaskCall) (any, error)
+
+// Execute calls f.
+func (f ExecutorFunc) Execute(ctx context.Context, call TaskCall) (any, error) {
+	return f(ctx, call)
+}
+
+// WithExecutor dispatches the task to e instead of calling its function.
+// The function is still required: its signature declares the input and
+// output types of the task.
+//
+// Example:
+//
+//	l.Do("score", func(ctx context.Context, u User) (float64, error) {
+//		panic("executed remotely")
+//	}, lyra.Use("fetchUser"), lyra.WithExecutor(workerPool))
+func WithExecutor(e Executor) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Executor = func(ctx context.Context, taskID string, args []any, outputType reflect.Type) (any, error) {
+			return e.Execute(ctx, TaskCall{TaskID: taskID, Args: args, OutputType: outputType})
+		}
+	})
+}
+
+// callExecutor hands the resolved arguments of the task to its executor and
+// converts the returned value to the task's output type.
+func callExecutor(ctx context.Context, task *compiledTask, args []reflect.Value) (output any, hasOutput bool, err error) {
+	values := make([]any, 0, len(args)-1)
+	for _, arg := range args[1:] { // skip context
+		values = append(values, arg.Interface())
+	}
+
+	outputType := task.GetOutputParams()
+	output, err = task.GetOptions().Executor(ctx, task.GetID(), values, outputType)
+	if err != nil {
+		return nil, outputType != nil, err
+	}
+	if outputType == nil {
+		return nil, false, nil
+	}
+
+	typed := reflect.New(outputType).Elem()
+	if output != nil {
+		value := reflect.ValueOf(output)
+		if !value.Type().AssignableTo(outputType) {
+			return nil, true, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"executor result for task %q -> expected type %s, got %s",
+				task.GetID(),
+				outputType,
+				typeName(value.Type(), task.GetOptions().SecretOutput),
+			)
+		}
+		typed.Set(value)
+	}
+	return typed.Interface(), true, nil
+}
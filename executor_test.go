@@ -0,0 +1,102 @@
+package lyra
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestRunWithExecutor(t *testing.T) {
+	t.Parallel()
+
+	var calls []TaskCall
+	remote := ExecutorFunc(func(ctx context.Context, call TaskCall) (any, error) {
+		calls = append(calls, call)
+		user, _ := call.Args[0].(User)
+		return user.Name + " scored", nil
+	})
+
+	l := New().
+		Do("fetchUser", func(ctx context.Context, id int) (User, error) {
+			return User{ID: id, Name: "Alice"}, nil
+		}, UseRun("userID")).
+		Do("score", func(ctx context.Context, u User) (string, error) {
+			panic("executed remotely")
+		}, Use("fetchUser"), WithExecutor(remote)).
+		Do("notify", func(ctx context.Context, score string) error {
+			return nil
+		}, Use("score"), WithExecutor(ExecutorFunc(func(ctx context.Context, call TaskCall) (any, error) {
+			calls = append(calls, call)
+			return nil, nil
+		})))
+
+	result, err := l.Run(context.Background(), map[string]any{"userID": 7})
+
+	require.NoError(t, err)
+	score, err := result.Get("score")
+	require.NoError(t, err)
+	require.Equal(t, "Alice scored", score)
+	require.Equal(t, []TaskCall{
+		{TaskID: "score", Args: []any{User{ID: 7, Name: "Alice"}}, OutputType: reflect.TypeOf("")},
+		{TaskID: "notify", Args: []any{"Alice scored"}},
+	}, calls)
+}
+
+func TestRunWithExecutorResults(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		output      any
+		err         error
+		expected    any
+		expectedErr error
+	}{
+		{
+			name:     "nil is the zero value",
+			output:   nil,
+			expected: (*User)(nil),
+		},
+		{
+			name:     "matching type",
+			output:   &User{Name: "Bob"},
+			expected: &User{Name: "Bob"},
+		},
+		{
+			name:        "wrong type",
+			output:      User{Name: "Bob"},
+			expectedErr: errors.ErrInvalidParamType,
+		},
+		{
+			name:        "executor error",
+			err:         errTaskFailed,
+			expectedErr: errTaskFailed,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().Do("fetchUser", func(ctx context.Context) (*User, error) {
+				return nil, nil
+			}, WithExecutor(ExecutorFunc(func(ctx context.Context, call TaskCall) (any, error) {
+				return tc.output, tc.err
+			})))
+
+			result, err := l.Run(context.Background(), nil)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			user, err := result.Get("fetchUser")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, user)
+		})
+	}
+}
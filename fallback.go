@@ -0,0 +1,156 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithFallback lets the task degrade instead of failing: when it fails,
+// after its retries, fn is called with the task's context and error, and
+// the output fn returns is stored as the result of the task, so its
+// dependents proceed. The output must be assignable to the task's result
+// type, and is nil for tasks returning only an error. If fn returns an
+// error, the task fails with it. The fallback is called last, after the
+// functions registered with WithFallbacks.
+//
+// The task succeeds; its original error is reported by Result.Failures.
+// No fallback applies once the run is canceled. It replaces any fallback
+// set with WithFallbackOnTimeout.
+//
+// Example:
+//
+//	l.Do("recommendations", recommend, lyra.Use("user"),
+//		lyra.WithFallback(func(ctx context.Context, err error) (any, error) {
+//			return []Product{}, nil
+//		}))
+func WithFallback(fn func(ctx context.Context, err error) (any, error)) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Fallback = fn
+	})
+}
+
+// WithFallbackOnTimeout is like WithFallback, storing value when the task
+// times out, that is fails with ErrTaskTimeout (see WithTimeout) or
+// context.DeadlineExceeded. Other failures fail the task.
+//
+// Example:
+//
+//	l.Do("shippingQuote", quote, lyra.Use("cart"),
+//		lyra.WithTimeout(300*time.Millisecond), lyra.WithFallbackOnTimeout(defaultQuote))
+func WithFallbackOnTimeout(value any) TaskOption {
+	return WithFallback(func(_ context.Context, err error) (any, error) {
+		if stderr.Is(err, errors.ErrTaskTimeout) || stderr.Is(err, context.DeadlineExceeded) {
+			return value, nil
+		}
+		return nil, err
+	})
+}
+
+// WithFallbacks registers functions to call, in order, when the task
+// fails after its retries, until one succeeds: a chain such as primary ->
+// secondary -> static default, where the default is set with WithFallback.
+// Every function must have the signature of the task's function; it is
+// called once with the same inputs and the task's context. Tasks with an
+// Executor (see WithExecutor) call the fallbacks locally.
+//
+// The task succeeds with the output of the first fallback to succeed; its
+// original error is reported by Result.Failures and every attempt by
+// TaskSummary.Attempts.
+//
+// Example:
+//
+//	l.Do("geocode", geocodePrimary, lyra.Use("address"),
+//		lyra.WithFallbacks(geocodeSecondary),
+//		lyra.WithFallbackOnTimeout(Location{}))
+func WithFallbacks(fns ...Alternative) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		for _, fn := range fns {
+			o.Fallbacks = append(o.Fallbacks, fn)
+		}
+	})
+}
+
+// TaskAttempt is a call of the function of a task with fallbacks (see
+// WithFallbacks), or of one of the fallbacks.
+type TaskAttempt struct {
+	// Fallback is 0 for the task's function, including its retries, and i
+	// for its i-th fallback.
+	Fallback int
+	// Err is the error of the call, nil if it succeeded.
+	Err error
+	// Duration is how long the call took.
+	Duration time.Duration
+}
+
+// callWithFallback calls the task and replaces its failure with the output
+// of its fallbacks, if it has any.
+func callWithFallback(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	opts := task.GetOptions()
+	start := time.Now()
+	output, hasOutput, err = callWithHooks(ctx, stageIdx, task, state)
+	if len(opts.Fallbacks) > 0 {
+		state.recordAttempt(task.GetID(), TaskAttempt{Err: err, Duration: time.Since(start)})
+	}
+	if err == nil || ctx.Err() != nil {
+		return output, hasOutput, err
+	}
+
+	failure := err
+	for i, fn := range opts.Fallbacks {
+		start = time.Now()
+		output, hasOutput, err = callFunction(ctx, task, fn, state)
+		state.recordAttempt(task.GetID(), TaskAttempt{Fallback: i + 1, Err: err, Duration: time.Since(start)})
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil && opts.Fallback != nil && ctx.Err() == nil {
+		output, hasOutput, err = callDefault(ctx, task, err)
+	}
+	if err != nil {
+		return output, hasOutput, err
+	}
+
+	state.result.mu.Lock()
+	defer state.result.mu.Unlock()
+	state.result.setFailure(task.GetID(), failure)
+	return output, hasOutput, nil
+}
+
+// callDefault returns the output the fallback set with WithFallback
+// returns for err.
+func callDefault(ctx context.Context, task *compiledTask, err error) (output any, hasOutput bool, _ error) {
+	output, err = task.GetOptions().Fallback(ctx, err)
+	if err != nil {
+		return nil, false, err
+	}
+	typ := task.GetOutputParams()
+	if typ == nil {
+		return nil, false, nil
+	}
+	output, err = typedOutput(output, typ)
+	if err != nil {
+		return nil, true, errors.Wrapf(err, "invalid fallback")
+	}
+	return output, true, nil
+}
+
+// recordAttempt records a call of the task or of one of its fallbacks.
+func (s *runState) recordAttempt(taskID string, attempt TaskAttempt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.attempts == nil {
+		s.attempts = make(map[string][]TaskAttempt)
+	}
+	s.attempts[taskID] = append(s.attempts[taskID], attempt)
+}
@@ -0,0 +1,175 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestWithFallback(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("recommend", func(ctx context.Context) ([]string, error) {
+			return nil, errTaskFailed
+		}, WithFallback(func(ctx context.Context, err error) (any, error) {
+			assert.ErrorIs(t, err, errTaskFailed)
+			return []string{"bestseller"}, nil
+		})).
+		Do("page", func(ctx context.Context, items []string) (int, error) {
+			return len(items), nil
+		}, Use("recommend"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("page")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	require.ErrorIs(t, result.Failures()["recommend"], errTaskFailed)
+}
+
+func TestWithFallbackNil(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (*User, error) {
+			return nil, errTaskFailed
+		}, WithFallback(func(ctx context.Context, err error) (any, error) {
+			return nil, nil
+		})).
+		Do("greet", func(ctx context.Context, user *User) (string, error) {
+			if user == nil {
+				return "hi guest", nil
+			}
+			return "hi " + user.Name, nil
+		}, Use("fetchUser"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	greeting, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hi guest", greeting)
+	user, err := result.Get("fetchUser")
+	require.NoError(t, err)
+	require.Equal(t, (*User)(nil), user)
+}
+
+func TestWithFallbackOnTimeout(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name  string
+		fn    func(ctx context.Context) (int, error)
+		value any
+		err   error
+	}{
+		{
+			name: "timeout",
+			fn: func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			},
+			value: 5,
+		},
+		{
+			name: "other failure",
+			fn: func(ctx context.Context) (int, error) {
+				return 0, errTaskFailed
+			},
+			err: errTaskFailed,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().Do("quote", tc.fn, WithTimeout(10*time.Millisecond), WithFallbackOnTimeout(5))
+			result, err := l.Run(context.Background(), nil)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			value, err := result.Get("quote")
+			require.NoError(t, err)
+			require.Equal(t, tc.value, value)
+			require.ErrorIs(t, result.Failures()["quote"], errors.ErrTaskTimeout)
+		})
+	}
+}
+
+func TestWithFallbackInvalidOutput(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("quote", func(ctx context.Context) (int, error) {
+		return 0, errTaskFailed
+	}, WithFallback(func(ctx context.Context, err error) (any, error) {
+		return "free", nil
+	}))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrInvalidParamType)
+}
+
+func TestWithFallbacks(t *testing.T) {
+	t.Parallel()
+
+	errSecondary := stderr.New("secondary down") //nolint:err113 // test case.
+	primary := func(ctx context.Context, address string) (string, error) { return "", errTaskFailed }
+	secondary := func(ctx context.Context, address string) (string, error) { return "", errSecondary }
+	tertiary := func(ctx context.Context, address string) (string, error) { return "geo:" + address, nil }
+
+	l := New().Do("geocode", primary, UseRun("address"), WithFallbacks(secondary, tertiary))
+	result, err := l.Run(context.Background(), map[string]any{"address": "main st"})
+	require.NoError(t, err)
+	value, err := result.Get("geocode")
+	require.NoError(t, err)
+	require.Equal(t, "geo:main st", value)
+	require.ErrorIs(t, result.Failures()["geocode"], errTaskFailed)
+
+	attempts := l.RecentRuns()[0].Tasks[0].Attempts
+	require.Len(t, attempts, 3)
+	for i, attempt := range attempts {
+		require.Equal(t, i, attempt.Fallback)
+	}
+	require.ErrorIs(t, attempts[0].Err, errTaskFailed)
+	require.ErrorIs(t, attempts[1].Err, errSecondary)
+	require.NoError(t, attempts[2].Err)
+}
+
+func TestWithFallbacksExhausted(t *testing.T) {
+	t.Parallel()
+
+	fail := func(ctx context.Context) (int, error) { return 0, errTaskFailed }
+
+	_, err := New().Do("task", fail, WithFallbacks(fail)).Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+
+	l := New().Do("task", fail, WithFallbacks(fail), WithFallback(func(ctx context.Context, err error) (any, error) {
+		return 7, nil
+	}))
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("task")
+	require.NoError(t, err)
+	require.Equal(t, 7, value)
+	require.Len(t, l.RecentRuns()[0].Tasks[0].Attempts, 2)
+}
+
+func TestWithFallbacksSignatureMismatch(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("task", func(ctx context.Context) (int, error) {
+		return 1, nil
+	}, WithFallbacks(func(ctx context.Context) (string, error) { return "", nil }))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrInvalidParamType)
+}
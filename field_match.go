@@ -0,0 +1,84 @@
+package lyra
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// FieldMatcher reports whether a struct field matches a name used in a field
+// path, for paths that do not name the field exactly. See MatchFields.
+type FieldMatcher func(field reflect.StructField, name string) bool
+
+// MatchFoldCase matches field names case-insensitively, so "userid" selects
+// the field UserID.
+func MatchFoldCase(field reflect.StructField, name string) bool {
+	return strings.EqualFold(field.Name, name)
+}
+
+// MatchTag matches the name given to a field by the struct tag key, such as
+// "json" or "yaml", so paths can use the aliases the fields are declared
+// with:
+//
+//	type User struct {
+//		UserID int `json:"user_id"`
+//	}
+//
+//	l.MatchFields(lyra.MatchTag("json"))
+//	l.Do("load", load, lyra.Use("fetchUser", "user_id"))
+func MatchTag(key string) FieldMatcher {
+	return func(field reflect.StructField, name string) bool {
+		alias, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		return alias != "" && alias != "-" && alias == name
+	}
+}
+
+// MatchAny matches a field if any of the matchers does.
+func MatchAny(matchers ...FieldMatcher) FieldMatcher {
+	return func(field reflect.StructField, name string) bool {
+		for _, match := range matchers {
+			if match(field, name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchFields sets how field paths of Use, UseRun, UseFields and UseTags
+// select fields whose name they do not spell exactly, for example paths
+// written with snake_case names by a loader. A field named exactly always
+// wins; otherwise the first exported field, in declaration order, accepted
+// by match is selected.
+//
+// Returns the same Lyra instance for method chaining.
+//
+// Example:
+//
+//	l := lyra.New().MatchFields(lyra.MatchAny(lyra.MatchTag("yaml"), lyra.MatchFoldCase))
+func (l *Lyra) MatchFields(match FieldMatcher) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to set the field matcher")
+		return l
+	}
+	l.fieldMatcher = match
+	return l
+}
+
+// lookupField returns the field of the struct type typ selected by name,
+// falling back to match when no field is named exactly.
+func lookupField(typ reflect.Type, name string, match FieldMatcher) (reflect.StructField, bool) {
+	if field, ok := typ.FieldByName(name); ok || match == nil {
+		return field, ok
+	}
+	for _, field := range reflect.VisibleFields(typ) {
+		if field.IsExported() && match(field, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,117 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchFields(t *testing.T) {
+	t.Parallel()
+
+	type Profile struct {
+		DisplayName string `yaml:"display_name"`
+	}
+	type Account struct {
+		AccountID int     `yaml:"account_id"`
+		Profile   Profile `yaml:"profile"`
+	}
+
+	fetchAccount := func(ctx context.Context) (Account, error) {
+		return Account{AccountID: 7, Profile: Profile{DisplayName: "Alice"}}, nil
+	}
+	echo := func(ctx context.Context, name string) (string, error) { return name, nil }
+
+	tcs := []struct {
+		name     string
+		match    FieldMatcher
+		path     []string
+		expected any
+		wantErr  bool
+	}{
+		{
+			name:     "exact names without matcher",
+			path:     []string{"Profile", "DisplayName"},
+			expected: "Alice",
+		},
+		{
+			name:    "different case without matcher",
+			path:    []string{"profile", "displayname"},
+			wantErr: true,
+		},
+		{
+			name:     "fold case",
+			match:    MatchFoldCase,
+			path:     []string{"profile", "displayname"},
+			expected: "Alice",
+		},
+		{
+			name:     "tag aliases",
+			match:    MatchTag("yaml"),
+			path:     []string{"profile", "display_name"},
+			expected: "Alice",
+		},
+		{
+			name:     "exact names with matcher",
+			match:    MatchTag("yaml"),
+			path:     []string{"Profile", "DisplayName"},
+			expected: "Alice",
+		},
+		{
+			name:     "any matcher",
+			match:    MatchAny(MatchTag("yaml"), MatchFoldCase),
+			path:     []string{"PROFILE", "display_name"},
+			expected: "Alice",
+		},
+		{
+			name:    "unknown alias",
+			match:   MatchTag("yaml"),
+			path:    []string{"profile", "name"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New()
+			if tc.match != nil {
+				l.MatchFields(tc.match)
+			}
+			l.Do("fetchAccount", fetchAccount).
+				Do("echo", echo, Use("fetchAccount", tc.path...))
+
+			result, err := l.Run(context.Background(), nil)
+
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			name, err := result.Get("echo")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, name)
+		})
+	}
+}
+
+func TestMatchFieldsRunInputs(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		MaxRetries int `json:"max_retries"`
+	}
+
+	l := New().MatchFields(MatchTag("json")).
+		Do("retries", func(ctx context.Context, n int) (int, error) { return n, nil },
+			UseRun("config", "max_retries"))
+
+	result, err := l.Run(context.Background(), map[string]any{"config": Config{MaxRetries: 3}})
+
+	require.NoError(t, err)
+	retries, err := result.Get("retries")
+	require.NoError(t, err)
+	require.Equal(t, 3, retries)
+}
@@ -0,0 +1,168 @@
+package lyra
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Graph describes the structure of a DAG for exports and tooling.
+type Graph struct {
+	// Nodes holds every task, ordered by stage and task ID.
+	Nodes []GraphNode `json:"nodes"`
+}
+
+// GraphNode describes a task of a Graph.
+type GraphNode struct {
+	ID    string `json:"id"`
+	Stage int    `json:"stage"`
+	// Description is set with WithDescription.
+	Description string `json:"description,omitempty"`
+	// Dependencies lists the sorted IDs of the tasks this task reads.
+	Dependencies []string `json:"dependencies"`
+	// Tags and Meta are those of the task (see WithTag and WithMeta).
+	Tags []string          `json:"tags,omitempty"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// Graph returns the structure of the DAG, validated like Run does.
+func (l *Lyra) Graph() (Graph, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	tasks, err := wireTasks(l.tasks)
+	if err != nil {
+		return Graph{}, err
+	}
+	deps := dependencies(tasks)
+	stages, err := buildStages(deps)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	nodes := make([]GraphNode, 0, len(deps))
+	for i, stage := range stages {
+		for _, taskID := range stage {
+			dependencies := slices.Clone(deps[taskID])
+			slices.Sort(dependencies)
+			opts := l.tasks[taskID].GetOptions()
+			nodes = append(nodes, GraphNode{
+				ID:           taskID,
+				Stage:        i,
+				Description:  opts.Description,
+				Dependencies: slices.Compact(dependencies),
+				Tags:         slices.Clone(opts.Tags),
+				Meta:         maps.Clone(opts.Meta),
+			})
+		}
+	}
+	return Graph{Nodes: nodes}, nil
+}
+
+// TransitiveReduction returns a copy of the graph without the dependency
+// edges implied by other ones: an edge from a dependency to a task is
+// dropped when the task also depends on it through another dependency.
+// Diagrams of heavily connected DAGs rendered from it show only the
+// essential edges; execution always uses every edge.
+//
+// Example:
+//
+//	g, err := l.Graph()
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(g.TransitiveReduction().Mermaid())
+func (g Graph) TransitiveReduction() Graph {
+	deps := make(map[string][]string, len(g.Nodes))
+	for _, node := range g.Nodes {
+		deps[node.ID] = node.Dependencies
+	}
+	ancestors := make(map[string]map[string]struct{}, len(g.Nodes))
+	var ancestorsOf func(id string) map[string]struct{}
+	ancestorsOf = func(id string) map[string]struct{} {
+		if set, ok := ancestors[id]; ok {
+			return set
+		}
+		set := make(map[string]struct{})
+		ancestors[id] = set // graphs are acyclic, so id is never reached again
+		for _, dep := range deps[id] {
+			set[dep] = struct{}{}
+			for ancestor := range ancestorsOf(dep) {
+				set[ancestor] = struct{}{}
+			}
+		}
+		return set
+	}
+
+	nodes := make([]GraphNode, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		essential := make([]string, 0, len(node.Dependencies))
+		for _, dep := range node.Dependencies {
+			implied := slices.ContainsFunc(node.Dependencies, func(other string) bool {
+				_, ok := ancestorsOf(other)[dep]
+				return other != dep && ok
+			})
+			if !implied {
+				essential = append(essential, dep)
+			}
+		}
+		node.Dependencies = essential
+		nodes = append(nodes, node)
+	}
+	return Graph{Nodes: nodes}
+}
+
+// DOT renders the graph in the Graphviz DOT language, with edges pointing
+// from a dependency to its dependents. Descriptions are shown as tooltips.
+func (g Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph lyra {\n\trankdir=LR;\n")
+	for _, node := range g.Nodes {
+		if node.Description != "" {
+			fmt.Fprintf(&b, "\t%q [tooltip=%q];\n", node.ID, node.Description)
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q;\n", node.ID)
+	}
+	for _, node := range g.Nodes {
+		for _, dep := range node.Dependencies {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", dep, node.ID)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, with edges pointing
+// from a dependency to its dependents. Descriptions are shown below the
+// task IDs.
+func (g Graph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		// Task IDs may contain characters Mermaid does not allow in node
+		// IDs, so nodes are numbered and labeled with the task ID.
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		label := mermaidEscape(node.ID)
+		if node.Description != "" {
+			label += "<br/><small>" + mermaidEscape(node.Description) + "</small>"
+		}
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[node.ID], label)
+	}
+	for _, node := range g.Nodes {
+		for _, dep := range node.Dependencies {
+			fmt.Fprintf(&b, "\t%s --> %s\n", ids[dep], ids[node.ID])
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscape replaces the characters that end or break a Mermaid label
+// by their entity codes.
+var mermaidEscape = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+).Replace
@@ -0,0 +1,103 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("b", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("a", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("sum", func(ctx context.Context, x, y, z int) error {
+			return nil
+		}, Use("b"), Use("a"), Use("b"))
+
+	graph, err := l.Graph()
+
+	require.NoError(t, err)
+	require.Equal(t, Graph{Nodes: []GraphNode{
+		{ID: "a", Stage: 0, Dependencies: []string{}},
+		{ID: "b", Stage: 0, Dependencies: []string{}},
+		{ID: "sum", Stage: 1, Dependencies: []string{"a", "b"}},
+	}}, graph)
+	require.Equal(t, `digraph lyra {
+	rankdir=LR;
+	"a";
+	"b";
+	"sum";
+	"a" -> "sum";
+	"b" -> "sum";
+}
+`, graph.DOT())
+}
+
+func TestGraphDescriptions(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetch user", func(ctx context.Context) (int, error) { return 1, nil },
+			WithDescription(`fetches the "user" <profile>`)).
+		Do("report", func(ctx context.Context, _ int) error { return nil }, Use("fetch user"))
+
+	graph, err := l.Graph()
+
+	require.NoError(t, err)
+	require.Equal(t, `fetches the "user" <profile>`, graph.Nodes[0].Description)
+	require.Empty(t, graph.Nodes[1].Description)
+	require.Equal(t, `digraph lyra {
+	rankdir=LR;
+	"fetch user" [tooltip="fetches the \"user\" <profile>"];
+	"report";
+	"fetch user" -> "report";
+}
+`, graph.DOT())
+	require.Equal(t, `flowchart LR
+	n0["fetch user<br/><small>fetches the #quot;user#quot; #lt;profile#gt;</small>"]
+	n1["report"]
+	n0 --> n1
+`, graph.Mermaid())
+}
+
+func TestGraphTransitiveReduction(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("config", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetch", func(ctx context.Context, c int) (int, error) { return c, nil }, Use("config")).
+		Do("parse", func(ctx context.Context, c, f int) (int, error) {
+			return c + f, nil
+		}, Use("config"), Use("fetch")).
+		Do("report", func(ctx context.Context, c, f, p int) error {
+			return nil
+		}, Use("config"), Use("fetch"), Use("parse"))
+
+	graph, err := l.Graph()
+	require.NoError(t, err)
+	reduced := graph.TransitiveReduction()
+
+	require.Equal(t, Graph{Nodes: []GraphNode{
+		{ID: "config", Stage: 0, Dependencies: []string{}},
+		{ID: "fetch", Stage: 1, Dependencies: []string{"config"}},
+		{ID: "parse", Stage: 2, Dependencies: []string{"fetch"}},
+		{ID: "report", Stage: 3, Dependencies: []string{"parse"}},
+	}}, reduced)
+	require.Equal(t, []string{"config", "fetch", "parse"}, graph.Nodes[3].Dependencies)
+}
+
+func TestGraphInvalid(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("sum", func(ctx context.Context, x int) error {
+		return nil
+	}, Use("missing"))
+
+	_, err := l.Graph()
+	require.ErrorIs(t, err, errors.ErrMissingDependency)
+}
@@ -0,0 +1,156 @@
+package lyra
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// TopologicalOrder returns every task of the DAG in an order where each task
+// comes after the tasks it depends on, for consumers that need a sequential
+// order, such as generating migration scripts or documentation. The order
+// is deterministic: tasks are ordered by stage, then by task ID.
+//
+// The DAG is validated like Graph does.
+func (l *Lyra) TopologicalOrder() ([]string, error) {
+	g, err := l.Graph()
+	if err != nil {
+		return nil, err
+	}
+	order := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		order = append(order, node.ID)
+	}
+	return order, nil
+}
+
+// DependenciesOf returns the sorted IDs of the tasks taskID reads directly.
+// It fails with errors.ErrTaskNotFound if the DAG has no task taskID.
+//
+// The DAG is validated like Graph does.
+func (l *Lyra) DependenciesOf(taskID string) ([]string, error) {
+	return l.reach(taskID, false, false)
+}
+
+// TransitiveDependenciesOf returns the sorted IDs of every task taskID
+// depends on, directly or through other tasks.
+func (l *Lyra) TransitiveDependenciesOf(taskID string) ([]string, error) {
+	return l.reach(taskID, false, true)
+}
+
+// DependentsOf returns the sorted IDs of the tasks reading taskID directly.
+// It fails with errors.ErrTaskNotFound if the DAG has no task taskID.
+//
+// The DAG is validated like Graph does.
+func (l *Lyra) DependentsOf(taskID string) ([]string, error) {
+	return l.reach(taskID, true, false)
+}
+
+// TransitiveDependentsOf returns the sorted IDs of every task depending on
+// taskID, directly or through other tasks: the tasks affected if taskID
+// changes.
+//
+// Example:
+//
+//	affected, err := l.TransitiveDependentsOf("fetchUser")
+func (l *Lyra) TransitiveDependentsOf(taskID string) ([]string, error) {
+	return l.reach(taskID, true, true)
+}
+
+// Subgraph returns a copy of the DAG holding only the targets and every
+// task they depend on, directly or transitively, for example to run a few
+// targets, deploy part of a pipeline or visualize the tasks behind one
+// output. Like a clone (see Clone), the subgraph can be changed and run
+// without affecting l.
+//
+// It fails with errors.ErrTaskNotFound if a target is not a task of the
+// DAG. The DAG is validated like Graph does.
+//
+// Example:
+//
+//	sub, err := l.Subgraph("invoice")
+//	if err != nil {
+//		return err
+//	}
+//	results, err := sub.Run(ctx, inputs)
+func (l *Lyra) Subgraph(targets ...string) (*Lyra, error) {
+	g, err := l.Graph()
+	if err != nil {
+		return nil, err
+	}
+	edges := g.edges(false)
+	for _, target := range targets {
+		if _, ok := edges[target]; !ok {
+			return nil, errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", target)
+		}
+	}
+	keep := reachable(edges, targets, true)
+	for _, target := range targets {
+		keep[target] = struct{}{}
+	}
+
+	sub := l.Clone()
+	maps.DeleteFunc(sub.tasks, func(taskID string, _ *internal.Task) bool {
+		_, ok := keep[taskID]
+		return !ok
+	})
+	return sub, nil
+}
+
+// reach returns the sorted IDs of the tasks reached from taskID by following
+// dependency edges, or dependent edges if reverse is set, either one step
+// or transitively.
+func (l *Lyra) reach(taskID string, reverse, transitive bool) ([]string, error) {
+	g, err := l.Graph()
+	if err != nil {
+		return nil, err
+	}
+	edges := g.edges(reverse)
+	if _, ok := edges[taskID]; !ok {
+		return nil, errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
+	}
+	return slices.Sorted(maps.Keys(reachable(edges, []string{taskID}, transitive))), nil
+}
+
+// edges returns the IDs of the nodes mapped to the nodes they depend on, or
+// to their dependents if reverse is set. Every node is a key.
+func (g Graph) edges(reverse bool) map[string][]string {
+	edges := make(map[string][]string, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if _, ok := edges[node.ID]; !ok {
+			edges[node.ID] = nil
+		}
+		for _, dep := range node.Dependencies {
+			if reverse {
+				edges[dep] = append(edges[dep], node.ID)
+			} else {
+				edges[node.ID] = append(edges[node.ID], dep)
+			}
+		}
+	}
+	return edges
+}
+
+// reachable returns the nodes reached from the from nodes by following
+// edges, either one step or transitively. The from nodes are included only
+// if they are reached from one another.
+func reachable(edges map[string][]string, from []string, transitive bool) map[string]struct{} {
+	reached := make(map[string]struct{})
+	queue := slices.Clone(from)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[id] {
+			if _, ok := reached[next]; ok {
+				continue
+			}
+			reached[next] = struct{}{}
+			if transitive {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
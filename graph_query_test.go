@@ -0,0 +1,148 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestTopologicalOrder(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("report", func(ctx context.Context, x, y int) error { return nil }, Use("parse"), Use("fetchB")).
+		Do("parse", func(ctx context.Context, x int) (int, error) { return x, nil }, Use("fetchA")).
+		Do("fetchB", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetchA", func(ctx context.Context) (int, error) { return 1, nil })
+
+	order, err := l.TopologicalOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"fetchA", "fetchB", "parse", "report"}, order)
+
+	empty, err := New().TopologicalOrder()
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+func TestTopologicalOrderInvalid(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("orphan", func(ctx context.Context, x int) error { return nil }, Use("missing"))
+
+	_, err := l.TopologicalOrder()
+	require.ErrorIs(t, err, errors.ErrMissingDependency)
+}
+
+func TestDependencyQueries(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetchOrders", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("profile", func(ctx context.Context, u int) (int, error) { return u, nil }, Use("fetchUser")).
+		Do("invoice", func(ctx context.Context, p, o int) (int, error) {
+			return p + o, nil
+		}, Use("profile"), Use("fetchOrders")).
+		Do("email", func(ctx context.Context, i, u int) error { return nil }, Use("invoice"), Use("fetchUser"))
+
+	tcs := []struct {
+		name  string
+		query func(string) ([]string, error)
+		id    string
+		want  []string
+	}{
+		{name: "direct dependencies", query: l.DependenciesOf, id: "email", want: []string{"fetchUser", "invoice"}},
+		{
+			name:  "transitive dependencies",
+			query: l.TransitiveDependenciesOf,
+			id:    "email",
+			want:  []string{"fetchOrders", "fetchUser", "invoice", "profile"},
+		},
+		{name: "direct dependents", query: l.DependentsOf, id: "fetchUser", want: []string{"email", "profile"}},
+		{
+			name:  "transitive dependents",
+			query: l.TransitiveDependentsOf,
+			id:    "fetchUser",
+			want:  []string{"email", "invoice", "profile"},
+		},
+		{name: "no dependencies", query: l.TransitiveDependenciesOf, id: "fetchOrders"},
+		{name: "no dependents", query: l.DependentsOf, id: "email"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.query(tc.id)
+			require.NoError(t, err)
+			if len(tc.want) == 0 {
+				require.Empty(t, got)
+				return
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDependencyQueriesUnknownTask(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("a", func(ctx context.Context) (int, error) { return 1, nil })
+
+	_, err := l.DependentsOf("missing")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+	_, err = l.TransitiveDependenciesOf("missing")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Parallel()
+
+	var emailed bool
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetchOrders", func(ctx context.Context) (int, error) { return 2, nil }).
+		Do("profile", func(ctx context.Context, u int) (int, error) { return u * 10, nil }, Use("fetchUser")).
+		Do("invoice", func(ctx context.Context, o int) (int, error) { return o * 100, nil }, Use("fetchOrders")).
+		Do("email", func(ctx context.Context, p, i int) error {
+			emailed = true
+			return nil
+		}, Use("profile"), Use("invoice"))
+
+	sub, err := l.Subgraph("profile")
+	require.NoError(t, err)
+
+	order, err := sub.TopologicalOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"fetchUser", "profile"}, order)
+
+	result, err := sub.Run(context.Background(), nil)
+	require.NoError(t, err)
+	profile, err := result.Get("profile")
+	require.NoError(t, err)
+	require.Equal(t, 10, profile)
+	require.False(t, emailed)
+
+	// The original DAG is untouched.
+	order, err = l.TopologicalOrder()
+	require.NoError(t, err)
+	require.Len(t, order, 5)
+
+	both, err := l.Subgraph("profile", "invoice")
+	require.NoError(t, err)
+	order, err = both.TopologicalOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"fetchOrders", "fetchUser", "invoice", "profile"}, order)
+}
+
+func TestSubgraphUnknownTarget(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("a", func(ctx context.Context) (int, error) { return 1, nil })
+
+	_, err := l.Subgraph("a", "missing")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
@@ -0,0 +1,87 @@
+package lyra
+
+import (
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// GroupOutcome describes how a group of tasks (see WithGroup) fared in a
+// run.
+type GroupOutcome struct {
+	// Err is the error of the first task of the group that failed; nil if
+	// none did.
+	Err error
+	// FailedTask is the ID of that task.
+	FailedTask string
+	// Canceled lists, in sorted order, the tasks canceled because the
+	// group failed.
+	Canceled []string
+}
+
+// WithGroup puts the task in a failure domain: when a task of the group
+// fails, the unfinished tasks of the group and every task depending on the
+// group's tasks are canceled, while the rest of the run continues and can
+// succeed. The failure is reported by Result.Group instead of the error
+// returned by Run.
+//
+// Example:
+//
+//	l.Do("fetchReviews", fetchReviews, lyra.Use("product"), lyra.WithGroup("reviews"))
+//	l.Do("rankReviews", rankReviews, lyra.Use("fetchReviews"), lyra.WithGroup("reviews"))
+func WithGroup(name string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Group = name
+	})
+}
+
+// Group returns the outcome of the group of tasks named name in the run,
+// and false if no task of the DAG is in the group.
+func (r *Result) Group(name string) (GroupOutcome, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	outcome, ok := r.groups[name]
+	return outcome, ok
+}
+
+// newGroupOutcomes returns the outcomes of the groups of tasks before they
+// run.
+func newGroupOutcomes(tasks map[string]*compiledTask) map[string]GroupOutcome {
+	var groups map[string]GroupOutcome
+	for _, task := range tasks {
+		if group := task.GetOptions().Group; group != "" {
+			if groups == nil {
+				groups = make(map[string]GroupOutcome)
+			}
+			groups[group] = GroupOutcome{}
+		}
+	}
+	return groups
+}
+
+// failGroup records that the task of group failed with err, canceling the
+// unfinished tasks of the group and their dependents.
+func (s *runState) failGroup(group, taskID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := slices.Clone(s.strictDependents()[taskID])
+	for id, task := range s.tasks {
+		if task.GetOptions().Group == group {
+			queue = append(queue, id)
+		}
+	}
+	canceled := s.cancelTasks(queue)
+
+	s.result.mu.Lock()
+	defer s.result.mu.Unlock()
+	outcome := s.result.groups[group]
+	if outcome.Err == nil {
+		outcome.Err, outcome.FailedTask = err, taskID
+	}
+	outcome.Canceled = append(outcome.Canceled, canceled...)
+	slices.Sort(outcome.Canceled)
+	s.result.groups[group] = outcome
+	s.result.setFailure(taskID, err)
+}
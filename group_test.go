@@ -0,0 +1,62 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithGroup(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("product", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("fetchReviews", func(ctx context.Context, _ int) (int, error) {
+			return 0, errTaskFailed
+		}, Use("product"), WithGroup("reviews")).
+		Do("fetchRatings", func(ctx context.Context, _ int) (int, error) {
+			<-ctx.Done()
+			assert.ErrorIs(t, ctx.Err(), context.Canceled)
+			return 0, ctx.Err()
+		}, Use("product"), WithGroup("reviews")).
+		Do("rankReviews", func(ctx context.Context, v int) (int, error) { return v, nil },
+			Use("fetchReviews"), WithGroup("reviews")).
+		Do("page", func(ctx context.Context, _ int) error { return nil }, Use("rankReviews")).
+		Do("price", func(ctx context.Context, v int) (int, error) { return v * 10, nil },
+			Use("product"), WithGroup("pricing"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("price")
+	require.NoError(t, err)
+	require.Equal(t, 10, value)
+
+	reviews, ok := result.Group("reviews")
+	require.True(t, ok)
+	require.ErrorIs(t, reviews.Err, errTaskFailed)
+	require.Equal(t, "fetchReviews", reviews.FailedTask)
+	require.Equal(t, []string{"fetchRatings", "page", "rankReviews"}, reviews.Canceled)
+	require.Equal(t, map[string]error{"fetchReviews": reviews.Err}, result.Failures())
+
+	pricing, ok := result.Group("pricing")
+	require.True(t, ok)
+	require.Equal(t, GroupOutcome{}, pricing)
+
+	_, ok = result.Group("unknown")
+	require.False(t, ok)
+
+	statuses := make(map[string]TaskStatus)
+	for _, task := range l.RecentRuns()[0].Tasks {
+		statuses[task.ID] = task.Status
+	}
+	require.Equal(t, map[string]TaskStatus{
+		"product":      TaskSucceeded,
+		"fetchReviews": TaskFailed,
+		"fetchRatings": TaskCanceled,
+		"rankReviews":  TaskCanceled,
+		"page":         TaskCanceled,
+		"price":        TaskSucceeded,
+	}, statuses)
+}
@@ -0,0 +1,170 @@
+package lyra
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// PercentileHistory is implemented by a DurationHistory that can report
+// percentiles of the observed durations, as needed by WithHedge.
+type PercentileHistory interface {
+	// DurationPercentile returns the duration below which the fraction p of
+	// the task's observed durations fall, and false if nothing is known
+	// about the task.
+	DurationPercentile(taskID string, p float64) (time.Duration, bool)
+}
+
+// WindowHistory is an in-memory DurationHistory that keeps the most recent
+// durations of each task, and reports their mean and percentiles.
+//
+// It is safe for concurrent use and is meant to be shared across runs.
+type WindowHistory struct {
+	mu      sync.RWMutex
+	size    int
+	samples map[string][]time.Duration
+}
+
+// NewWindowHistory creates an empty WindowHistory keeping the last size
+// durations of each task. A non-positive size is treated as 1.
+func NewWindowHistory(size int) *WindowHistory {
+	return &WindowHistory{
+		size:    max(size, 1),
+		samples: make(map[string][]time.Duration),
+	}
+}
+
+// ExpectedDuration returns the mean of the task's recorded durations.
+func (h *WindowHistory) ExpectedDuration(taskID string) (time.Duration, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	samples := h.samples[taskID]
+	if len(samples) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for _, d := range samples {
+		total += d
+	}
+	return total / time.Duration(len(samples)), true
+}
+
+// DurationPercentile returns the nearest-rank percentile p, in (0, 1], of
+// the task's recorded durations. Values of p outside that range are clamped.
+func (h *WindowHistory) DurationPercentile(taskID string, p float64) (time.Duration, bool) {
+	h.mu.RLock()
+	samples := slices.Clone(h.samples[taskID])
+	h.mu.RUnlock()
+
+	if len(samples) == 0 {
+		return 0, false
+	}
+	slices.Sort(samples)
+	rank := int(math.Ceil(p * float64(len(samples))))
+	return samples[min(max(rank, 1), len(samples))-1], true
+}
+
+// RecordDuration adds d to the task's window, evicting the oldest duration
+// once the window is full.
+func (h *WindowHistory) RecordDuration(taskID string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := append(h.samples[taskID], d)
+	if len(samples) > h.size {
+		samples = samples[len(samples)-h.size:]
+	}
+	h.samples[taskID] = samples
+}
+
+// WithHedge launches a second, concurrent execution of the task when the
+// first has not completed within the percentile p (for example 0.95) of the
+// task's durations, taking whichever succeeds first and canceling the other.
+// Both executions see the same attempt number.
+//
+// The percentile comes from the run's DurationHistory (see
+// WithDurationHistory) when it implements PercentileHistory, such as
+// WindowHistory. Until it knows the task, fallback is used as the delay
+// instead; a non-positive fallback disables hedging for those runs.
+//
+// Only hedge tasks that are safe to execute twice.
+func WithHedge(p float64, fallback time.Duration) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.HedgePercentile = p
+		o.HedgeFallback = fallback
+	})
+}
+
+// hedgeDelay returns how long to wait for the first execution of the task
+// before hedging it, and false if the task is not hedged.
+func hedgeDelay(task *compiledTask, state *runState) (time.Duration, bool) {
+	opts := task.GetOptions()
+	if opts.HedgePercentile <= 0 {
+		return 0, false
+	}
+	if h, ok := state.cfg.history.(PercentileHistory); ok {
+		if d, ok := h.DurationPercentile(task.GetID(), opts.HedgePercentile); ok {
+			return d, true
+		}
+	}
+	return opts.HedgeFallback, opts.HedgeFallback > 0
+}
+
+// callHedged calls the task, and calls it a second time if the first call
+// is still running after the task's hedge delay. The first successful call
+// wins and the other is canceled; if both fail, the first error is returned.
+func callHedged(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	delay, ok := hedgeDelay(task, state)
+	if !ok {
+		return callFunction(ctx, task, nil, state)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		output    any
+		hasOutput bool
+		err       error
+	}
+	outcomes := make(chan outcome, 2) // buffered so the loser never blocks
+	launch := func() {
+		go func() {
+			output, hasOutput, err := callFunction(ctx, task, nil, state)
+			outcomes <- outcome{output: output, hasOutput: hasOutput, err: err}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case first := <-outcomes:
+		return first.output, first.hasOutput, first.err
+	case <-timer.C:
+	}
+
+	state.emit(Event{Type: EventTaskHedged, Stage: stageIdx, TaskID: task.GetID()})
+	launch()
+
+	first := <-outcomes
+	if first.err == nil {
+		return first.output, first.hasOutput, nil
+	}
+	second := <-outcomes
+	if second.err == nil {
+		return second.output, second.hasOutput, nil
+	}
+	return first.output, first.hasOutput, first.err
+}
@@ -0,0 +1,103 @@
+package lyra
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowHistory(t *testing.T) {
+	t.Parallel()
+
+	h := NewWindowHistory(4)
+	_, ok := h.ExpectedDuration("task")
+	require.False(t, ok)
+	_, ok = h.DurationPercentile("task", 0.5)
+	require.False(t, ok)
+
+	for _, d := range []time.Duration{100, 10, 40, 20, 30} {
+		h.RecordDuration("task", d*time.Millisecond)
+	}
+
+	// 100ms was evicted; the window holds 10, 40, 20 and 30ms.
+	mean, ok := h.ExpectedDuration("task")
+	require.True(t, ok)
+	require.Equal(t, 25*time.Millisecond, mean)
+
+	tcs := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0.25, 10 * time.Millisecond},
+		{0.5, 20 * time.Millisecond},
+		{0.95, 40 * time.Millisecond},
+		{0, 10 * time.Millisecond},
+		{2, 40 * time.Millisecond},
+	}
+	for _, tc := range tcs {
+		d, ok := h.DurationPercentile("task", tc.p)
+		require.True(t, ok)
+		require.Equal(t, tc.expected, d, "percentile %v", tc.p)
+	}
+}
+
+func TestRunWithHedge(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	l := New().Do("remote", func(ctx context.Context) (int, error) {
+		call := calls.Add(1)
+		if call == 1 {
+			<-ctx.Done() // the first call hangs until the hedge wins
+			return 0, ctx.Err()
+		}
+		return int(call), nil
+	}, WithHedge(0.95, 10*time.Millisecond))
+
+	run := l.RunAsync(context.Background(), nil)
+	result, err := run.Wait()
+
+	require.NoError(t, err)
+	value, err := result.Get("remote")
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+	require.Len(t, eventsOfType(collectEvents(run), EventTaskHedged), 1)
+}
+
+func TestRunWithHedgeUsesHistory(t *testing.T) {
+	t.Parallel()
+
+	history := NewWindowHistory(10)
+	history.RecordDuration("remote", time.Hour)
+
+	var calls atomic.Int32
+	l := New().Do("remote", func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 1, nil
+	}, WithHedge(0.95, time.Millisecond))
+
+	_, err := l.Run(context.Background(), nil, WithDurationHistory(history))
+
+	require.NoError(t, err)
+	require.Equal(t, int32(1), calls.Load(), "history delay wins over the fallback")
+}
+
+func TestRunWithHedgeBothFail(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	l := New().Do("remote", func(ctx context.Context) error {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return errTaskFailed
+	}, WithHedge(0.95, time.Millisecond))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,157 @@
+package lyra
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// HistoryStore keeps the summaries of finished runs, for example in a file
+// or a database so the history outlives the process.
+//
+// Implementations must be safe for concurrent use.
+type HistoryStore interface {
+	// Append stores a finished run.
+	Append(run RunSummary) error
+	// Runs returns the stored runs, oldest first.
+	Runs() ([]RunSummary, error)
+}
+
+// HistoryQuery selects runs returned by Lyra.History. Zero fields match
+// every run.
+type HistoryQuery struct {
+	// Since drops runs started before it.
+	Since time.Time
+	// Failed keeps only the runs that returned an error.
+	Failed bool
+	// TaskID keeps only the runs that included the task.
+	TaskID string
+	// InputsHash keeps only the runs with the given RunSummary.InputsHash.
+	InputsHash string
+	// Limit caps the number of runs returned; zero means no cap.
+	Limit int
+}
+
+// RecordHistory appends the summary of every run of l to store, in addition
+// to the in-memory runs returned by RecentRuns. Lyra.History then queries
+// store. Errors returned by store are logged to the run's Logger, if any,
+// and never fail the run.
+//
+// Example:
+//
+//	l.RecordHistory(sqlHistory)
+//	failed, err := l.History(lyra.HistoryQuery{Failed: true, Limit: 10})
+func (l *Lyra) RecordHistory(store HistoryStore) *Lyra {
+	l.recentMu.Lock()
+	defer l.recentMu.Unlock()
+
+	l.historyStore = store
+	return l
+}
+
+// History returns the runs of l matching q, most recent first. Without a
+// store set with RecordHistory, it queries the runs kept by RecentRuns.
+func (l *Lyra) History(q HistoryQuery) ([]RunSummary, error) {
+	l.recentMu.Lock()
+	store := l.historyStore
+	l.recentMu.Unlock()
+
+	var runs []RunSummary
+	if store == nil {
+		runs = l.RecentRuns()
+	} else {
+		stored, err := store.Runs()
+		if err != nil {
+			return nil, fmt.Errorf("read run history: %w", err)
+		}
+		runs = slices.Clone(stored)
+		slices.Reverse(runs)
+	}
+
+	matched := make([]RunSummary, 0, len(runs))
+	for _, run := range runs {
+		if q.Limit > 0 && len(matched) == q.Limit {
+			break
+		}
+		if q.matches(run) {
+			matched = append(matched, run)
+		}
+	}
+	return matched, nil
+}
+
+func (q HistoryQuery) matches(run RunSummary) bool {
+	switch {
+	case run.Start.Before(q.Since):
+		return false
+	case q.Failed && run.Err == nil:
+		return false
+	case q.InputsHash != "" && run.InputsHash != q.InputsHash:
+		return false
+	case q.TaskID != "":
+		return slices.ContainsFunc(run.Tasks, func(task TaskSummary) bool {
+			return task.ID == q.TaskID
+		})
+	default:
+		return true
+	}
+}
+
+// FlakyTasks returns the sorted IDs of the tasks that both succeeded and
+// failed across runs with the same inputs, a sign that their outcome does
+// not only depend on their inputs.
+//
+// Example:
+//
+//	runs, _ := l.History(lyra.HistoryQuery{Since: time.Now().Add(-24 * time.Hour)})
+//	log.Printf("flaky tasks: %v", lyra.FlakyTasks(runs))
+func FlakyTasks(runs []RunSummary) []string {
+	type outcomes struct{ succeeded, failed bool }
+	seen := make(map[[2]string]*outcomes) // inputs hash, task ID
+	flaky := make(map[string]struct{})
+	for _, run := range runs {
+		for _, task := range run.Tasks {
+			key := [2]string{run.InputsHash, task.ID}
+			o, ok := seen[key]
+			if !ok {
+				o = &outcomes{}
+				seen[key] = o
+			}
+			switch task.Status {
+			case TaskSucceeded:
+				o.succeeded = true
+			case TaskFailed:
+				o.failed = true
+			default:
+				continue
+			}
+			if o.succeeded && o.failed {
+				flaky[task.ID] = struct{}{}
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(flaky))
+}
+
+// hashInputs returns a digest of the runtime inputs identifying runs with
+// the same inputs. Sensitive values are left out, and values that cannot be
+// encoded as JSON are hashed by their Go syntax representation.
+func hashInputs(runInputs map[string]any, secrets map[string]struct{}) string {
+	h := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(runInputs)) {
+		value := runInputs[key]
+		if _, ok := secrets[key]; ok {
+			value = Redacted
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = fmt.Appendf(nil, "%#v", value)
+		}
+		fmt.Fprintf(h, "%q=%s\n", key, data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
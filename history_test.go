@@ -0,0 +1,122 @@
+package lyra
+
+import (
+	"bytes"
+	"context"
+	stderr "errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryHistory struct {
+	mu   sync.Mutex
+	runs []RunSummary
+	err  error
+}
+
+func (m *memoryHistory) Append(run RunSummary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *memoryHistory) Runs() ([]RunSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runs, m.err
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryHistory{}
+	l := New().
+		Do("check", func(ctx context.Context, n int) error {
+			if n < 0 {
+				return errTaskFailed
+			}
+			return nil
+		}, UseRun("n")).
+		RecordHistory(store)
+
+	start := time.Now()
+	for i, n := range []int{1, -1, 1} {
+		_, _ = l.Run(context.Background(), map[string]any{"n": n}, WithRunID([]string{"a", "b", "c"}[i]))
+	}
+	require.Len(t, store.runs, 3)
+	require.Equal(t, store.runs[0].InputsHash, store.runs[2].InputsHash)
+	require.NotEqual(t, store.runs[0].InputsHash, store.runs[1].InputsHash)
+
+	tcs := []struct {
+		name string
+		q    HistoryQuery
+		ids  []string
+	}{
+		{name: "all", ids: []string{"c", "b", "a"}},
+		{name: "limit", q: HistoryQuery{Limit: 2}, ids: []string{"c", "b"}},
+		{name: "failed", q: HistoryQuery{Failed: true}, ids: []string{"b"}},
+		{name: "inputs", q: HistoryQuery{InputsHash: store.runs[0].InputsHash}, ids: []string{"c", "a"}},
+		{name: "task", q: HistoryQuery{TaskID: "other"}, ids: []string{}},
+		{name: "since", q: HistoryQuery{Since: start.Add(time.Hour)}, ids: []string{}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			runs, err := l.History(tc.q)
+
+			require.NoError(t, err)
+			ids := make([]string, 0, len(runs))
+			for _, run := range runs {
+				ids = append(ids, run.ID)
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestHistoryStoreErrors(t *testing.T) {
+	t.Parallel()
+
+	//nolint:err113 // test case.
+	store := &memoryHistory{err: stderr.New("disk full")}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	l := New().Do("task", func(ctx context.Context) error { return nil }).RecordHistory(store)
+
+	_, err := l.Run(context.Background(), nil, WithLogger(logger))
+	require.NoError(t, err, "history errors never fail the run")
+	require.Contains(t, buf.String(), `level=ERROR msg="lyra: history store failed" run_id=`)
+
+	_, err = l.History(HistoryQuery{})
+	require.ErrorIs(t, err, store.err)
+}
+
+func TestFlakyTasks(t *testing.T) {
+	t.Parallel()
+
+	run := func(hash string, statuses map[string]TaskStatus) RunSummary {
+		summary := RunSummary{InputsHash: hash}
+		for id, status := range statuses {
+			summary.Tasks = append(summary.Tasks, TaskSummary{ID: id, Status: status})
+		}
+		return summary
+	}
+
+	flaky := FlakyTasks([]RunSummary{
+		run("x", map[string]TaskStatus{"fetch": TaskSucceeded, "parse": TaskFailed, "send": TaskSucceeded}),
+		run("y", map[string]TaskStatus{"fetch": TaskSucceeded, "parse": TaskSucceeded, "send": TaskSkipped}),
+		run("x", map[string]TaskStatus{"fetch": TaskFailed, "parse": TaskFailed, "send": TaskSucceeded}),
+	})
+
+	require.Equal(t, []string{"fetch"}, flaky)
+}
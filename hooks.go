@@ -0,0 +1,47 @@
+package lyra
+
+import (
+	"context"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithHooks calls onStart when the task starts executing, once it holds
+// its bulkheads and locks, and onEnd when it finished with its output and
+// error, after every retry. Either hook may be nil. Hooks run on the task's
+// goroutine with its context, so TaskIDFromContext identifies the task, and
+// the time they take counts towards the task.
+//
+// Example:
+//
+//	l.Do("charge", charge, lyra.Use("order"), lyra.WithHooks(
+//		func(ctx context.Context) {
+//			log.Print("charging")
+//		},
+//		func(ctx context.Context, output any, err error) {
+//			log.Printf("charged: %v", err)
+//		}))
+func WithHooks(onStart func(ctx context.Context), onEnd func(ctx context.Context, output any, err error)) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.OnStart = onStart
+		o.OnEnd = onEnd
+	})
+}
+
+// callWithHooks calls the task between its hooks.
+func callWithHooks(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	opts := task.GetOptions()
+	if opts.OnStart != nil {
+		opts.OnStart(ctx)
+	}
+	output, hasOutput, err = callIdempotent(ctx, stageIdx, task, state)
+	if opts.OnEnd != nil {
+		opts.OnEnd(ctx, output, err)
+	}
+	return output, hasOutput, err
+}
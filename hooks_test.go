@@ -0,0 +1,55 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHooks(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	attempts := 0
+	l := New().
+		Do("fetch", func(ctx context.Context) (int, error) { return 2, nil }).
+		Do("double", func(ctx context.Context, v int) (int, error) {
+			calls = append(calls, "call")
+			attempts++
+			if attempts == 1 {
+				return 0, errTaskFailed
+			}
+			return v * 2, nil
+		}, WithRetry(1), Use("fetch"), WithHooks(
+			func(ctx context.Context) {
+				taskID, _ := TaskIDFromContext(ctx)
+				assert.Equal(t, "double", taskID)
+				calls = append(calls, "start")
+			},
+			func(ctx context.Context, output any, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 4, output)
+				calls = append(calls, "end")
+			}))
+
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"start", "call", "call", "end"}, calls)
+}
+
+func TestWithHooksOnFailure(t *testing.T) {
+	t.Parallel()
+
+	var endErr error
+	l := New().Do("fail", func(ctx context.Context) error {
+		return errTaskFailed
+	}, WithHooks(nil, func(ctx context.Context, output any, err error) {
+		endErr = err
+	}))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+	require.ErrorIs(t, endErr, errTaskFailed)
+}
@@ -0,0 +1,147 @@
+package lyra
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// IdempotencyStore records the successful executions of tasks with an
+// idempotency key (see WithIdempotencyKey). Implementations backed by a
+// database deduplicate executions across every process sharing the store.
+//
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Load returns the output recorded for key of the task, and false if
+	// no execution was recorded.
+	Load(ctx context.Context, taskID, key string) (output any, ok bool, err error)
+	// Save records that the task executed successfully for key and
+	// returned output, which is nil for tasks returning only an error.
+	Save(ctx context.Context, taskID, key string, output any) error
+}
+
+// WithIdempotencyKey records every successful execution of the task under
+// the key returned by fn for its inputs, and skips the task when an
+// execution was already recorded for the key, for example when a failed
+// run is retried or resumed. The skipped task succeeds with the recorded
+// output. Use it for side-effecting tasks such as charging a card or sending
+// an email. An empty key executes the task without recording it.
+//
+// Executions are recorded in the run's IdempotencyStore (see
+// WithIdempotencyStore), which defaults to one held in memory by the Lyra
+// instance, remembering its latest 10000 executions. A task whose key cannot be loaded or saved fails. Executions
+// running at the same time are not deduplicated; serialize them with
+// WithExclusive if they can overlap.
+//
+// Example:
+//
+//	l.Do("charge", charge, lyra.Use("order"),
+//		lyra.WithIdempotencyKey(func(ctx context.Context, inputs []any) string {
+//			return "charge:" + inputs[0].(Order).ID
+//		}))
+func WithIdempotencyKey(fn func(ctx context.Context, inputs []any) string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.IdempotencyKey = fn
+	})
+}
+
+// defaultIdempotencyEntries bounds the executions recorded by the default
+// IdempotencyStore of a Lyra instance.
+const defaultIdempotencyEntries = 10_000
+
+// memoryIdempotencyKey identifies a recorded execution.
+type memoryIdempotencyKey struct {
+	taskID string
+	key    string
+}
+
+// memoryIdempotencyStore records executions in memory, forgetting the
+// oldest once it holds maxEntries; zero means no bound.
+type memoryIdempotencyStore struct {
+	mu         sync.Mutex
+	outputs    map[memoryIdempotencyKey]any
+	order      []memoryIdempotencyKey // oldest first
+	maxEntries int
+}
+
+func (s *memoryIdempotencyStore) Load(_ context.Context, taskID, key string) (any, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	output, ok := s.outputs[memoryIdempotencyKey{taskID: taskID, key: key}]
+	return output, ok, nil
+}
+
+func (s *memoryIdempotencyStore) Save(_ context.Context, taskID, key string, output any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.outputs == nil {
+		s.outputs = make(map[memoryIdempotencyKey]any)
+	}
+	k := memoryIdempotencyKey{taskID: taskID, key: key}
+	if _, ok := s.outputs[k]; !ok {
+		s.order = append(s.order, k)
+	}
+	s.outputs[k] = output
+	for s.maxEntries > 0 && len(s.order) > s.maxEntries {
+		delete(s.outputs, s.order[0])
+		s.order = s.order[1:]
+	}
+	return nil
+}
+
+// callIdempotent calls the task with its retries, unless an execution was
+// already recorded for its idempotency key, and records the successful
+// execution.
+func callIdempotent(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	keyFn := task.GetOptions().IdempotencyKey
+	if keyFn == nil {
+		return callWithRetries(ctx, stageIdx, task, state)
+	}
+	args, err := task.resolve(ctx, state.result)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "input resolution failed")
+	}
+	inputs := make([]any, 0, len(args)-1)
+	for _, arg := range args[1:] { // skip the context
+		inputs = append(inputs, arg.Interface())
+	}
+	key := keyFn(ctx, inputs)
+	if key == "" {
+		return callWithRetries(ctx, stageIdx, task, state)
+	}
+
+	store := state.cfg.idempotency
+	hasOutput = task.GetOutputParams() != nil
+	output, ok, err := store.Load(ctx, task.GetID(), key)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to load idempotency key %q", key)
+	}
+	if ok {
+		if hasOutput {
+			output, err = typedOutput(output, task.GetOutputParams())
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "recorded output of idempotency key %q", key)
+			}
+		}
+		state.emit(Event{Type: EventTaskDeduplicated, Stage: stageIdx, TaskID: task.GetID()})
+		return output, hasOutput, nil
+	}
+
+	output, hasOutput, err = callWithRetries(ctx, stageIdx, task, state)
+	if err != nil {
+		return output, hasOutput, err
+	}
+	if err := store.Save(context.WithoutCancel(ctx), task.GetID(), key, output); err != nil {
+		return nil, false, errors.Wrapf(err, "failed to save idempotency key %q", key)
+	}
+	return output, hasOutput, nil
+}
@@ -0,0 +1,188 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// failingIdempotencyStore fails every call with err.
+type failingIdempotencyStore struct {
+	err error
+}
+
+func (s failingIdempotencyStore) Load(context.Context, string, string) (any, bool, error) {
+	return nil, false, s.err
+}
+
+func (s failingIdempotencyStore) Save(context.Context, string, string, any) error {
+	return s.err
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	errNotify := stderr.New("notify failed") //nolint:err113 // test case.
+	var charges atomic.Int32
+	failNotify := true
+	l := New().
+		Do("charge", func(ctx context.Context, orderID string) (string, error) {
+			n := charges.Add(1)
+			return fmt.Sprintf("receipt-%d", n), nil
+		}, UseRun("orderID"), WithIdempotencyKey(func(ctx context.Context, inputs []any) string {
+			return inputs[0].(string)
+		})).
+		Do("notify", func(ctx context.Context, receipt string) error {
+			if failNotify {
+				return errNotify
+			}
+			return nil
+		}, Use("charge"))
+
+	store := &memoryIdempotencyStore{}
+	inputs := map[string]any{"orderID": "order-1"}
+
+	_, err := l.Run(context.Background(), inputs, WithIdempotencyStore(store))
+	require.ErrorIs(t, err, errNotify)
+
+	failNotify = false
+	run := l.RunAsync(context.Background(), inputs, WithIdempotencyStore(store))
+	var deduplicated []string
+	for e := range run.Events() {
+		if e.Type == EventTaskDeduplicated {
+			deduplicated = append(deduplicated, e.TaskID)
+		}
+	}
+	result, err := run.Wait()
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), charges.Load())
+	require.Equal(t, []string{"charge"}, deduplicated)
+	receipt, err := result.Get("charge")
+	require.NoError(t, err)
+	require.Equal(t, "receipt-1", receipt)
+
+	_, err = l.Run(context.Background(), map[string]any{"orderID": "order-2"}, WithIdempotencyStore(store))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), charges.Load())
+}
+
+func TestWithIdempotencyKeyEmpty(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	l := New().Do("send", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}, WithIdempotencyKey(func(ctx context.Context, inputs []any) string { return "" }))
+
+	store := &memoryIdempotencyStore{}
+	for range 2 {
+		_, err := l.Run(context.Background(), nil, WithIdempotencyStore(store))
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestWithIdempotencyKeyErrors(t *testing.T) {
+	t.Parallel()
+
+	errStore := stderr.New("store unavailable") //nolint:err113 // test case.
+	recorded := &memoryIdempotencyStore{}
+	require.NoError(t, recorded.Save(context.Background(), "count", "key", "not an int"))
+
+	tcs := []struct {
+		name    string
+		store   IdempotencyStore
+		wantErr error
+	}{
+		{name: "store failure", store: failingIdempotencyStore{err: errStore}, wantErr: errStore},
+		{name: "recorded output of another type", store: recorded, wantErr: errors.ErrInvalidParamType},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().Do("count", func(ctx context.Context) (int, error) {
+				return 1, nil
+			}, WithIdempotencyKey(func(ctx context.Context, inputs []any) string { return "key" }))
+
+			_, err := l.Run(context.Background(), nil, WithIdempotencyStore(tc.store))
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestWithIdempotencyKeyDefaultStore(t *testing.T) {
+	t.Parallel()
+
+	newLyra := func(output string) *Lyra {
+		return New().Do("send", func(ctx context.Context) (string, error) {
+			return output, nil
+		}, WithIdempotencyKey(func(ctx context.Context, inputs []any) string { return "k" }))
+	}
+	a, b := newLyra("from a"), newLyra("from b")
+
+	for _, tc := range []struct {
+		l    *Lyra
+		want string
+	}{{a, "from a"}, {b, "from b"}, {a, "from a"}} {
+		result, err := tc.l.Run(context.Background(), nil)
+		require.NoError(t, err)
+		output, err := result.Get("send")
+		require.NoError(t, err)
+		require.Equal(t, tc.want, output)
+	}
+}
+
+func TestMemoryIdempotencyStoreBounded(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &memoryIdempotencyStore{maxEntries: 2}
+	require.NoError(t, store.Save(ctx, "task", "a", 1))
+	require.NoError(t, store.Save(ctx, "task", "b", 2))
+	require.NoError(t, store.Save(ctx, "task", "a", 3)) // updates without growing
+	require.NoError(t, store.Save(ctx, "task", "c", 4))
+
+	_, ok, err := store.Load(ctx, "task", "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+	for key, want := range map[string]int{"b": 2, "c": 4} {
+		output, ok, err := store.Load(ctx, "task", key)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, want, output)
+	}
+	require.Len(t, store.outputs, 2)
+}
+
+func TestWithIdempotencyKeyRecordedNil(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryIdempotencyStore{}
+	require.NoError(t, store.Save(context.Background(), "fetchUser", "k", nil))
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (*User, error) {
+			return &User{Name: "fetched"}, nil
+		}, WithIdempotencyKey(func(ctx context.Context, inputs []any) string { return "k" })).
+		Do("greet", func(ctx context.Context, user *User) (bool, error) {
+			return user == nil, nil
+		}, Use("fetchUser"))
+
+	result, err := l.Run(context.Background(), nil, WithIdempotencyStore(store))
+	require.NoError(t, err)
+	user, err := result.Get("fetchUser")
+	require.NoError(t, err)
+	require.Equal(t, (*User)(nil), user)
+	guest, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, true, guest)
+}
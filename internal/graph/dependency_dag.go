@@ -1,6 +1,10 @@
 package graph
 
 import (
+	stderr "errors"
+	"slices"
+	"strings"
+
 	"github.com/sourabh-kumar2/lyra/errors"
 )
 
@@ -35,15 +39,20 @@ func NewDependencyDAG(dependencies map[string][]string) *DependencyDAG {
 // between them. Each level must complete before the next level can begin.
 //
 // Returns a slice of string slices, where each inner slice contains node IDs
-// that can run in parallel.
+// that can run in parallel, sorted by ID so the output is deterministic.
 //
 // Returns an error if:
-//   - Cycles are detected in the dependency graph
-//   - Missing dependencies are found (node depends on non-existent node)
+//   - Cycles are detected in the dependency graph; the error names one of
+//     them, e.g. "cycle a -> b -> c -> a"
+//   - Missing dependencies are found (node depends on non-existent node);
+//     the error joins one error per missing dependency
 //
 // Example output: [["task1", "task2"], ["task3"], ["task4"]]
 // This means task1 and task2 can run in parallel, then task3, then task4.
 //
+// The levels run in O(V+E) and share one backing array, so a level must not
+// be appended to.
+//
 //nolint:cyclop // Kahn's algo
 //revive:disable-next-line:cyclomatic,cognitive-complexity
 func (g *DependencyDAG) GetExecutionLevels() ([][]string, error) {
@@ -51,77 +60,137 @@ func (g *DependencyDAG) GetExecutionLevels() ([][]string, error) {
 		return [][]string{}, nil
 	}
 
-	inDegree, err := g.getInDegree()
+	ids, index := g.indexNodes()
+	inDegree, err := g.getInDegree(ids, index)
 	if err != nil {
 		return nil, err
 	}
+	offsets, dependents := g.reverseDeps(ids, index)
 
-	queue := make([]string, 0, len(g.deps))
-	for nodeID, degree := range inDegree {
+	// queue holds every node in processing order; the nodes of a level are
+	// appended while the previous level is walked, so no level is copied.
+	queue := make([]int, 0, len(ids))
+	for node, degree := range inDegree {
 		if degree == 0 {
-			queue = append(queue, nodeID)
+			queue = append(queue, node)
 		}
 	}
 
-	reverseDeps := make(map[string][]string, len(g.deps))
-	for nodeID, deps := range g.deps {
-		for _, dep := range deps {
-			reverseDeps[dep] = append(reverseDeps[dep], nodeID)
-		}
-	}
-
-	levels := make([][]string, 0, len(g.deps))
-	processedCount := 0
-
-	for len(queue) > 0 {
-		// Sort current level for deterministic output
-		currentLevel := make([]string, len(queue))
-		copy(currentLevel, queue)
-
-		levels = append(levels, currentLevel)
-		processedCount += len(currentLevel)
-
-		// Process all nodes in current level
-		nextQueue := queue[:0]
-		for _, nodeID := range queue {
-			for _, dependentID := range reverseDeps[nodeID] {
-				inDegree[dependentID]--
-				if inDegree[dependentID] == 0 {
-					nextQueue = append(nextQueue, dependentID)
+	order := make([]string, 0, len(ids))
+	var levels [][]string
+	for start := 0; start < len(queue); {
+		end := len(queue)
+		for _, node := range queue[start:end] {
+			order = append(order, ids[node])
+			for _, dependent := range dependents[offsets[node]:offsets[node+1]] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					queue = append(queue, dependent)
 				}
 			}
 		}
-
-		queue = nextQueue
+		level := order[start:end:end]
+		slices.Sort(level)
+		levels = append(levels, level)
+		start = end
 	}
 
-	if processedCount != len(g.deps) {
-		return nil, errors.ErrCyclicDependency
+	if len(order) != len(ids) {
+		return nil, errors.Wrapf(errors.ErrCyclicDependency, "cycle %s", g.findCycle(ids, index, inDegree))
 	}
 
 	return levels, nil
 }
 
-func (g *DependencyDAG) getInDegree() (map[string]int, error) {
-	inDegree := make(map[string]int)
-
-	// Initialize in-degrees for all nodes
+// indexNodes assigns every node a dense index in ID order, so that errors
+// report the same node on every call.
+func (g *DependencyDAG) indexNodes() (ids []string, index map[string]int) {
+	ids = make([]string, 0, len(g.deps))
 	for nodeID := range g.deps {
-		inDegree[nodeID] = 0
+		ids = append(ids, nodeID)
 	}
+	slices.Sort(ids)
 
-	for nodeID, deps := range g.deps {
-		for _, depNode := range deps {
-			if _, exists := inDegree[depNode]; !exists {
-				return nil, errors.Wrapf(
+	index = make(map[string]int, len(g.deps))
+	for i, nodeID := range ids {
+		index[nodeID] = i
+	}
+	return ids, index
+}
+
+// getInDegree returns the number of dependencies of every node. It reports
+// every missing dependency at once, so a large DAG can be fixed in one pass.
+func (g *DependencyDAG) getInDegree(ids []string, index map[string]int) ([]int, error) {
+	inDegree := make([]int, len(ids))
+	var errs []error
+	for node, nodeID := range ids {
+		for _, depNode := range g.deps[nodeID] {
+			if _, exists := index[depNode]; !exists {
+				errs = append(errs, errors.Wrapf(
 					errors.ErrMissingDependency,
 					"node %q depends on non-existent node %q",
 					nodeID,
 					depNode,
-				)
+				))
+				continue
 			}
-			inDegree[nodeID]++ // nodeID has an incoming edge
+			inDegree[node]++ // nodeID has an incoming edge
 		}
 	}
+	if len(errs) > 0 {
+		//nolint:wrapcheck // stderr points to standard errors.
+		return nil, stderr.Join(errs...)
+	}
 	return inDegree, nil
 }
+
+// findCycle returns one cycle among the nodes Kahn's algorithm could not
+// order, written in execution direction: "a -> b -> a" means b depends on a.
+// Every such node still has a dependency that was not ordered, so following
+// those dependencies from any of them must revisit a node.
+func (g *DependencyDAG) findCycle(ids []string, index map[string]int, inDegree []int) string {
+	start := slices.IndexFunc(inDegree, func(degree int) bool { return degree > 0 })
+	visited := make(map[int]int) // node -> position in path
+	var path []string
+	for node := start; ; {
+		if pos, seen := visited[node]; seen {
+			cycle := append(path[pos:], ids[node])
+			slices.Reverse(cycle)
+			return strings.Join(cycle, " -> ")
+		}
+		visited[node] = len(path)
+		path = append(path, ids[node])
+		for _, depNode := range g.deps[ids[node]] {
+			if dep := index[depNode]; inDegree[dep] > 0 {
+				node = dep
+				break
+			}
+		}
+	}
+}
+
+// reverseDeps returns the dependents of every node in compressed form: the
+// dependents of node n are dependents[offsets[n]:offsets[n+1]].
+func (g *DependencyDAG) reverseDeps(ids []string, index map[string]int) (offsets, dependents []int) {
+	offsets = make([]int, len(ids)+1)
+	for _, nodeID := range ids {
+		for _, depNode := range g.deps[nodeID] {
+			offsets[index[depNode]+1]++
+		}
+	}
+	for n := range ids {
+		offsets[n+1] += offsets[n]
+	}
+
+	dependents = make([]int, offsets[len(ids)])
+	next := make([]int, len(ids))
+	copy(next, offsets)
+	for node, nodeID := range ids {
+		for _, depNode := range g.deps[nodeID] {
+			dep := index[depNode]
+			dependents[next[dep]] = node
+			next[dep]++
+		}
+	}
+	return offsets, dependents
+}
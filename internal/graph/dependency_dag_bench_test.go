@@ -38,6 +38,30 @@ func BenchmarkDependencyDAG(b *testing.B) {
 			name: "complex diamond 100 nodes",
 			deps: generateComplexGraph(200),
 		},
+		{
+			name: "large graph 10k nodes",
+			deps: generateLinearGraph(10_000),
+		},
+		{
+			name: "wide graph 10k nodes parallel",
+			deps: generateWideGraph(10_000),
+		},
+		{
+			name: "complex graph 10k nodes",
+			deps: generateComplexGraph(10_000),
+		},
+		{
+			name: "large graph 100k nodes",
+			deps: generateLinearGraph(100_000),
+		},
+		{
+			name: "wide graph 100k nodes parallel",
+			deps: generateWideGraph(100_000),
+		},
+		{
+			name: "complex graph 100k nodes",
+			deps: generateComplexGraph(100_000),
+		},
 	}
 
 	for _, bm := range benchmarks {
@@ -76,7 +76,7 @@ func TestDependencyDAGGetExecutionLevels(t *testing.T) {
 				"notify":       {"processData", "sendEmail"},
 			},
 			expected: [][]string{
-				{"fetchUser", "fetchOrders"},
+				{"fetchOrders", "fetchUser"},
 				{"createReport"},
 				{"processData", "sendEmail"},
 				{"notify"},
@@ -211,6 +211,20 @@ func TestDependencyDAGGetExecutionLevels(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "fan-out from several roots",
+			dependencies: map[string][]string{
+				"nodeA": {},
+				"nodeB": {},
+				"nodeC": {"nodeA"},
+				"nodeD": {"nodeA"},
+				"nodeE": {"nodeA"},
+				"nodeF": {"nodeB"},
+				"nodeG": {"nodeF", "nodeC"},
+			},
+			expected:    [][]string{{"nodeA", "nodeB"}, {"nodeC", "nodeD", "nodeE", "nodeF"}, {"nodeG"}},
+			expectError: false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -226,10 +240,82 @@ func TestDependencyDAGGetExecutionLevels(t *testing.T) {
 				require.Nil(t, levels)
 			} else {
 				require.NoError(t, err)
-				for i := range len(tc.expected) {
-					require.ElementsMatch(t, tc.expected[i], levels[i])
-				}
+				require.Equal(t, tc.expected, levels)
 			}
 		})
 	}
 }
+
+func TestDependencyDAGCyclePath(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name         string
+		dependencies map[string][]string
+		cycle        string
+	}{
+		{
+			name:         "self dependency",
+			dependencies: map[string][]string{"nodeA": {"nodeA"}},
+			cycle:        "cycle nodeA -> nodeA",
+		},
+		{
+			name: "three nodes",
+			dependencies: map[string][]string{
+				"nodeA": {"nodeC"},
+				"nodeB": {"nodeA"},
+				"nodeC": {"nodeB"},
+			},
+			cycle: "cycle nodeA -> nodeB -> nodeC -> nodeA",
+		},
+		{
+			name: "dependents of a cycle are not part of it",
+			dependencies: map[string][]string{
+				"nodeA": {"nodeD"},
+				"nodeB": {"nodeX"},
+				"nodeC": {"nodeB"},
+				"nodeD": {},
+				"nodeX": {"nodeC", "nodeD"},
+			},
+			cycle: "cycle nodeB -> nodeC -> nodeX -> nodeB",
+		},
+		{
+			name: "path leading into a cycle",
+			dependencies: map[string][]string{
+				"nodeA": {"nodeB"},
+				"nodeB": {"nodeC"},
+				"nodeC": {"nodeD"},
+				"nodeD": {"nodeC"},
+			},
+			cycle: "cycle nodeC -> nodeD -> nodeC",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewDependencyDAG(tc.dependencies).GetExecutionLevels()
+			require.ErrorIs(t, err, errors.ErrCyclicDependency)
+			require.ErrorContains(t, err, tc.cycle+":")
+		})
+	}
+}
+
+func TestDependencyDAGMissingDependencies(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDependencyDAG(map[string][]string{
+		"nodeA": {"ghost1"},
+		"nodeB": {"nodeA"},
+		"nodeC": {"nodeB", "ghost2", "ghost3"},
+	}).GetExecutionLevels()
+	require.ErrorIs(t, err, errors.ErrMissingDependency)
+
+	var joined interface{ Unwrap() []error }
+	require.ErrorAs(t, err, &joined)
+	require.Len(t, joined.Unwrap(), 3)
+	require.Equal(t, `node "nodeA" depends on non-existent node "ghost1": dependency not found
+node "nodeC" depends on non-existent node "ghost2": dependency not found
+node "nodeC" depends on non-existent node "ghost3": dependency not found`, err.Error())
+}
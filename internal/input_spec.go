@@ -1,5 +1,11 @@
 package internal
 
+import (
+	"reflect"
+	"slices"
+	"text/template"
+)
+
 type inputSpecType = int
 
 const (
@@ -8,6 +14,25 @@ const (
 
 	// TaskResultInputSpec defines for task output used as input.
 	TaskResultInputSpec inputSpecType = iota
+
+	// TaskOptionSpec defines a task option passed alongside the inputs.
+	TaskOptionSpec inputSpecType = iota
+
+	// TemplateInputSpec defines a string rendered from a template over task
+	// results and runtime inputs.
+	TemplateInputSpec inputSpecType = iota
+
+	// FieldsInputSpec defines a struct assembled from fields of a task
+	// output.
+	FieldsInputSpec inputSpecType = iota
+
+	// AutoInputSpec defines a task output picked by the parameter type; it
+	// is resolved to a TaskResultInputSpec when the DAG is frozen.
+	AutoInputSpec inputSpecType = iota
+
+	// TaggedInputSpec defines a struct whose fields are bound by their
+	// struct tags to task results and runtime inputs.
+	TaggedInputSpec inputSpecType = iota
 )
 
 // InputSpec specifies how to get input for a task parameter.
@@ -15,7 +40,75 @@ const (
 //
 // Do not create InputSpec instances directly; use the provided helper functions.
 type InputSpec struct {
-	Type   inputSpecType // Type Distinguishes between runtime and task dependency inputs
-	Source string        // Source task ID or runtime key
-	Field  []string      // Field Optional nested field path
+	Type     inputSpecType      // Type Distinguishes between runtime and task dependency inputs
+	Source   string             // Source task ID or runtime key
+	Field    []string           // Field Optional nested field path
+	Option   func(*TaskOptions) // Option Applies a task option, set only for TaskOptionSpec
+	Secret   bool               // Secret Marks the bound value as sensitive
+	Optional bool               // Optional Binds Default when Source holds no value
+	Default  any                // Default Replaces a missing optional value; nil means the zero value
+
+	Template *template.Template // Template Renders the value, set only for TemplateInputSpec
+	Refs     []string           // Refs Task IDs or runtime keys read by Template
+	Err      error              // Err Reports an invalid spec, returned by NewTask
+
+	Projection map[string][]string // Projection Maps parameter fields to Source field paths, set only for FieldsInputSpec
+
+	Bindings []FieldBinding // Bindings Binds parameter fields to inputs, set by NewTask only for TaggedInputSpec
+}
+
+// FieldBinding binds a field of a struct parameter to the input read by
+// Spec, a TaskResultInputSpec or RuntimeInputSpec.
+type FieldBinding struct {
+	Name string
+	Spec InputSpec
+}
+
+// NewOptionSpec wraps a task option so it can be passed to lyra.Do()
+// together with the input specs.
+func NewOptionSpec(apply func(*TaskOptions)) InputSpec {
+	return InputSpec{
+		Type:   TaskOptionSpec,
+		Option: apply,
+	}
+}
+
+// PadAutoSpecs appends an AutoInputSpec for every trailing parameter of fn
+// that has no input spec. Invalid functions are left to NewTask to report.
+func PadAutoSpecs(fn any, specs []InputSpec) []InputSpec {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return specs
+	}
+	inputs := 0
+	for _, spec := range specs {
+		if spec.Type != TaskOptionSpec {
+			inputs++
+		}
+	}
+	if inputs >= fnType.NumIn()-1 {
+		return specs
+	}
+	padded := slices.Clone(specs)
+	for range fnType.NumIn() - 1 - inputs {
+		padded = append(padded, InputSpec{Type: AutoInputSpec})
+	}
+	return padded
+}
+
+// splitSpecs separates the task options from the input specs, keeping the
+// order of the input specs, and applies the options.
+func splitSpecs(specs []InputSpec) ([]InputSpec, TaskOptions) {
+	var options TaskOptions
+	inputs := make([]InputSpec, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Type != TaskOptionSpec {
+			inputs = append(inputs, spec)
+			continue
+		}
+		if spec.Option != nil {
+			spec.Option(&options)
+		}
+	}
+	return inputs, options
 }
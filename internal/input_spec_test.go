@@ -1 +1,53 @@
 package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSpecs(t *testing.T) {
+	t.Parallel()
+
+	user := InputSpec{Type: TaskResultInputSpec, Source: "fetchUser"}
+	userID := InputSpec{Type: RuntimeInputSpec, Source: "userID"}
+	priority := func(n int) InputSpec {
+		return NewOptionSpec(func(o *TaskOptions) { o.Priority = n })
+	}
+
+	tcs := []struct {
+		name            string
+		specs           []InputSpec
+		expectedInputs  []InputSpec
+		expectedOptions TaskOptions
+	}{
+		{
+			name:           "nil specs",
+			specs:          nil,
+			expectedInputs: []InputSpec{},
+		},
+		{
+			name:           "only inputs",
+			specs:          []InputSpec{user, userID},
+			expectedInputs: []InputSpec{user, userID},
+		},
+		{
+			name:            "options mixed with inputs",
+			specs:           []InputSpec{priority(1), user, priority(5), userID},
+			expectedInputs:  []InputSpec{user, userID},
+			expectedOptions: TaskOptions{Priority: 5},
+		},
+		{
+			name:           "nil option",
+			specs:          []InputSpec{{Type: TaskOptionSpec}, user},
+			expectedInputs: []InputSpec{user},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			inputs, options := splitSpecs(tc.specs)
+			require.Equal(t, tc.expectedInputs, inputs)
+			require.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
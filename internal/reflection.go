@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/sourabh-kumar2/lyra/errors"
 )
@@ -21,12 +22,23 @@ var (
 	contextInterface = reflect.TypeOf((*context.Context)(nil)).Elem()
 )
 
+// signatureCache holds the functionInfo of every valid function type seen so
+// far. The analysis only depends on the type, so functions sharing a type,
+// such as one function registered under many task IDs, are analyzed once.
+// The number of function types in a program is bounded, so is the cache.
+var signatureCache sync.Map // map[reflect.Type]*functionInfo
+
+// analyzeFunctionSignature validates fn and describes its parameters and
+// result. The returned functionInfo is shared and must not be modified.
 func analyzeFunctionSignature(fn any) (*functionInfo, error) {
 	if fn == nil {
 		return nil, errors.ErrMustBeFunction
 	}
 
 	fnType := reflect.TypeOf(fn)
+	if info, ok := signatureCache.Load(fnType); ok {
+		return info.(*functionInfo), nil //nolint:forcetypeassert // only *functionInfo is stored
+	}
 
 	err := validateFunction(fnType)
 	if err != nil {
@@ -43,10 +55,11 @@ func analyzeFunctionSignature(fn any) (*functionInfo, error) {
 		outputType = fnType.Out(0)
 	}
 
-	return &functionInfo{
+	info, _ := signatureCache.LoadOrStore(fnType, &functionInfo{
 		inputTypes: inputTypes,
 		outputType: outputType,
-	}, nil
+	})
+	return info.(*functionInfo), nil //nolint:forcetypeassert // only *functionInfo is stored
 }
 
 func validateFunction(fnType reflect.Type) error {
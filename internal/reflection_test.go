@@ -315,6 +315,28 @@ func TestFunctionInfoStructure(t *testing.T) {
 	require.Equal(t, expectedOutput, fnInfo.outputType)
 }
 
+func TestAnalyzeFunctionSignatureCache(t *testing.T) {
+	t.Parallel()
+
+	type cacheInput struct{ ID int }
+	first := func(ctx context.Context, in cacheInput) (int, error) { return in.ID, nil }
+	second := func(ctx context.Context, in cacheInput) (int, error) { return 0, nil }
+
+	firstInfo, err := analyzeFunctionSignature(first)
+	require.NoError(t, err)
+	secondInfo, err := analyzeFunctionSignature(second)
+	require.NoError(t, err)
+	require.Same(t, firstInfo, secondInfo, "functions of the same type share the analysis")
+
+	invalid := func(in cacheInput) error { return nil }
+	for range 2 {
+		_, err = analyzeFunctionSignature(invalid)
+		require.ErrorIs(t, err, errors.ErrFirstParamMustBeContext)
+	}
+	_, cached := signatureCache.Load(reflect.TypeOf(invalid))
+	require.False(t, cached, "invalid signatures are not cached")
+}
+
 // Benchmark tests for performance analysis.
 func BenchmarkAnalyzeFunctionSignatureSimple(b *testing.B) {
 	fn := func(ctx context.Context, id string) (User, error) {
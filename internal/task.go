@@ -15,6 +15,7 @@ type Task struct {
 	fn         any
 	fnInfo     *functionInfo
 	inputSpecs []InputSpec
+	options    TaskOptions
 }
 
 // NewTask creates a task node with validation.
@@ -23,11 +24,16 @@ type Task struct {
 //   - The function signature is valid
 //   - The number of input specs matches function parameters
 //
+// Specs of type TaskOptionSpec are applied to the task options and do not
+// count as inputs.
+//
 // Returns an error if validation fails.
-func NewTask(id string, fn any, inputSpecs []InputSpec) (*Task, error) {
+func NewTask(id string, fn any, specs []InputSpec) (*Task, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, errors.ErrTaskIDCannotBeEmpty
 	}
+	inputSpecs, options := splitSpecs(specs)
+
 	fnInfo, err := analyzeFunctionSignature(fn)
 	if err != nil {
 		return nil, fmt.Errorf("invalid function for task %q: %w", id, err)
@@ -41,27 +47,91 @@ func NewTask(id string, fn any, inputSpecs []InputSpec) (*Task, error) {
 			len(inputSpecs)+1,
 		)
 	}
+	for i, spec := range inputSpecs {
+		if spec.Err != nil {
+			return nil, fmt.Errorf("invalid input spec %d for task %q: %w", i+2, id, spec.Err)
+		}
+		if err := checkOptional(spec, fnInfo.inputTypes[i+1]); err != nil {
+			return nil, errors.Wrapf(err, "parameter %d of task %q", i+2, id)
+		}
+		if spec.Type == TemplateInputSpec && !stringType.AssignableTo(fnInfo.inputTypes[i+1]) {
+			return nil, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"parameter %d of task %q must accept a string to bind a template, got %s",
+				i+2,
+				id,
+				fnInfo.inputTypes[i+1],
+			)
+		}
+		if spec.Type == FieldsInputSpec {
+			if err := checkProjection(spec.Projection, fnInfo.inputTypes[i+1]); err != nil {
+				return nil, errors.Wrapf(err, "parameter %d of task %q", i+2, id)
+			}
+		}
+		if spec.Type == TaggedInputSpec {
+			bindings, err := tagBindings(fnInfo.inputTypes[i+1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parameter %d of task %q", i+2, id)
+			}
+			inputSpecs[i].Bindings = bindings
+			for _, binding := range bindings {
+				inputSpecs[i].Refs = append(inputSpecs[i].Refs, binding.Spec.Source)
+			}
+		}
+	}
+	for i, fallback := range options.Fallbacks {
+		if typ := reflect.TypeOf(fallback); typ != reflect.TypeOf(fn) {
+			return nil, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"fallback %d of task %q is %v, want %T",
+				i+1,
+				id,
+				typ,
+				fn,
+			)
+		}
+	}
 	return &Task{
 		id:         id,
 		fn:         fn,
 		inputSpecs: inputSpecs,
 		fnInfo:     fnInfo,
+		options:    options,
 	}, nil
 }
 
+// stringType is the type of the values bound by TemplateInputSpec.
+var stringType = reflect.TypeOf("")
+
 // GetDependencies returns the task IDs that this task depends on.
-// Only returns dependencies from TaskResultInputSpec types (lyra.Use() calls),
-// not runtime inputs (lyra.UseRun() calls).
+// Only returns dependencies from TaskResultInputSpec and FieldsInputSpec types
+// (lyra.Use() and lyra.UseFields() calls) and the task fields of
+// TaggedInputSpec, not runtime inputs (lyra.UseRun() calls) or templates.
 func (t *Task) GetDependencies() []string {
 	deps := make([]string, 0)
 	for _, spec := range t.inputSpecs {
-		if spec.Type == TaskResultInputSpec {
+		switch spec.Type {
+		case TaskResultInputSpec, FieldsInputSpec:
 			deps = append(deps, spec.Source)
+		case TaggedInputSpec:
+			for _, binding := range spec.Bindings {
+				if binding.Spec.Type == TaskResultInputSpec {
+					deps = append(deps, binding.Spec.Source)
+				}
+			}
 		}
 	}
 	return deps
 }
 
+// WithInputSpecs returns a copy of the task reading its parameters with
+// specs instead, which must match the parameters of the task.
+func (t *Task) WithInputSpecs(specs []InputSpec) *Task {
+	task := *t
+	task.inputSpecs = specs
+	return &task
+}
+
 // GetInputParams returns the input specifications and parameter types for this task.
 // Used internally during execution to resolve parameter values.
 func (t *Task) GetInputParams() (specs []InputSpec, types []reflect.Type) {
@@ -84,3 +154,112 @@ func (t *Task) GetOutputParams() reflect.Type {
 func (t *Task) GetID() string {
 	return t.id
 }
+
+// GetOptions returns the options the task was registered with.
+func (t *Task) GetOptions() TaskOptions {
+	return t.options
+}
+
+// checkOptional checks that an optional spec reads a single source and that
+// its default fits typ.
+func checkOptional(spec InputSpec, typ reflect.Type) error {
+	if !spec.Optional {
+		return nil
+	}
+	if spec.Type == TemplateInputSpec || spec.Type == TaggedInputSpec {
+		return errors.Wrapf(errors.ErrInvalidParamType, "templates and tagged structs cannot be optional")
+	}
+	if spec.Default != nil && !reflect.TypeOf(spec.Default).AssignableTo(typ) {
+		return errors.Wrapf(
+			errors.ErrInvalidParamType,
+			"default of type %s is not assignable to %s",
+			reflect.TypeOf(spec.Default),
+			typ,
+		)
+	}
+	return nil
+}
+
+// checkProjection checks that typ is a struct, or a pointer to one, with an
+// exported field for every key of projection.
+func checkProjection(projection map[string][]string, typ reflect.Type) error {
+	structType := typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errors.Wrapf(errors.ErrInvalidParamType, "UseFields needs a struct parameter, got %s", typ)
+	}
+	for name := range projection {
+		field, ok := structType.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return errors.Wrapf(errors.ErrInvalidParamType, "%s has no exported field %q", typ, name)
+		}
+	}
+	return nil
+}
+
+const (
+	// resultTag binds a struct field to a task result, for example
+	// `lyra:"fetchUser.Name"`.
+	resultTag = "lyra"
+
+	// runTag binds a struct field to a runtime input, for example
+	// `lyrarun:"userID"`.
+	runTag = "lyrarun"
+)
+
+// tagBindings returns the bindings of the tagged fields of typ, a struct or
+// a pointer to one. Untagged fields are not bound.
+func tagBindings(typ reflect.Type) ([]FieldBinding, error) {
+	structType := typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, errors.Wrapf(errors.ErrInvalidParamType, "UseTags needs a struct parameter, got %s", typ)
+	}
+
+	var bindings []FieldBinding
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		result, hasResult := field.Tag.Lookup(resultTag)
+		run, hasRun := field.Tag.Lookup(runTag)
+		if !hasResult && !hasRun {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, errors.Wrapf(errors.ErrInvalidParamType, "field %s of %s is not exported", field.Name, typ)
+		}
+		if hasResult && hasRun {
+			return nil, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"field %s of %s has both %s and %s tags",
+				field.Name,
+				typ,
+				resultTag,
+				runTag,
+			)
+		}
+
+		spec := InputSpec{Type: TaskResultInputSpec}
+		path := result
+		if hasRun {
+			spec.Type = RuntimeInputSpec
+			path = run
+		}
+		source, fields, nested := strings.Cut(path, ".")
+		spec.Source = source
+		if nested {
+			spec.Field = strings.Split(fields, ".")
+		}
+		if spec.Source == "" {
+			return nil, errors.Wrapf(errors.ErrInvalidParamType, "field %s of %s has an empty tag", field.Name, typ)
+		}
+		bindings = append(bindings, FieldBinding{Name: field.Name, Spec: spec})
+	}
+	if len(bindings) == 0 {
+		return nil, errors.Wrapf(errors.ErrInvalidParamType, "%s has no tagged fields", typ)
+	}
+	return bindings, nil
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// TaskOptions holds the per-task configuration set by task options passed
+// to lyra.Do().
+type TaskOptions struct {
+	// Priority orders tasks that are ready at the same time; higher runs first.
+	Priority int
+
+	// Retries is the number of times a failed task is attempted again.
+	Retries int
+
+	// Timeout bounds every attempt of the task; zero means no limit.
+	Timeout time.Duration
+
+	// PanicPolicy is the lyra.PanicPolicy applied when the task panics.
+	PanicPolicy int
+
+	// Backoff returns the delay after a failed attempt; nil attempts the
+	// task again right away.
+	Backoff func(attempt int) time.Duration
+
+	// HedgePercentile is the percentile of past durations after which a
+	// second execution of the task is started; zero disables hedging.
+	HedgePercentile float64
+
+	// HedgeFallback is the hedge delay used while no durations are known.
+	HedgeFallback time.Duration
+
+	// Executor executes the task instead of its function when set.
+	Executor func(ctx context.Context, taskID string, args []any, outputType reflect.Type) (any, error)
+
+	// SecretOutput marks the task result as sensitive.
+	SecretOutput bool
+
+	// LockKey is the lock held while the task runs; empty means none.
+	LockKey string
+
+	// Bulkhead limits the executions of the task running at the same time
+	// across runs; zero means no limit.
+	Bulkhead int
+
+	// Quorum is the number of task results read by the task that must be
+	// available for it to run; zero requires all of them. QuorumSet
+	// reports whether it was set, to reject invalid quorums.
+	Quorum    int
+	QuorumSet bool
+
+	// Fallbacks are functions with the signature of the task called in
+	// order when it fails, until one succeeds.
+	Fallbacks []any
+
+	// Fallback returns the output stored when the task fails; nil fails
+	// the task.
+	Fallback func(ctx context.Context, err error) (any, error)
+
+	// MaxOutputSize bounds the size of the task's result in bytes; zero
+	// uses the limit of the run.
+	MaxOutputSize int64
+
+	// Critical tasks fail the run even when failures are allowed.
+	Critical bool
+
+	// Group is the failure domain of the task; empty means none.
+	Group string
+
+	// Tags and Meta describe the task to tooling; they do not affect
+	// execution.
+	Tags []string
+	Meta map[string]string
+
+	// Description documents the task in exports.
+	Description string
+
+	// Kind classifies the task for per-kind limits, see lyra.TaskKind.
+	Kind int
+
+	// SoftDeadline is the running time after which OnSoftDeadline is
+	// called, without canceling the task; zero disables it.
+	SoftDeadline   time.Duration
+	OnSoftDeadline func(ctx context.Context, elapsed time.Duration)
+
+	// OnStart and OnEnd are called around the execution of the task.
+	OnStart func(ctx context.Context)
+	OnEnd   func(ctx context.Context, output any, err error)
+
+	// CleanupTimeout is how long the context returned by
+	// lyra.CleanupContext outlives the task's context; zero means it does
+	// not.
+	CleanupTimeout time.Duration
+
+	// IdempotencyKey derives the key under which a successful execution
+	// of the task is recorded from its inputs; nil disables recording.
+	IdempotencyKey func(ctx context.Context, inputs []any) string
+}
@@ -287,3 +287,24 @@ func TestGetID(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, taskID, task.GetID())
 }
+
+func TestGetOptions(t *testing.T) {
+	t.Parallel()
+
+	task, err := NewTask(
+		"id",
+		func(ctx context.Context, userID string) error { return nil },
+		[]InputSpec{
+			NewOptionSpec(func(o *TaskOptions) { o.Priority = 3 }),
+			{
+				Type:   RuntimeInputSpec,
+				Source: "userID",
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, TaskOptions{Priority: 3}, task.GetOptions())
+
+	specs, _ := task.GetInputParams()
+	require.Len(t, specs, 1, "options do not count as inputs")
+}
@@ -0,0 +1,140 @@
+package lyra
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// TaskKind classifies a task by the resource it mostly waits for, so the
+// executor can limit each kind separately.
+type TaskKind int
+
+const (
+	// UnknownBound is the kind of tasks registered without WithKind; they
+	// are only subject to the run-wide limits.
+	UnknownBound TaskKind = iota
+	// CPUBound tasks mostly compute. By default at most runtime.GOMAXPROCS
+	// of them execute at the same time.
+	CPUBound
+	// IOBound tasks mostly wait on the network or disk. They are not
+	// limited by default.
+	IOBound
+)
+
+// String returns the name of the kind.
+func (k TaskKind) String() string {
+	switch k {
+	case CPUBound:
+		return "cpu"
+	case IOBound:
+		return "io"
+	default:
+		return "unknown"
+	}
+}
+
+// WithKind declares the kind of the task. Tasks of each kind are limited
+// separately (see WithKindLimit), so CPU-heavy tasks queue up among
+// themselves instead of starving quick IO-bound fan-outs of the same stage.
+//
+// Example:
+//
+//	l.Do("resize", resize, lyra.Use("download"), lyra.WithKind(lyra.CPUBound))
+func WithKind(kind TaskKind) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Kind = int(kind)
+	})
+}
+
+// WithKindLimit limits the number of tasks of kind executing at the same
+// time within a stage, replacing its default. A non-positive n removes the
+// limit. Tasks of a kind at its limit are passed over for later tasks of
+// other kinds, so they never hold up the rest of the stage.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs, lyra.WithKindLimit(lyra.IOBound, 100))
+func WithKindLimit(kind TaskKind, n int) RunOption {
+	return func(cfg *runConfig) {
+		if cfg.kindLimits == nil {
+			cfg.kindLimits = defaultKindLimits()
+		}
+		cfg.kindLimits[kind] = n
+	}
+}
+
+// defaultKindLimits returns the limits applied to tasks of each kind unless
+// changed with WithKindLimit.
+func defaultKindLimits() map[TaskKind]int {
+	return map[TaskKind]int{CPUBound: runtime.GOMAXPROCS(0)}
+}
+
+// stageQueue hands out the tasks of a stage in dispatch order to a pool of
+// workers, passing over tasks whose kind is at its limit.
+type stageQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []string
+	kinds   map[string]TaskKind
+	limits  map[TaskKind]int
+	running map[TaskKind]int
+}
+
+func newStageQueue(stage []string, tasks map[string]*compiledTask, limits map[TaskKind]int) *stageQueue {
+	q := &stageQueue{
+		pending: append([]string(nil), stage...),
+		kinds:   make(map[string]TaskKind, len(stage)),
+		limits:  limits,
+		running: make(map[TaskKind]int),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	for _, taskID := range stage {
+		if task, ok := tasks[taskID]; ok {
+			q.kinds[taskID] = TaskKind(task.GetOptions().Kind)
+		}
+	}
+	return q
+}
+
+// next returns the first pending task whose kind is below its limit,
+// waiting for running tasks to finish if there is none. ok is false once no
+// task is pending.
+func (q *stageQueue) next() (taskID string, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pending) > 0 {
+		for i, id := range q.pending {
+			kind := q.kinds[id]
+			if limit := q.limits[kind]; limit > 0 && q.running[kind] >= limit {
+				continue
+			}
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.running[kind]++
+			return id, true
+		}
+		q.cond.Wait()
+	}
+	return "", false
+}
+
+// done records that a task returned by next finished.
+func (q *stageQueue) done(taskID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running[q.kinds[taskID]]--
+	q.cond.Broadcast()
+}
+
+// limited reports whether the limit of any kind applies to the stage.
+func (q *stageQueue) limited() bool {
+	for _, kind := range q.kinds {
+		if q.limits[kind] > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithKindLimits(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu         sync.Mutex
+		cpuRunning int
+		cpuPeak    int
+		ioPending  sync.WaitGroup
+	)
+	ioDone := make(chan struct{})
+
+	// CPU-bound tasks are dispatched first and wait for every IO-bound task,
+	// which only works if they do not take up the workers the IO-bound
+	// tasks need.
+	l := New()
+	for i := range 3 {
+		l.Do(fmt.Sprintf("cpu%d", i), func(ctx context.Context) error {
+			mu.Lock()
+			cpuRunning++
+			cpuPeak = max(cpuPeak, cpuRunning)
+			mu.Unlock()
+			select {
+			case <-ioDone:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			mu.Lock()
+			cpuRunning--
+			mu.Unlock()
+			return nil
+		}, WithKind(CPUBound), WithPriority(1))
+	}
+	for i := range 5 {
+		ioPending.Add(1)
+		l.Do(fmt.Sprintf("io%d", i), func(ctx context.Context) error {
+			ioPending.Done()
+			return nil
+		}, WithKind(IOBound))
+	}
+	go func() {
+		ioPending.Wait()
+		close(ioDone)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := l.Run(ctx, nil, WithStageWorkers(2), WithKindLimit(CPUBound, 1))
+
+	require.NoError(t, err)
+	require.Equal(t, 1, cpuPeak)
+}
+
+func TestStageQueue(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("cpu1", func(ctx context.Context) error { return nil }, WithKind(CPUBound)).
+		Do("cpu2", func(ctx context.Context) error { return nil }, WithKind(CPUBound)).
+		Do("io", func(ctx context.Context) error { return nil }, WithKind(IOBound)).
+		Do("other", func(ctx context.Context) error { return nil })
+	snapshot, err := l.freeze()
+	require.NoError(t, err)
+
+	queue := newStageQueue([]string{"cpu1", "cpu2", "io", "other"}, snapshot.tasks, map[TaskKind]int{CPUBound: 1})
+	require.True(t, queue.limited())
+
+	var order []string
+	for range 3 {
+		id, ok := queue.next()
+		require.True(t, ok)
+		order = append(order, id)
+	}
+	require.Equal(t, []string{"cpu1", "io", "other"}, order)
+
+	queue.done("cpu1")
+	id, ok := queue.next()
+	require.True(t, ok)
+	require.Equal(t, "cpu2", id)
+
+	_, ok = queue.next()
+	require.False(t, ok)
+}
+
+func TestTaskKindString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "cpu", CPUBound.String())
+	require.Equal(t, "io", IOBound.String())
+	require.Equal(t, "unknown", UnknownBound.String())
+}
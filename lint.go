@@ -0,0 +1,224 @@
+package lyra
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// LintRule identifies a structural smell reported by Lyra.Lint.
+type LintRule string
+
+const (
+	// LintUnusedOutput reports tasks whose result is never consumed by
+	// another task and is not in the requested outputs.
+	LintUnusedOutput LintRule = "unused-output"
+	// LintUndocumentedInput reports runtime inputs referenced with UseRun
+	// that were neither described with Lyra.DescribeInput nor declared with
+	// Lyra.Require.
+	LintUndocumentedInput LintRule = "undocumented-input"
+	// LintDuplicateEdge reports tasks that bind the same source and field
+	// path to more than one parameter.
+	LintDuplicateEdge LintRule = "duplicate-edge"
+	// LintHighFanIn reports tasks depending on more distinct tasks than the
+	// configured maximum.
+	LintHighFanIn LintRule = "high-fan-in"
+)
+
+// defaultMaxFanIn is the fan-in above which LintHighFanIn is reported.
+const defaultMaxFanIn = 10
+
+// LintIssue is a single finding of Lyra.Lint.
+type LintIssue struct {
+	Rule    LintRule
+	TaskID  string // Task the issue is about
+	Message string
+}
+
+// String returns the issue formatted as "rule: task: message".
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Rule, i.TaskID, i.Message)
+}
+
+// LintOption configures Lyra.Lint.
+type LintOption func(*lintConfig)
+
+type lintConfig struct {
+	requested map[string]struct{}
+	maxFanIn  int
+}
+
+// LintRequested lists the task outputs the caller reads from the Result.
+// Only when it is given does Lint report LintUnusedOutput, for every task
+// that produces a result no other task consumes and that is not listed.
+func LintRequested(taskIDs ...string) LintOption {
+	return func(cfg *lintConfig) {
+		if cfg.requested == nil {
+			cfg.requested = make(map[string]struct{}, len(taskIDs))
+		}
+		for _, taskID := range taskIDs {
+			cfg.requested[taskID] = struct{}{}
+		}
+	}
+}
+
+// LintMaxFanIn sets the number of distinct dependencies a task may have
+// before LintHighFanIn is reported. The default is 10.
+func LintMaxFanIn(n int) LintOption {
+	return func(cfg *lintConfig) {
+		cfg.maxFanIn = n
+	}
+}
+
+// DescribeInput documents a runtime input key expected by UseRun specs.
+// Undocumented keys are reported by Lint.
+//
+// Returns the same Lyra instance for method chaining.
+func (l *Lyra) DescribeInput(key, description string) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inputDocs == nil {
+		l.inputDocs = make(map[string]string)
+	}
+	l.inputDocs[key] = description
+	return l
+}
+
+// Lint inspects the DAG for structural smells that are legal but make large
+// DAGs harder to maintain: unused outputs, undocumented runtime inputs,
+// duplicate edges and high fan-in.
+//
+// Lint does not validate the DAG; use Run for that. Issues are sorted by
+// rule and task ID.
+//
+// Example:
+//
+//	for _, issue := range l.Lint(lyra.LintRequested("generateReport")) {
+//		log.Println(issue)
+//	}
+func (l *Lyra) Lint(opts ...LintOption) []LintIssue {
+	cfg := &lintConfig{maxFanIn: defaultMaxFanIn}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	consumed := make(map[string]struct{}, len(l.tasks))
+	issues := make([]LintIssue, 0)
+	for taskID, task := range l.tasks {
+		specs, _ := task.GetInputParams()
+		for _, spec := range specs {
+			for _, dep := range specDependencies(spec, l.tasks) {
+				consumed[dep] = struct{}{}
+			}
+		}
+		issues = append(issues, l.lintTaskInputs(taskID, specs, cfg)...)
+	}
+
+	if cfg.requested != nil {
+		for taskID, task := range l.tasks {
+			_, isConsumed := consumed[taskID]
+			_, isRequested := cfg.requested[taskID]
+			if task.GetOutputParams() != nil && !isConsumed && !isRequested {
+				issues = append(issues, LintIssue{
+					Rule:    LintUnusedOutput,
+					TaskID:  taskID,
+					Message: "result is never consumed or requested",
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(issues, func(a, b LintIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.TaskID, b.TaskID),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return issues
+}
+
+// lintTaskInputs checks the input specs of a single task. Callers must hold l.mu.
+func (l *Lyra) lintTaskInputs(taskID string, specs []internal.InputSpec, cfg *lintConfig) []LintIssue {
+	var issues []LintIssue
+
+	type edgeKey struct {
+		kind int
+		path string
+	}
+	seen := make(map[edgeKey]struct{}, len(specs))
+	deps := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		if spec.Type == internal.AutoInputSpec {
+			continue // bound when the DAG is frozen
+		}
+		if spec.Type == internal.TaggedInputSpec {
+			for _, binding := range spec.Bindings {
+				if binding.Spec.Type == internal.RuntimeInputSpec {
+					issues = append(issues, l.lintRuntimeInput(taskID, binding.Spec.Source)...)
+				} else {
+					deps[binding.Spec.Source] = struct{}{}
+				}
+			}
+			continue
+		}
+		if spec.Type == internal.TemplateInputSpec {
+			for _, ref := range spec.Refs {
+				if _, ok := l.tasks[ref]; ok {
+					deps[ref] = struct{}{}
+				} else {
+					issues = append(issues, l.lintRuntimeInput(taskID, ref)...)
+				}
+			}
+			continue
+		}
+		if spec.Type == internal.RuntimeInputSpec {
+			issues = append(issues, l.lintRuntimeInput(taskID, spec.Source)...)
+		} else {
+			deps[spec.Source] = struct{}{}
+		}
+
+		path := append([]string{spec.Source}, spec.Field...)
+		edge := strings.Join(slices.DeleteFunc(path, func(f string) bool { return f == "" }), ".")
+		key := edgeKey{kind: spec.Type, path: edge}
+		if _, dup := seen[key]; dup {
+			issues = append(issues, LintIssue{
+				Rule:    LintDuplicateEdge,
+				TaskID:  taskID,
+				Message: fmt.Sprintf("%q is bound to more than one parameter", edge),
+			})
+		}
+		seen[key] = struct{}{}
+	}
+
+	if len(deps) > cfg.maxFanIn {
+		issues = append(issues, LintIssue{
+			Rule:    LintHighFanIn,
+			TaskID:  taskID,
+			Message: fmt.Sprintf("depends on %d tasks (max %d)", len(deps), cfg.maxFanIn),
+		})
+	}
+	return issues
+}
+
+// lintRuntimeInput checks a runtime input read by a task. Callers must hold
+// l.mu.
+func (l *Lyra) lintRuntimeInput(taskID, key string) []LintIssue {
+	_, described := l.inputDocs[key]
+	_, declared := l.required[key]
+	if described || declared {
+		return nil
+	}
+	return []LintIssue{{
+		Rule:    LintUndocumentedInput,
+		TaskID:  taskID,
+		Message: fmt.Sprintf("runtime input %q is not documented", key),
+	}}
+}
@@ -0,0 +1,167 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	produce := func(ctx context.Context) (int, error) { return 1, nil }
+	consume := func(ctx context.Context, a, b int) (int, error) { return a + b, nil }
+
+	tcs := []struct {
+		name     string
+		build    func() *Lyra
+		opts     []LintOption
+		expected []LintIssue
+	}{
+		{
+			name:     "empty dag",
+			build:    New,
+			expected: []LintIssue{},
+		},
+		{
+			name: "clean dag",
+			build: func() *Lyra {
+				return New().
+					DescribeInput("seed", "initial value").
+					Do("source", func(ctx context.Context, seed int) (int, error) {
+						return seed, nil
+					}, UseRun("seed")).
+					Do("other", produce).
+					Do("sink", consume, Use("source"), Use("other"))
+			},
+			opts:     []LintOption{LintRequested("sink")},
+			expected: []LintIssue{},
+		},
+		{
+			name: "unused output only reported with requested outputs",
+			build: func() *Lyra {
+				return New().Do("orphan", produce)
+			},
+			expected: []LintIssue{},
+		},
+		{
+			name: "unused output",
+			build: func() *Lyra {
+				return New().
+					Do("orphan", produce).
+					Do("sideEffect", func(ctx context.Context) error { return nil }).
+					Do("wanted", produce)
+			},
+			opts: []LintOption{LintRequested("wanted")},
+			expected: []LintIssue{
+				{Rule: LintUnusedOutput, TaskID: "orphan", Message: "result is never consumed or requested"},
+			},
+		},
+		{
+			name: "undocumented input",
+			build: func() *Lyra {
+				return New().
+					DescribeInput("a", "documented").
+					Require("c", nil).
+					Do("identity", func(ctx context.Context, c int) (int, error) {
+						return c, nil
+					}, UseRun("c")).
+					Do("sum", func(ctx context.Context, a, b int) (int, error) {
+						return a + b, nil
+					}, UseRun("a"), UseRun("b"))
+			},
+			expected: []LintIssue{
+				{Rule: LintUndocumentedInput, TaskID: "sum", Message: `runtime input "b" is not documented`},
+			},
+		},
+		{
+			name: "duplicate edge",
+			build: func() *Lyra {
+				return New().
+					Do("source", produce).
+					Do("sum", consume, Use("source"), Use("source"))
+			},
+			expected: []LintIssue{
+				{Rule: LintDuplicateEdge, TaskID: "sum", Message: `"source" is bound to more than one parameter`},
+			},
+		},
+		{
+			name: "equivalent field paths",
+			build: func() *Lyra {
+				return New().
+					Do("user", func(ctx context.Context) (User, error) { return User{}, nil }).
+					Do("names", func(ctx context.Context, a, b string) (string, error) {
+						return a + b, nil
+					}, Use("user", "Name"), Use("user", "", "Name"))
+			},
+			expected: []LintIssue{
+				{Rule: LintDuplicateEdge, TaskID: "names", Message: `"user.Name" is bound to more than one parameter`},
+			},
+		},
+		{
+			name: "same key from run inputs and task is not a duplicate",
+			build: func() *Lyra {
+				return New().
+					DescribeInput("source", "").
+					Do("source", produce).
+					Do("sum", consume, Use("source"), UseRun("source"))
+			},
+			expected: []LintIssue{},
+		},
+		{
+			name: "high fan-in",
+			build: func() *Lyra {
+				return New().
+					Do("a", produce).
+					Do("b", produce).
+					Do("sum", consume, Use("a"), Use("b"))
+			},
+			opts: []LintOption{LintMaxFanIn(1)},
+			expected: []LintIssue{
+				{Rule: LintHighFanIn, TaskID: "sum", Message: "depends on 2 tasks (max 1)"},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.build().Lint(tc.opts...))
+		})
+	}
+}
+
+func TestLintDefaultMaxFanIn(t *testing.T) {
+	t.Parallel()
+
+	build := func(sources int) *Lyra {
+		l := New()
+		specs := make([]TaskOption, 0, defaultMaxFanIn+1)
+		for i := range defaultMaxFanIn + 1 {
+			taskID := fmt.Sprintf("source-%d", i%sources)
+			if i < sources {
+				l.Do(taskID, func(ctx context.Context) (int, error) { return i, nil })
+			}
+			specs = append(specs, Use(taskID))
+		}
+		return l.Do("sink", func(ctx context.Context, a, b, c, d, e, f, g, h, i, j, k int) error {
+			return nil
+		}, specs...)
+	}
+
+	issues := build(defaultMaxFanIn + 1).Lint()
+	require.Len(t, issues, 1)
+	require.Equal(t, LintHighFanIn, issues[0].Rule)
+
+	// Fan-in counts distinct tasks, not parameters.
+	for _, issue := range build(defaultMaxFanIn).Lint() {
+		require.NotEqual(t, LintHighFanIn, issue.Rule)
+	}
+}
+
+func TestLintIssueString(t *testing.T) {
+	t.Parallel()
+
+	issue := LintIssue{Rule: LintHighFanIn, TaskID: "sum", Message: "depends on 2 tasks (max 1)"}
+	require.Equal(t, "high-fan-in: sum: depends on 2 tasks (max 1)", issue.String())
+}
@@ -0,0 +1,35 @@
+package lyra
+
+// Logger receives the lifecycle events of runs as log lines. Each call
+// carries a message followed by alternating keys and values, in the style
+// of log/slog, so a *slog.Logger can be used directly. Adapters for zap and
+// logrus live in the zaplog and logruslog packages.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// logEvent writes e to logger. Failures are logged as errors, retries,
+// hedges, cancellations and overdue tasks as info, and everything else as
+// debug.
+func logEvent(logger Logger, e Event) {
+	keysAndValues := []any{"run_id", e.RunID, "stage", e.Stage}
+	if e.TaskID != "" {
+		keysAndValues = append(keysAndValues, "task_id", e.TaskID)
+	}
+	if e.Err != nil {
+		keysAndValues = append(keysAndValues, "error", e.Err)
+	}
+
+	msg := "lyra: " + e.Type.String()
+	switch {
+	case e.Type == EventTaskFailed, e.Type == EventStageFinished && e.Err != nil:
+		logger.Error(msg, keysAndValues...)
+	case e.Type == EventTaskRetrying, e.Type == EventTaskHedged, e.Type == EventTaskCanceled,
+		e.Type == EventTaskOverdue:
+		logger.Info(msg, keysAndValues...)
+	default:
+		logger.Debug(msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,34 @@
+package lyra
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	l := New().
+		Do("ok", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("fail", func(ctx context.Context, _ int) error {
+			return errTaskFailed
+		}, Use("ok"), WithRetry(1))
+
+	_, err := l.Run(context.Background(), nil, WithRunID("run-1"), WithLogger(logger))
+	require.ErrorIs(t, err, errTaskFailed)
+
+	out := buf.String()
+	require.NotContains(t, out, "task_finished", "debug events are below the level")
+	require.Contains(t, out, `level=INFO msg="lyra: task_retrying" run_id=run-1 stage=1 task_id=fail error="task failed"`)
+	require.Contains(t, out, `level=ERROR msg="lyra: task_failed" run_id=run-1 stage=1 task_id=fail`)
+	require.Contains(t, out, `level=ERROR msg="lyra: stage_finished" run_id=run-1 stage=1 error=`)
+}
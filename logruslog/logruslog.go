@@ -0,0 +1,61 @@
+// Package logruslog adapts a logrus logger to lyra.Logger without making
+// lyra depend on logrus.
+//
+// Keys and values are appended to the message as key=value pairs, since
+// logrus fields cannot be attached without importing logrus.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithLogger(logruslog.New(logrus.StandardLogger())))
+package logruslog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// Printer is the subset of *logrus.Logger and *logrus.Entry used by the
+// adapter.
+type Printer interface {
+	Debug(args ...any)
+	Info(args ...any)
+	Error(args ...any)
+}
+
+// New returns a lyra.Logger writing to logger.
+func New(logger Printer) lyra.Logger {
+	return adapter{logger: logger}
+}
+
+type adapter struct {
+	logger Printer
+}
+
+func (a adapter) Debug(msg string, keysAndValues ...any) {
+	a.logger.Debug(format(msg, keysAndValues))
+}
+
+func (a adapter) Info(msg string, keysAndValues ...any) {
+	a.logger.Info(format(msg, keysAndValues))
+}
+
+func (a adapter) Error(msg string, keysAndValues ...any) {
+	a.logger.Error(format(msg, keysAndValues))
+}
+
+// format appends the key=value pairs to msg. A trailing key without a
+// value is kept with an empty value.
+func format(msg string, keysAndValues []any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value any
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%v", keysAndValues[i], value)
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package logruslog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+type fakePrinter struct {
+	lines []string
+}
+
+func (f *fakePrinter) Debug(args ...any) {
+	f.lines = append(f.lines, "debug: "+fmt.Sprint(args...))
+}
+
+func (f *fakePrinter) Info(args ...any) {
+	f.lines = append(f.lines, "info: "+fmt.Sprint(args...))
+}
+
+func (f *fakePrinter) Error(args ...any) {
+	f.lines = append(f.lines, "error: "+fmt.Sprint(args...))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakePrinter{}
+	l := lyra.New().Do("task", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	_, err := l.Run(context.Background(), nil, lyra.WithRunID("run-1"), lyra.WithLogger(New(fake)))
+
+	require.NoError(t, err)
+	require.Contains(t, fake.lines, "debug: lyra: task_finished run_id=run-1 stage=0 task_id=task")
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "msg", format("msg", nil))
+	require.Equal(t, "msg a=1 b=<nil>", format("msg", []any{"a", 1, "b"}))
+}
@@ -2,9 +2,13 @@ package lyra
 
 import (
 	"context"
-	stderr "errors"
+	"maps"
 	"reflect"
+	"runtime/pprof"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/sourabh-kumar2/lyra/errors"
 	"github.com/sourabh-kumar2/lyra/internal"
@@ -16,10 +20,42 @@ import (
 // It replaces manual sync.WaitGroup and channel coordination with a clean, fluent API.
 //
 // The zero value is not usable; create instances with New().
+//
+// A Lyra is safe for concurrent use. Build the DAG first: the first call to
+// Run freezes it, after which any number of runs may execute concurrently,
+// each with its own Result and state. Changing a frozen DAG is an error
+// reported by every later run; use Clone to derive a modified copy.
 type Lyra struct {
-	mu    sync.RWMutex
-	tasks map[string]*internal.Task
-	error error
+	mu        sync.RWMutex
+	tasks     map[string]*internal.Task
+	inputDocs map[string]string
+	required  map[string]reflect.Type
+	error     error
+	frozen    bool
+	autoWire  bool
+	// builderErrs holds the errors of incomplete tasks added with Task.
+	builderErrs map[string]error
+	// tagLimits is set with LimitTag.
+	tagLimits map[string]int
+	// fieldMatcher is set with MatchFields.
+	fieldMatcher FieldMatcher
+	metrics      engineMetrics
+
+	running sync.Map // runningKey -> RunningTask
+
+	// runsMu guards the runs in flight, tracked for Shutdown.
+	runsMu     sync.Mutex
+	runCancels map[*runState]context.CancelCauseFunc
+	inflight   sync.WaitGroup
+	shutdown   bool
+
+	recentMu     sync.Mutex
+	recentRuns   []RunSummary
+	historyStore HistoryStore
+
+	snapshotOnce sync.Once
+	snapshot     *dagSnapshot
+	snapshotErr  error
 }
 
 // New creates a new Lyra instance for building and executing DAGs.
@@ -36,6 +72,32 @@ func New() *Lyra {
 	}
 }
 
+// Clone returns a copy of the DAG that can be changed, and run, without
+// affecting l, even when l is already frozen by a run.
+//
+// Use it to add request-specific tasks on top of a shared base DAG. Build
+// errors recorded on l are carried over to the clone.
+//
+// Example:
+//
+//	l := base.Clone().Do("audit", audit, lyra.Use("generateReport"))
+func (l *Lyra) Clone() *Lyra {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	// Tasks are immutable once created, so the clones may share them.
+	return &Lyra{
+		tasks:        maps.Clone(l.tasks),
+		inputDocs:    maps.Clone(l.inputDocs),
+		required:     maps.Clone(l.required),
+		error:        l.error,
+		builderErrs:  maps.Clone(l.builderErrs),
+		autoWire:     l.autoWire,
+		tagLimits:    maps.Clone(l.tagLimits),
+		fieldMatcher: l.fieldMatcher,
+	}
+}
+
 // Do adds a task to the DAG with the specified function and input specifications.
 //
 // The taskID must be unique within the DAG and will be used to reference this
@@ -50,6 +112,19 @@ func New() *Lyra {
 //   - Use("taskID") - use entire result from another task
 //   - Use("taskID", "field") - use specific field from task result
 //   - UseRun("key") - use value from runtime inputs map
+//   - UseAuto() - use the result of the one task producing the parameter type
+//   - UseTags() - populate a struct from the lyra and lyrarun tags of its fields
+//
+// With AutoWire, trailing parameters without an input spec are bound as if
+// by UseAuto.
+//
+// Task options can be mixed with the input specs, in any position; they
+// configure the task and are not bound to parameters. Among them:
+//   - WithTimeout, WithRetry and WithBackoff - bound and retry attempts
+//   - WithPriority, WithBulkhead and WithExclusive - control scheduling
+//   - WithTag, WithMeta and WithDescription - describe the task to tooling
+//   - WithIdempotencyKey - reuse the output of an earlier execution
+//   - WithHooks - observe the execution of the task
 //
 // Returns the same Lyra instance for method chaining.
 //
@@ -61,6 +136,13 @@ func (l *Lyra) Do(taskID string, fn any, inputs ...internal.InputSpec) *Lyra {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to add task %q", taskID)
+		return l
+	}
+	if l.autoWire {
+		inputs = internal.PadAutoSpecs(fn, inputs)
+	}
 	task, err := internal.NewTask(taskID, fn, inputs)
 	if err != nil {
 		l.error = errors.Wrapf(err, "failed to add task %q", taskID)
@@ -74,6 +156,63 @@ func (l *Lyra) Do(taskID string, fn any, inputs ...internal.InputSpec) *Lyra {
 	return l
 }
 
+// Remove deletes a task from the DAG, typically one taken over from a Clone.
+// Tasks that still depend on it fail the run with a missing dependency.
+//
+// Removing an unknown task is a build error returned by Run.
+//
+// Returns the same Lyra instance for method chaining.
+func (l *Lyra) Remove(taskID string) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to remove task %q", taskID)
+		return l
+	}
+	if _, exists := l.tasks[taskID]; !exists {
+		l.error = errors.Wrapf(errors.ErrTaskNotFound, "failed to remove task %q", taskID)
+		return l
+	}
+	delete(l.tasks, taskID)
+	return l
+}
+
+// Replace swaps the function and input specs of an existing task, keeping
+// its ID so that dependents are wired to the new function. It accepts the
+// same arguments as Do, and options of the old task are not kept.
+//
+// Replacing an unknown task is a build error returned by Run.
+//
+// Returns the same Lyra instance for method chaining.
+//
+// Example:
+//
+//	l := base.Clone().Replace("fetchUser", fakeFetchUser, lyra.UseRun("userID"))
+func (l *Lyra) Replace(taskID string, fn any, inputs ...internal.InputSpec) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to replace task %q", taskID)
+		return l
+	}
+	if _, exists := l.tasks[taskID]; !exists {
+		l.error = errors.Wrapf(errors.ErrTaskNotFound, "failed to replace task %q", taskID)
+		return l
+	}
+	if l.autoWire {
+		inputs = internal.PadAutoSpecs(fn, inputs)
+	}
+	task, err := internal.NewTask(taskID, fn, inputs)
+	if err != nil {
+		l.error = errors.Wrapf(err, "failed to replace task %q", taskID)
+		return l
+	}
+	l.tasks[taskID] = task
+	return l
+}
+
 // Run executes the DAG with the provided runtime inputs.
 //
 // The method validates the DAG structure, detects cycles, and executes tasks
@@ -83,8 +222,21 @@ func (l *Lyra) Do(taskID string, fn any, inputs ...internal.InputSpec) *Lyra {
 // The runInputs map provides initial values that can be referenced by tasks
 // using UseRun() input specifications.
 //
+// Use WithRunTimeout to bound the whole run independently of ctx.
+//
+// Run may be called concurrently. The first call freezes the DAG (see Lyra).
+//
+// Each call is assigned a unique run ID (see WithRunID) that is injected into
+// every task's context, retrievable with RunIDFromContext, and included in
+// returned errors for log correlation.
+//
+// Runtime inputs and task outputs share the Result namespace, so a runtime
+// input key must not be the ID of a task.
+//
 // Returns a Result object containing all task outputs, or an error if:
 //   - The DAG contains cycles
+//   - A runtime input key is also a task ID
+//   - A required or referenced runtime input is missing or mistyped (see Require)
 //   - Dependencies reference non-existent tasks
 //   - Parameter types don't match between tasks
 //   - Any task function returns an error
@@ -100,42 +252,155 @@ func (l *Lyra) Do(taskID string, fn any, inputs ...internal.InputSpec) *Lyra {
 //	}
 //
 //	user, _ := results.Get("fetchUser")
-func (l *Lyra) Run(ctx context.Context, runInputs map[string]any) (*Result, error) {
-	if l.error != nil {
-		return nil, errors.Wrapf(l.error, "build error")
+func (l *Lyra) Run(ctx context.Context, runInputs map[string]any, opts ...RunOption) (*Result, error) {
+	state, err := l.prepare(runInputs, newRunConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return l.execute(ctx, state)
+}
+
+// prepare validates the DAG and creates the state of a new run.
+func (l *Lyra) prepare(runInputs map[string]any, cfg *runConfig) (*runState, error) {
+	snapshot, err := l.freeze()
+
+	l.mu.RLock()
+	buildErr := l.buildError()
+	l.mu.RUnlock()
+	if buildErr != nil {
+		return nil, errors.Wrapf(buildErr, "run %s: build error", cfg.runID)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "run %s: failed to get stages", cfg.runID)
+	}
+	runInputs = provideInputs(runInputs, cfg.inputProvider, snapshot.requirements)
+	if err := checkInputCollisions(runInputs, snapshot.deps); err != nil {
+		return nil, errors.Wrapf(err, "run %s: invalid run inputs", cfg.runID)
+	}
+	if err := checkRunInputs(runInputs, snapshot.requirements, snapshot.secrets); err != nil {
+		return nil, errors.Wrapf(err, "run %s: invalid run inputs", cfg.runID)
 	}
 
-	result := l.initialiseResult(runInputs)
-	stages, err := l.getStages()
+	seeded, err := skippedOutputs(cfg, snapshot.tasks)
+	if err != nil {
+		return nil, errors.Wrapf(err, "run %s: invalid seeded outputs", cfg.runID)
+	}
+
+	if cfg.idempotency == nil {
+		cfg.idempotency = snapshot.idempotency
+	}
+	state := newRunState(cfg, initialiseResult(runInputs, snapshot), snapshot.deps, snapshot.stages)
+	state.tasks = snapshot.tasks
+	state.seeded = seeded
+	state.strictDeps = snapshot.strictDeps
+	state.bulkheads = snapshot.bulkheads
+	state.quorumReaders = snapshot.quorumReaders
+	state.metrics = &l.metrics
+	state.inputsHash = hashInputs(runInputs, snapshot.secrets)
+	return state, nil
+}
+
+// execute runs the prepared DAG to completion, unless l is shut down.
+func (l *Lyra) execute(ctx context.Context, state *runState) (*Result, error) {
+	ctx, finish, err := l.admit(ctx, state)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get stages")
+		return nil, err
 	}
+	defer finish()
 
-	err = l.process(ctx, stages, result)
+	result, err := l.executeStages(ctx, state)
+	l.recordRun(state.summary(time.Now(), err), state.cfg.logger)
+	return result, err
+}
+
+// executeStages runs the stages of the prepared DAG in order.
+func (l *Lyra) executeStages(ctx context.Context, state *runState) (*Result, error) {
+	cfg := state.cfg
+	ctx = contextWithRunID(ctx, cfg.runID)
+
+	var timeoutErr *errors.RunTimeoutError
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		timeoutErr = &errors.RunTimeoutError{Timeout: cfg.timeout}
+		ctx, cancel = context.WithTimeoutCause(ctx, cfg.timeout, timeoutErr)
+		defer cancel()
+	}
+
+	state.metrics.runs.Add(1)
+	state.metrics.activeRuns.Add(1)
+	defer state.metrics.activeRuns.Add(-1)
+
+	err := l.process(ctx, state)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to process stages")
+		state.metrics.failedRuns.Add(1)
+		switch {
+		case timeoutErr != nil && context.Cause(ctx) == timeoutErr:
+			state.markSkipped()
+			timeoutErr.Pending = state.pendingTasks()
+			err = timeoutErr
+		case ctx.Err() != nil:
+			err = &errors.RunCanceledError{Cause: context.Cause(ctx), Canceled: state.markCanceled()}
+		default:
+			state.markSkipped()
+		}
+		return nil, errors.Wrapf(err, "run %s: failed to process stages", cfg.runID)
 	}
 
-	return result, nil
+	return state.result, nil
 }
 
-func (*Lyra) initialiseResult(runInputs map[string]any) *Result {
+// checkInputCollisions rejects runtime input keys that are also task IDs,
+// since either value would silently shadow the other in the Result.
+func checkInputCollisions(runInputs map[string]any, tasks map[string][]string) error {
+	var collisions []string
+	for key := range runInputs {
+		if _, ok := tasks[key]; ok {
+			collisions = append(collisions, key)
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+	slices.Sort(collisions)
+	return errors.Wrapf(errors.ErrInputCollidesWithTask, "keys %q", collisions)
+}
+
+func initialiseResult(runInputs map[string]any, snapshot *dagSnapshot) *Result {
 	result := NewResult()
+	result.slotIndexes = snapshot.slots
+	result.slots = make([]resultSlot, len(snapshot.slots))
+	result.secrets = snapshot.secrets
+	result.final = snapshot.leaves
+	result.groups = newGroupOutcomes(snapshot.tasks)
 	for taskID, input := range runInputs {
 		result.set(taskID, input)
 	}
 	return result
 }
 
-func (l *Lyra) getStages() ([][]string, error) {
+// dependencies returns the IDs of tasks mapped to the task IDs they depend on.
+func dependencies(tasks map[string]*internal.Task) map[string][]string {
+	taskGraph := make(map[string][]string, len(tasks))
+	for taskID, task := range tasks {
+		taskGraph[taskID] = taskDependencies(task, tasks)
+	}
+	return taskGraph
+}
+
+// taskPriorities returns the scheduling priority of every task.
+func (l *Lyra) taskPriorities() map[string]int {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	taskGraph := map[string][]string{}
+	priorities := make(map[string]int, len(l.tasks))
 	for taskID, task := range l.tasks {
-		taskGraph[taskID] = task.GetDependencies()
+		priorities[taskID] = task.GetOptions().Priority
 	}
+	return priorities
+}
 
+func buildStages(taskGraph map[string][]string) ([][]string, error) {
 	stages, err := graph.NewDependencyDAG(taskGraph).GetExecutionLevels()
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build graph")
@@ -143,9 +408,20 @@ func (l *Lyra) getStages() ([][]string, error) {
 	return stages, nil
 }
 
-func (l *Lyra) process(ctx context.Context, stages [][]string, result *Result) error {
-	for _, stage := range stages {
-		err := l.executeStage(ctx, stage, result)
+func (l *Lyra) process(ctx context.Context, state *runState) error {
+	for i, stage := range state.stages {
+		if ctx.Err() != nil {
+			return errors.Wrapf(context.Cause(ctx), "stage %d not started", i)
+		}
+		state.startStage(i)
+		state.emit(Event{Type: EventStageStarted, Stage: i, TaskIDs: stage})
+		state.metrics.queuedTasks.Add(int64(len(stage)))
+		for _, taskID := range stage {
+			state.emit(Event{Type: EventTaskScheduled, Stage: i, TaskID: taskID})
+		}
+		err := l.executeStage(ctx, i, stage, state)
+		state.emit(Event{Type: EventStageFinished, Stage: i, TaskIDs: stage, Err: err})
+		state.finishStage(i, err)
 		if err != nil {
 			return errors.Wrapf(err, "execute stage")
 		}
@@ -153,65 +429,208 @@ func (l *Lyra) process(ctx context.Context, stages [][]string, result *Result) e
 	return nil
 }
 
-func (l *Lyra) executeStage(ctx context.Context, stage []string, result *Result) error {
-	if len(stage) == 1 {
-		return l.executeTask(ctx, stage[0], result) // Single task - no need for goroutines
+func (l *Lyra) executeStage(ctx context.Context, stageIdx int, stage []string, state *runState) error {
+	if len(stage) == 1 && state.cfg.adaptive == nil {
+		return l.executeTask(ctx, stageIdx, stage[0], state) // Single task - no need for goroutines
 	}
-	// Multiple tasks - execute concurrently
+	// Multiple tasks - execute concurrently, in dispatch order when limited
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(stage))
-
-	for _, taskID := range stage {
-		wg.Add(1)
-		go func(id string) {
-			defer wg.Done()
-			if err := l.executeTask(ctx, id, result); err != nil {
-				errChan <- errors.Wrapf(err, "task %q failed", id)
+	var mu sync.Mutex
+	errs := make(map[string]error)
+
+	fail := func(id string, err error) {
+		mu.Lock()
+		errs[id] = err
+		mu.Unlock()
+	}
+	run := func(id string) {
+		if limiter := state.cfg.adaptive; limiter != nil {
+			if err := limiter.acquire(ctx); err != nil {
+				// The run is canceled; the task stays pending and is
+				// marked canceled with the other unstarted tasks.
+				state.metrics.queuedTasks.Add(-1)
+				fail(id, errors.Wrapf(err, "task %s not started", id))
+				return
 			}
-		}(taskID)
+			start := time.Now()
+			defer func() { limiter.release(id, time.Since(start)) }()
+		}
+		if err := l.executeTask(ctx, stageIdx, id, state); err != nil {
+			fail(id, err)
+		}
+	}
+
+	queue := newStageQueue(stage, state.tasks, state.cfg.kindLimits)
+	if workers := stageWorkers(len(stage), state.cfg); workers < len(stage) || queue.limited() {
+		// A pool of workers takes the tasks in dispatch order.
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for id, ok := queue.next(); ok; id, ok = queue.next() {
+					run(id)
+					queue.done(id)
+				}
+			}()
+		}
+	} else {
+		for _, taskID := range stage {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				run(id)
+			}(taskID)
+		}
 	}
 
 	wg.Wait()
-	close(errChan)
 
-	//nolint:prealloc // pre-allocating is not required.
-	var errs []error
-	for err := range errChan {
-		errs = append(errs, err)
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		for _, err := range errs {
+			return err
+		}
 	}
+	return errors.NewMultiTaskError(errs)
+}
 
-	if len(errs) > 0 {
-		//nolint:wrapcheck // stderr points to standard errors.
-		return stderr.Join(errs...)
+// stageWorkers returns the number of goroutines executing a stage of n
+// tasks, bounded by the concurrency limit, the stage workers and the
+// largest adaptive limit of cfg.
+func stageWorkers(n int, cfg *runConfig) int {
+	workers := n
+	limits := []int{cfg.concurrency, cfg.stageWorkers}
+	if cfg.adaptive != nil {
+		limits = append(limits, cfg.adaptive.maxLimit)
+	}
+	for _, limit := range limits {
+		if limit > 0 && limit < workers {
+			workers = limit
+		}
+	}
+	return workers
+}
+
+// executeTask executes the task with its goroutine labeled for profiles
+// and goroutine dumps (see TaskLabel).
+func (l *Lyra) executeTask(ctx context.Context, stageIdx int, taskID string, state *runState) error {
+	var err error
+	pprof.Do(ctx, pprof.Labels(RunLabel, state.cfg.runID, TaskLabel, taskID), func(ctx context.Context) {
+		err = l.runTask(ctx, stageIdx, taskID, state)
+	})
+	return err
+}
+
+func (l *Lyra) runTask(ctx context.Context, stageIdx int, taskID string, state *runState) error {
+	task := state.tasks[taskID]
+	state.metrics.queuedTasks.Add(-1)
+	if output, ok := state.seeded[taskID]; ok {
+		state.markSeeded(stageIdx, taskID, output)
+		return nil
+	}
+
+	ctx, cancel, ok := state.markStarted(contextWithTask(ctx, taskID, 1), stageIdx, taskID)
+	if !ok {
+		return nil // canceled before it could start
+	}
+	defer cancel()
+	ctx = contextWithReporter(ctx, state, stageIdx, taskID)
+	ctx = contextWithCleanup(ctx, task)
+	defer l.trackRunning(state.cfg.runID, stageIdx, taskID)()
+	defer watchSoftDeadline(ctx, stageIdx, task, state)()
+
+	var output any
+	var hasOutput bool
+	start := time.Now()
+	err := state.checkQuorum(task)
+	var release func()
+	if err == nil {
+		release, err = acquireTask(ctx, task, state)
+	}
+	if err == nil {
+		state.metrics.activeTasks.Add(1)
+		output, hasOutput, err = callWithAllocations(ctx, stageIdx, task, state)
+		state.metrics.activeTasks.Add(-1)
+		release()
+	}
+	if err == nil && hasOutput {
+		output, err = limitOutput(ctx, task, state.cfg.outputLimit, output)
+	}
+	if state.cfg.audit != nil {
+		err = auditTask(ctx, task, state, start, output, err)
+	}
+	if err != nil {
+		if state.cfg.snapshotInputs {
+			err = snapshotInputs(ctx, task, state.result, err)
+		}
+		if !state.markFailed(stageIdx, taskID, err) {
+			return nil // canceled while running
+		}
+		state.metrics.failedTasks.Add(1)
+		if group := task.GetOptions().Group; group != "" {
+			state.failGroup(group, taskID, err)
+			return nil
+		}
+		if skipsDependents(task, err) {
+			state.skipDependents(taskID, err)
+			return nil
+		}
+		if state.allowSourceFailure(taskID, err) || state.allowFailure(task, err) {
+			return nil
+		}
+		chain := state.dependencyChain(taskID)
+		return errors.Wrapf(err, "task %q failed (%s)", taskID, strings.Join(chain, " <- "))
 	}
 
+	state.markCompleted(stageIdx, taskID, output, hasOutput)
+	state.metrics.completedTasks.Add(1)
+	state.meetQuorums(taskID)
 	return nil
 }
 
-func (l *Lyra) executeTask(ctx context.Context, taskID string, result *Result) error {
-	l.mu.RLock()
-	task := l.tasks[taskID]
-	l.mu.RUnlock()
+// callFunction resolves the task's inputs and calls fn, which has the
+// signature of the task's function, with them. A nil fn calls the task's
+// function, or its executor.
+func callFunction(
+	ctx context.Context,
+	task *compiledTask,
+	fn any,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	defer recoverPanic(task, &err)
 
-	args, err := resolveInputs(ctx, task, result)
+	args, err := task.resolve(ctx, state.result)
 	if err != nil {
-		return errors.Wrapf(err, "input resolution failed")
+		return nil, false, errors.Wrapf(err, "input resolution failed")
+	}
+	if state.cfg.copyInputs {
+		for i := 1; i < len(args); i++ { // skip the context
+			args[i] = deepCopy(args[i])
+		}
+	}
+	if fn == nil {
+		if task.GetOptions().Executor != nil {
+			return callExecutor(ctx, task, args)
+		}
+		fn = task.GetFunction()
 	}
 
-	values := reflect.ValueOf(task.GetFunction()).Call(args)
+	values := reflect.ValueOf(fn).Call(args)
 
 	if len(values) == 2 { // (result, error)
 		if !values[1].IsNil() {
 			// revive:disable-next-line:unchecked-type-assertion // It's always error
 			err, _ = values[1].Interface().(error)
-			return err
+			return nil, true, err
 		}
-		result.set(taskID, values[0].Interface())
-	} else if !values[0].IsNil() { // just (error)
+		return values[0].Interface(), true, nil
+	}
+	if !values[0].IsNil() { // just (error)
 		// revive:disable-next-line:unchecked-type-assertion // It's always error
 		err, _ = values[0].Interface().(error)
-		return err
+		return nil, false, err
 	}
-
-	return nil
+	return nil, false, nil
 }
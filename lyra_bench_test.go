@@ -149,7 +149,7 @@ func BenchmarkDAGValidation(b *testing.B) {
 	b.ResetTimer()
 	for range b.N {
 		// Test just the stage generation (which includes validation)
-		stages, err := l.getStages()
+		stages, err := buildStages(dependencies(l.tasks))
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -368,13 +368,13 @@ func fetchOrders(ctx context.Context, userID int) ([]Order, error) {
 }
 
 //nolint:gocritic // This is test case.
-func generateReport(ctx context.Context, user User, orders []Order) (Report, error) {
+func generateReport(ctx context.Context, user User, orders []Order) (UserReport, error) {
 	var totalSpent float64
 	for _, order := range orders {
 		totalSpent += order.Amount
 	}
 
-	return Report{
+	return UserReport{
 		UserID:     user.ID,
 		UserName:   user.Name,
 		TotalSpent: totalSpent,
@@ -413,3 +413,20 @@ func memoryIntensiveTask(ctx context.Context, size int) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// BenchmarkResultGetParallel tests concurrent reads of a single result.
+func BenchmarkResultGetParallel(b *testing.B) {
+	r := NewResult()
+	for j := range 64 {
+		r.set(fmt.Sprintf("task%d", j), j)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := r.Get("task7"); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourabh-kumar2/lyra/errors"
@@ -16,6 +17,8 @@ import (
 
 //revive:disable:use-errors-new,unnecessary-format
 
+var errTaskFailed = stderr.New("task failed")
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -277,7 +280,7 @@ func TestRunEmptyDAGWithRunInputs(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
-	require.Equal(t, result.data, runInputs)
+	require.Equal(t, result.values(), runInputs)
 }
 
 func TestRunSingleTaskNoInputs(t *testing.T) {
@@ -417,8 +420,8 @@ func TestRunComplexWorkflow(t *testing.T) {
 			}
 			return total, nil
 		}, Use("fetchOrders")).
-		Do("generateReport", func(ctx context.Context, user User, orders []Order, total float64) (Report, error) {
-			return Report{
+		Do("generateReport", func(ctx context.Context, user User, orders []Order, total float64) (UserReport, error) {
+			return UserReport{
 				UserName:   user.Name,
 				OrderCount: len(orders),
 				TotalSpent: total,
@@ -432,7 +435,7 @@ func TestRunComplexWorkflow(t *testing.T) {
 	report, err := result.Get("generateReport")
 	require.NoError(t, err)
 
-	expectedReport := Report{
+	expectedReport := UserReport{
 		UserName:   "Bob",
 		OrderCount: 2,
 		TotalSpent: 350.0,
@@ -895,6 +898,37 @@ func TestConcurrentStageContextCancellation(t *testing.T) {
 	require.Contains(t, err.Error(), "context")
 }
 
+func TestRunCanceledMarksRemainingTasks(t *testing.T) {
+	t.Parallel()
+
+	errStop := stderr.New("stop") //nolint:err113 // test case.
+	ctx, cancel := context.WithCancelCause(context.Background())
+	l := New().
+		Do("source", func(ctx context.Context) (int, error) {
+			cancel(errStop)
+			return 10, nil
+		}).
+		Do("left", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("source")).
+		Do("right", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("left"))
+
+	run := l.RunAsync(ctx, nil)
+	_, err := run.Wait()
+
+	require.ErrorIs(t, err, errStop)
+	var canceledErr *errors.RunCanceledError
+	require.True(t, stderr.As(err, &canceledErr))
+	require.Equal(t, []string{"left", "right"}, canceledErr.Canceled)
+	for _, taskID := range canceledErr.Canceled {
+		status, _ := run.TaskStatus(taskID)
+		require.Equal(t, TaskCanceled, status, taskID)
+	}
+	require.Len(t, eventsOfType(collectEvents(run), EventTaskCanceled), 2)
+}
+
 type User struct {
 	ID      int     `json:"id"`
 	Name    string  `json:"name"`
@@ -922,7 +956,7 @@ type Item struct {
 	Quantity int     `json:"quantity"`
 }
 
-type Report struct {
+type UserReport struct {
 	UserID     int     `json:"user_id"`
 	UserName   string  `json:"user_name"`
 	TotalSpent float64 `json:"total_spent"`
@@ -954,3 +988,286 @@ type testTask struct {
 	fn         any
 	inputSpecs []internal.InputSpec
 }
+
+func TestRunInputCollidesWithTask(t *testing.T) {
+	t.Parallel()
+
+	ran := false
+	l := New().
+		Do("userID", func(ctx context.Context) (int, error) {
+			ran = true
+			return 1, nil
+		}).
+		Do("orderID", func(ctx context.Context) (int, error) {
+			ran = true
+			return 2, nil
+		})
+
+	result, err := l.Run(context.Background(), map[string]any{
+		"userID":  123,
+		"orderID": 456,
+		"other":   789,
+	})
+
+	require.ErrorIs(t, err, errors.ErrInputCollidesWithTask)
+	require.Contains(t, err.Error(), `["orderID" "userID"]`)
+	require.Nil(t, result)
+	require.False(t, ran, "no task runs when inputs collide")
+}
+
+func TestRunErrorIncludesDependencyChain(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetchOrders", func(ctx context.Context) (int, error) {
+			return 3, nil
+		}).
+		Do("fetchUser", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("calculateTotal", func(ctx context.Context, orders int) (int, error) {
+			return orders * 10, nil
+		}, Use("fetchOrders")).
+		Do("generateReport", func(ctx context.Context, user, total int) (int, error) {
+			return 0, errTaskFailed
+		}, Use("fetchUser"), Use("calculateTotal"))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Contains(t, err.Error(), `task "generateReport" failed (generateReport <- calculateTotal <- fetchOrders)`)
+}
+
+func TestRunStateDependencyChain(t *testing.T) {
+	t.Parallel()
+
+	deps := map[string][]string{
+		"a": {},
+		"b": {},
+		"c": {"b", "a"},
+		"d": {"c", "a"},
+	}
+	stages := [][]string{{"a", "b"}, {"c"}, {"d"}}
+	state := newRunState(newRunConfig(nil), NewResult(), deps, stages)
+
+	require.Equal(t, []string{"a"}, state.dependencyChain("a"))
+	require.Equal(t, []string{"c", "a"}, state.dependencyChain("c"))
+	require.Equal(t, []string{"d", "c", "a"}, state.dependencyChain("d"))
+}
+
+func TestRunMultiTaskError(t *testing.T) {
+	t.Parallel()
+
+	errOther := stderr.New("other failed")
+	l := New().
+		Do("first", func(ctx context.Context) (int, error) {
+			return 0, errTaskFailed
+		}).
+		Do("second", func(ctx context.Context) (int, error) {
+			return 0, errOther
+		}).
+		Do("third", func(ctx context.Context) (int, error) {
+			return 3, nil
+		})
+
+	_, err := l.Run(context.Background(), nil)
+
+	var multi *errors.MultiTaskError
+	require.ErrorAs(t, err, &multi)
+	require.Equal(t, []string{"first", "second"}, multi.TaskIDs())
+	require.ErrorIs(t, multi.Errors()["first"], errTaskFailed)
+	require.ErrorIs(t, multi.Errors()["second"], errOther)
+	require.ErrorIs(t, err, errTaskFailed)
+	require.ErrorIs(t, err, errOther)
+}
+
+func TestRunConcurrentlyOnSharedLyra(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("double", func(ctx context.Context, n int) (int, error) {
+			return n * 2, nil
+		}, UseRun("n")).
+		Do("inc", func(ctx context.Context, n int) (int, error) {
+			return n + 1, nil
+		}, Use("double"))
+
+	var wg sync.WaitGroup
+	for n := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := l.Run(context.Background(), map[string]any{"n": n})
+			assert.NoError(t, err)
+			value, err := result.Get("inc")
+			assert.NoError(t, err)
+			assert.Equal(t, n*2+1, value)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestChangingFrozenDAG(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		change func(l *Lyra)
+	}{
+		{
+			name: "Do",
+			change: func(l *Lyra) {
+				l.Do("late", func(ctx context.Context) error { return nil })
+			},
+		},
+		{
+			name: "Require",
+			change: func(l *Lyra) {
+				l.Require("late", nil)
+			},
+		},
+		{
+			name: "Remove",
+			change: func(l *Lyra) {
+				l.Remove("task")
+			},
+		},
+		{
+			name: "Replace",
+			change: func(l *Lyra) {
+				l.Replace("task", func(ctx context.Context) (int, error) { return 2, nil })
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().Do("task", func(ctx context.Context) (int, error) {
+				return 1, nil
+			})
+			_, err := l.Run(context.Background(), nil)
+			require.NoError(t, err)
+
+			tc.change(l)
+
+			_, err = l.Run(context.Background(), nil)
+			require.ErrorIs(t, err, errors.ErrDAGFrozen)
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	base := New().
+		Do("fetchUser", func(ctx context.Context, id int) (User, error) {
+			return User{ID: id, Name: "Alice"}, nil
+		}, UseRun("userID")).
+		Require("userID", nil)
+	_, err := base.Run(context.Background(), map[string]any{"userID": 1})
+	require.NoError(t, err)
+
+	clone := base.Clone().
+		Do("greet", func(ctx context.Context, name string) (string, error) {
+			return "hi " + name, nil
+		}, Use("fetchUser", "Name"))
+
+	result, err := clone.Run(context.Background(), map[string]any{"userID": 1})
+	require.NoError(t, err)
+	greeting, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hi Alice", greeting)
+
+	result, err = base.Run(context.Background(), map[string]any{"userID": 1})
+	require.NoError(t, err, "base is unaffected by changes to the clone")
+	_, err = result.Get("greet")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+
+	_, err = clone.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrMissingRunInput, "requirements are cloned")
+}
+
+func TestCloneKeepsBuildError(t *testing.T) {
+	t.Parallel()
+
+	base := New().Do("", func(ctx context.Context) error { return nil })
+
+	_, err := base.Clone().Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrTaskIDCannotBeEmpty)
+}
+
+func TestRemoveAndReplace(t *testing.T) {
+	t.Parallel()
+
+	base := New().
+		Do("fetchUser", func(ctx context.Context, id int) (User, error) {
+			return User{ID: id, Name: "Alice"}, nil
+		}, UseRun("userID")).
+		Do("greet", func(ctx context.Context, name string) (string, error) {
+			return "hi " + name, nil
+		}, Use("fetchUser", "Name")).
+		Do("audit", func(ctx context.Context) error {
+			return nil
+		})
+
+	l := base.Clone().
+		Remove("audit").
+		Replace("fetchUser", func(ctx context.Context) (User, error) {
+			return User{Name: "Fake"}, nil
+		})
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	greeting, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hi Fake", greeting)
+	_, err = result.Get("audit")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestRemoveAndReplaceErrors(t *testing.T) {
+	t.Parallel()
+
+	noop := func(ctx context.Context) (User, error) { return User{}, nil }
+
+	tcs := []struct {
+		name        string
+		lyra        *Lyra
+		expectedErr error
+	}{
+		{
+			name:        "remove unknown task",
+			lyra:        New().Remove("missing"),
+			expectedErr: errors.ErrTaskNotFound,
+		},
+		{
+			name:        "replace unknown task",
+			lyra:        New().Replace("missing", noop),
+			expectedErr: errors.ErrTaskNotFound,
+		},
+		{
+			name:        "replace with invalid function",
+			lyra:        New().Do("task", noop).Replace("task", "not a function"),
+			expectedErr: errors.ErrMustBeFunction,
+		},
+		{
+			name: "remove a dependency",
+			lyra: New().
+				Do("fetchUser", noop).
+				Do("greet", func(ctx context.Context, u User) error { return nil }, Use("fetchUser")).
+				Remove("fetchUser"),
+			expectedErr: errors.ErrMissingDependency,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.lyra.Run(context.Background(), nil)
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
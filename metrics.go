@@ -0,0 +1,65 @@
+package lyra
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// MetricsSnapshot is a point-in-time view of the activity of a Lyra
+// instance across all of its runs.
+type MetricsSnapshot struct {
+	// Runs is the number of runs started.
+	Runs int64 `json:"runs"`
+	// FailedRuns is the number of runs that returned an error.
+	FailedRuns int64 `json:"failed_runs"`
+	// ActiveRuns is the number of runs executing now.
+	ActiveRuns int64 `json:"active_runs"`
+	// QueuedTasks is the number of tasks of started stages waiting to
+	// execute, for example on the concurrency limit (see WithConcurrency).
+	QueuedTasks int64 `json:"queued_tasks"`
+	// ActiveTasks is the number of tasks executing now.
+	ActiveTasks int64 `json:"active_tasks"`
+	// CompletedTasks is the number of tasks that succeeded.
+	CompletedTasks int64 `json:"completed_tasks"`
+	// FailedTasks is the number of tasks that failed after all retries.
+	FailedTasks int64 `json:"failed_tasks"`
+}
+
+// engineMetrics holds the live counters behind MetricsSnapshot.
+type engineMetrics struct {
+	runs           atomic.Int64
+	failedRuns     atomic.Int64
+	activeRuns     atomic.Int64
+	queuedTasks    atomic.Int64
+	activeTasks    atomic.Int64
+	completedTasks atomic.Int64
+	failedTasks    atomic.Int64
+}
+
+func (m *engineMetrics) snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Runs:           m.runs.Load(),
+		FailedRuns:     m.failedRuns.Load(),
+		ActiveRuns:     m.activeRuns.Load(),
+		QueuedTasks:    m.queuedTasks.Load(),
+		ActiveTasks:    m.activeTasks.Load(),
+		CompletedTasks: m.completedTasks.Load(),
+		FailedTasks:    m.failedTasks.Load(),
+	}
+}
+
+// Metrics returns the current counters of all runs of l, for health checks
+// and monitoring. Clones start with their own, zeroed counters.
+func (l *Lyra) Metrics() MetricsSnapshot {
+	return l.metrics.snapshot()
+}
+
+// PublishExpvar publishes the metrics of l as the expvar variable name, so
+// they are served as JSON on /debug/vars.
+//
+// Like expvar.Publish, it panics if name is already in use.
+func (l *Lyra) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return l.Metrics()
+	}))
+}
@@ -0,0 +1,67 @@
+package lyra
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	l := New().
+		Do("block", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		}).
+		Do("waiting", func(ctx context.Context) (int, error) {
+			return 2, nil
+		}).
+		Do("fail", func(ctx context.Context, _ int) error {
+			return errTaskFailed
+		}, Use("block"))
+
+	require.Equal(t, MetricsSnapshot{}, l.Metrics())
+
+	run := l.RunAsync(context.Background(), nil, WithConcurrency(1))
+	<-started
+	require.Equal(t, MetricsSnapshot{
+		Runs:        1,
+		ActiveRuns:  1,
+		QueuedTasks: 1,
+		ActiveTasks: 1,
+	}, l.Metrics())
+
+	close(release)
+	_, err := run.Wait()
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Equal(t, MetricsSnapshot{
+		Runs:           1,
+		FailedRuns:     1,
+		CompletedTasks: 2,
+		FailedTasks:    1,
+	}, l.Metrics())
+}
+
+func TestPublishExpvar(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("task", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	name := "lyra_test_" + NewRunID() // unique across -count runs
+	l.PublishExpvar(name)
+
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	var published MetricsSnapshot
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &published))
+	require.Equal(t, MetricsSnapshot{Runs: 1, CompletedTasks: 1}, published)
+}
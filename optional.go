@@ -0,0 +1,62 @@
+package lyra
+
+import (
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Optional marks the input bound by spec as optional: when the source holds
+// no value, because the upstream task was canceled or returns only an
+// error, or the runtime input is not given, the parameter receives its zero
+// value instead of failing input resolution. Canceling the upstream task
+// does not cancel the task reading it optionally.
+//
+// Example:
+//
+//	l.Do("render", render, lyra.Use("fetchUser"), lyra.Optional(lyra.Use("fetchAvatar")))
+func Optional(spec internal.InputSpec) internal.InputSpec {
+	spec.Optional = true
+	return spec
+}
+
+// Default is like Optional but binds value instead of the zero value. The
+// value must be assignable to the parameter type.
+//
+// Example:
+//
+//	l.Do("page", page, lyra.Default(lyra.UseRun("pageSize"), 50))
+func Default(spec internal.InputSpec, value any) internal.InputSpec {
+	spec = Optional(spec)
+	spec.Default = value
+	return spec
+}
+
+// optionalValue returns the value bound to a parameter of type typ when the
+// source of spec holds no value. ok is false if spec is not optional.
+func optionalValue(spec internal.InputSpec, typ reflect.Type) (value reflect.Value, ok bool) {
+	if !spec.Optional {
+		return reflect.Value{}, false
+	}
+	if spec.Default != nil {
+		return reflect.ValueOf(spec.Default), true
+	}
+	return reflect.Zero(typ), true
+}
+
+// strictDependencies returns the IDs of tasks mapped to the task IDs they
+// read with inputs that are not optional, along which cancellation spreads.
+func strictDependencies(tasks map[string]*internal.Task) map[string][]string {
+	taskGraph := make(map[string][]string, len(tasks))
+	for taskID, task := range tasks {
+		specs, _ := task.GetInputParams()
+		deps := make([]string, 0, len(specs))
+		for _, spec := range specs {
+			if !spec.Optional {
+				deps = append(deps, specDependencies(spec, tasks)...)
+			}
+		}
+		taskGraph[taskID] = deps
+	}
+	return taskGraph
+}
@@ -0,0 +1,141 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	pageSize := func(ctx context.Context, size int) (int, error) { return size, nil }
+
+	tcs := []struct {
+		name     string
+		spec     internal.InputSpec
+		inputs   map[string]any
+		expected any
+		err      error
+	}{
+		{
+			name:     "present runtime input",
+			spec:     Optional(UseRun("pageSize")),
+			inputs:   map[string]any{"pageSize": 10},
+			expected: 10,
+		},
+		{
+			name:     "missing runtime input",
+			spec:     Optional(UseRun("pageSize")),
+			inputs:   map[string]any{},
+			expected: 0,
+		},
+		{
+			name:     "missing runtime input with default",
+			spec:     Default(UseRun("pageSize"), 50),
+			inputs:   map[string]any{},
+			expected: 50,
+		},
+		{
+			name:   "present runtime input of wrong type",
+			spec:   Optional(UseRun("pageSize")),
+			inputs: map[string]any{"pageSize": "10"},
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name:   "default of wrong type",
+			spec:   Default(UseRun("pageSize"), "50"),
+			inputs: map[string]any{},
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name:   "optional template",
+			spec:   Optional(UseTemplate("{{.pageSize}}")),
+			inputs: map[string]any{},
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name:   "required runtime input",
+			spec:   UseRun("pageSize"),
+			inputs: map[string]any{},
+			err:    errors.ErrMissingRunInput,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().Do("page", pageSize, tc.spec)
+
+			result, err := l.Run(context.Background(), tc.inputs)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				size, err := result.Get("page")
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, size)
+			}
+		})
+	}
+}
+
+func TestOptionalTaskWithoutOutput(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("warmCache", func(ctx context.Context) error { return nil }).
+		Do("count", func(ctx context.Context, hits *int) (bool, error) { return hits == nil, nil },
+			Optional(Use("warmCache")))
+
+	result, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	missing, err := result.Get("count")
+	require.NoError(t, err)
+	require.Equal(t, true, missing)
+}
+
+func TestOptionalSurvivesCanceledDependency(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	// avatar -> render (optional)
+	// user -> render
+	l := New().
+		Do("avatar", func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}).
+		Do("user", func(ctx context.Context) (string, error) {
+			<-release
+			return "Alice", nil
+		}).
+		Do("render", func(ctx context.Context, user, avatar string) (string, error) {
+			return user + avatar, nil
+		}, Use("user"), Default(Use("avatar"), "[default avatar]"))
+
+	run := l.RunAsync(context.Background(), nil)
+
+	require.Eventually(t, func() bool {
+		status, _ := run.TaskStatus("avatar")
+		return status == TaskRunning
+	}, time.Second, time.Millisecond)
+	require.NoError(t, run.CancelTask("avatar"))
+	close(release)
+
+	result, err := run.Wait()
+	require.NoError(t, err)
+
+	status, _ := run.TaskStatus("render")
+	require.Equal(t, TaskSucceeded, status)
+	page, err := result.Get("render")
+	require.NoError(t, err)
+	require.Equal(t, "Alice[default avatar]", page)
+}
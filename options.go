@@ -0,0 +1,296 @@
+package lyra
+
+import "time"
+
+// RunOption configures a single execution started by Lyra.Run.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	runID          string
+	timeout        time.Duration
+	progress       ProgressFunc
+	progressDetail func(Progress)
+	history        DurationHistory
+	concurrency    int
+	stageWorkers   int
+	adaptive       *AdaptiveLimiter
+	kindLimits     map[TaskKind]int
+	snapshotInputs bool
+	retryBudget    int
+	logger         Logger
+	locker         Locker
+	retained       map[string]struct{}
+	copyInputs     bool
+	audit          AuditSink
+	inputProvider  InputProvider
+	seeds          map[string]any
+	precomputed    map[string]any
+	forced         map[string]struct{}
+	idempotency    IdempotencyStore
+	onStageStart   func(stageIdx int, taskIDs []string)
+	onStageEnd     func(StageSummary)
+	// allowedFailures is set with WithAllowedFailures.
+	allowedFailures int
+	profiling       bool
+	allocations     bool
+	outputLimit     OutputLimit
+}
+
+func newRunConfig(opts []RunOption) *runConfig {
+	cfg := &runConfig{retryBudget: -1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	if cfg.runID == "" {
+		cfg.runID = NewRunID()
+	}
+	if cfg.kindLimits == nil {
+		cfg.kindLimits = defaultKindLimits()
+	}
+	return cfg
+}
+
+// WithRunID sets the run ID injected into task contexts and error messages
+// instead of generating a random one.
+//
+// Use it to propagate an existing correlation ID (for example an incoming
+// request ID) into the run. An empty id keeps the generated default.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs, lyra.WithRunID(requestID))
+func WithRunID(id string) RunOption {
+	return func(cfg *runConfig) {
+		cfg.runID = id
+	}
+}
+
+// WithRunTimeout bounds the total execution time of the run, even when the
+// caller's context has no deadline.
+//
+// When the budget is exhausted the run context is canceled and Run returns
+// an *errors.RunTimeoutError listing the tasks that had not completed.
+// A non-positive d disables the budget.
+//
+// Example:
+//
+//	results, err := l.Run(context.Background(), inputs, lyra.WithRunTimeout(5*time.Second))
+//	var timeoutErr *errors.RunTimeoutError
+//	if stderr.As(err, &timeoutErr) {
+//		log.Printf("still pending: %v", timeoutErr.Pending)
+//	}
+func WithRunTimeout(d time.Duration) RunOption {
+	return func(cfg *runConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithProgress registers fn to be called each time a task completes
+// successfully, for example to drive a CLI progress bar or a health endpoint.
+//
+// Calls are serialized and done increases by one on every call, reaching
+// total once every task has succeeded. fn runs on the execution path, so it
+// should return quickly.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithProgress(func(done, total int, lastTask string) {
+//		fmt.Printf("\r%3d%% (%s)", done*100/total, lastTask)
+//	}))
+func WithProgress(fn ProgressFunc) RunOption {
+	return func(cfg *runConfig) {
+		cfg.progress = fn
+	}
+}
+
+// WithProgressDetail registers fn to be called each time a task completes
+// successfully with a Progress snapshot that includes elapsed time and, when
+// WithDurationHistory is used, an estimate of the remaining time.
+//
+// Calls are serialized in the same way as WithProgress.
+func WithProgressDetail(fn func(Progress)) RunOption {
+	return func(cfg *runConfig) {
+		cfg.progressDetail = fn
+	}
+}
+
+// WithDurationHistory sets the provider of historical task durations used to
+// estimate the remaining time of the run (see Progress.Remaining).
+//
+// If h also implements DurationRecorder, the duration of every successful
+// task is recorded into it, so a single history shared between runs keeps
+// learning.
+//
+// Example:
+//
+//	history := lyra.NewMovingAverageHistory(0.2) // shared across runs
+//	l.Run(ctx, inputs,
+//		lyra.WithDurationHistory(history),
+//		lyra.WithProgressDetail(func(p lyra.Progress) {
+//			log.Printf("%d/%d done, ~%s left", p.Done, p.Total, p.Remaining)
+//		}))
+func WithDurationHistory(h DurationHistory) RunOption {
+	return func(cfg *runConfig) {
+		cfg.history = h
+	}
+}
+
+// WithConcurrency limits the number of tasks of the run executing at the
+// same time. When more tasks are ready than the limit allows, they are
+// dispatched in priority order (see WithPriority). A non-positive n means
+// no limit, which is the default.
+func WithConcurrency(n int) RunOption {
+	return func(cfg *runConfig) {
+		cfg.concurrency = n
+	}
+}
+
+// WithStageWorkers executes the tasks of a stage with a pool of at most n
+// goroutines, which take the tasks in dispatch order (see WithPriority), so
+// a stage with thousands of ready tasks runs in bounded batches instead of
+// starting a goroutine per task and flooding downstream services with
+// connections. Combined with WithConcurrency, the smaller limit applies. A
+// non-positive n means one goroutine per task, which is the default.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs, lyra.WithStageWorkers(64))
+func WithStageWorkers(n int) RunOption {
+	return func(cfg *runConfig) {
+		cfg.stageWorkers = n
+	}
+}
+
+// WithAdaptiveConcurrency limits the number of tasks of the run executing
+// at the same time with limiter, which sizes the limit from
+// runtime.GOMAXPROCS and adjusts it to the observed task latency. It suits
+// DAGs mixing CPU-bound and IO-bound tasks, for which any static limit is
+// wrong somewhere. A nil limiter creates one for the run; pass a shared
+// limiter to keep what it learned across runs. WithConcurrency and
+// WithStageWorkers still apply.
+//
+// Example:
+//
+//	limiter := lyra.NewAdaptiveLimiter(0)
+//	results, err := l.Run(ctx, inputs, lyra.WithAdaptiveConcurrency(limiter))
+func WithAdaptiveConcurrency(limiter *AdaptiveLimiter) RunOption {
+	return func(cfg *runConfig) {
+		if limiter == nil {
+			limiter = NewAdaptiveLimiter(0)
+		}
+		cfg.adaptive = limiter
+	}
+}
+
+// WithInputSnapshots records the resolved input values of a failing task in
+// its error, so the failure can be reproduced by calling the task function
+// directly. Retrieve them with errors.As and *errors.TaskInputsError. Values
+// marked with Secret or WithSecretOutput are replaced by Redacted.
+//
+// Snapshots hold references to the inputs, not copies, and are off by
+// default.
+func WithInputSnapshots() RunOption {
+	return func(cfg *runConfig) {
+		cfg.snapshotInputs = true
+	}
+}
+
+// WithRetryBudget caps the total number of retries across all tasks of the
+// run, so per-task retry policies (see WithRetry) cannot collectively blow
+// the run's latency. Once the budget is used up, failing tasks fail with
+// errors.ErrRetryBudgetExhausted in addition to their own error. A negative
+// n means no budget, which is the default.
+func WithRetryBudget(n int) RunOption {
+	return func(cfg *runConfig) {
+		cfg.retryBudget = n
+	}
+}
+
+// WithLogger logs the lifecycle events of the run (see Event) to logger.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithLogger(slog.Default()))
+func WithLogger(logger Logger) RunOption {
+	return func(cfg *runConfig) {
+		cfg.logger = logger
+	}
+}
+
+// WithLocker acquires the locks of exclusive tasks (see WithExclusive) from
+// locker instead of the process-wide default.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithLocker(redisLocker))
+func WithLocker(locker Locker) RunOption {
+	return func(cfg *runConfig) {
+		cfg.locker = locker
+	}
+}
+
+// WithRetainedOutputs bounds the memory held by the run's Result: the output
+// of a task is dropped as soon as every task depending on it has finished,
+// and only the outputs of the listed tasks are kept in the Result returned
+// by Run. Runtime inputs are always kept.
+//
+// Without this option every task output is kept until the run returns.
+// Calling it without task IDs keeps no task output at all, for runs executed
+// only for their side effects.
+//
+// Example:
+//
+//	result, err := l.Run(ctx, inputs, lyra.WithRetainedOutputs("report"))
+func WithRetainedOutputs(taskIDs ...string) RunOption {
+	return func(cfg *runConfig) {
+		cfg.retained = make(map[string]struct{}, len(taskIDs))
+		for _, taskID := range taskIDs {
+			cfg.retained[taskID] = struct{}{}
+		}
+	}
+}
+
+// WithCopiedInputs hands every task a deep copy of its inputs, so a task
+// mutating a slice, map or pointer it received cannot corrupt the value seen
+// by other tasks reading the same output or runtime input, nor the Result.
+//
+// Copies cost an allocation per reference-typed value on every call.
+// Unexported struct fields, channels and functions are still shared.
+func WithCopiedInputs() RunOption {
+	return func(cfg *runConfig) {
+		cfg.copyInputs = true
+	}
+}
+
+// WithAudit records the inputs and output of every task that runs to sink,
+// with sensitive values redacted (see Secret), for example to keep a
+// compliance trail of a financial pipeline.
+//
+// Records are written once per task, after its last attempt. A task whose
+// record cannot be written fails.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithAudit(lyra.AuditFunc(func(ctx context.Context, r lyra.AuditRecord) error {
+//		return auditLog.Write(ctx, r)
+//	})))
+func WithAudit(sink AuditSink) RunOption {
+	return func(cfg *runConfig) {
+		cfg.audit = sink
+	}
+}
+
+// WithIdempotencyStore records the executions of tasks with an idempotency
+// key (see WithIdempotencyKey) in store instead of the in-memory default
+// of the Lyra instance, so they are deduplicated across processes and restarts.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithIdempotencyStore(sqlStore))
+func WithIdempotencyStore(store IdempotencyStore) RunOption {
+	return func(cfg *runConfig) {
+		cfg.idempotency = store
+	}
+}
@@ -0,0 +1,340 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestNewRunConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := newRunConfig(nil)
+	require.NotEmpty(t, cfg.runID)
+	require.Zero(t, cfg.timeout)
+
+	cfg = newRunConfig([]RunOption{nil, WithRunID("abc"), WithRunTimeout(time.Second)})
+	require.Equal(t, "abc", cfg.runID)
+	require.Equal(t, time.Second, cfg.timeout)
+
+	cfg = newRunConfig([]RunOption{WithRunID("")})
+	require.NotEmpty(t, cfg.runID, "empty run id falls back to a generated one")
+}
+
+func TestRunWithRunTimeout(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fast", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("slow", func(ctx context.Context, val int) (int, error) {
+			select {
+			case <-time.After(time.Second):
+				return val, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}, Use("fast")).
+		Do("after", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("slow"))
+
+	start := time.Now()
+	result, err := l.Run(context.Background(), nil, WithRunTimeout(50*time.Millisecond))
+
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.Nil(t, result)
+	require.ErrorIs(t, err, errors.ErrRunTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var timeoutErr *errors.RunTimeoutError
+	require.True(t, stderr.As(err, &timeoutErr))
+	require.Equal(t, 50*time.Millisecond, timeoutErr.Timeout)
+	require.Equal(t, []string{"after", "slow"}, timeoutErr.Pending)
+}
+
+func TestRunWithRunTimeoutIgnoredByTask(t *testing.T) {
+	t.Parallel()
+
+	// The first stage ignores ctx, but the budget still stops later stages.
+	l := New().
+		Do("stubborn", func(ctx context.Context) (int, error) {
+			time.Sleep(80 * time.Millisecond)
+			return 1, nil
+		}).
+		Do("next", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("stubborn"))
+
+	result, err := l.Run(context.Background(), nil, WithRunTimeout(20*time.Millisecond))
+
+	require.Nil(t, result)
+	var timeoutErr *errors.RunTimeoutError
+	require.True(t, stderr.As(err, &timeoutErr))
+	require.Equal(t, []string{"next"}, timeoutErr.Pending)
+}
+
+func TestRunWithRunTimeoutNotExceeded(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fast", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+
+	result, err := l.Run(context.Background(), nil, WithRunTimeout(time.Second))
+	require.NoError(t, err)
+	got, err := result.Get("fast")
+	require.NoError(t, err)
+	require.Equal(t, 1, got)
+}
+
+func TestRunParentCancelIsNotRunTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l := New().
+		Do("task", func(ctx context.Context) (int, error) {
+			return 0, ctx.Err()
+		})
+
+	_, err := l.Run(ctx, nil, WithRunTimeout(time.Second))
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, errors.ErrRunTimeout)
+}
+
+func TestRunWithProgress(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("source", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("left", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("source")).
+		Do("right", func(ctx context.Context, val int) error {
+			return nil
+		}, Use("source")).
+		Do("sink", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("left"))
+
+	var (
+		dones  []int
+		totals []int
+		tasks  []string
+	)
+	_, err := l.Run(context.Background(), nil, WithProgress(func(done, total int, lastTask string) {
+		dones = append(dones, done)
+		totals = append(totals, total)
+		tasks = append(tasks, lastTask)
+	}))
+
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 4, 4, 4}, totals)
+	require.Equal(t, []int{1, 2, 3, 4}, dones)
+	require.Equal(t, "source", tasks[0])
+	require.ElementsMatch(t, []string{"left", "right"}, tasks[1:3])
+	require.Equal(t, "sink", tasks[3])
+}
+
+func TestRunWithProgressStopsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("ok", func(ctx context.Context) error {
+			return nil
+		}).
+		Do("fail", func(ctx context.Context) (int, error) {
+			return 0, errTaskFailed
+		}).
+		Do("other", func(ctx context.Context, _ int) error {
+			return nil
+		}, UseRun("value")).
+		Do("never", func(ctx context.Context, _ int) error {
+			return nil
+		}, Use("fail"))
+
+	var (
+		calls  int
+		totals []int
+	)
+	_, err := l.Run(context.Background(), map[string]any{"value": 1}, WithProgress(func(done, total int, _ string) {
+		calls++
+		totals = append(totals, total)
+	}))
+
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Equal(t, 2, calls)
+	require.Equal(t, []int{4, 4}, totals)
+}
+
+func TestRunWithInputSnapshots(t *testing.T) {
+	t.Parallel()
+
+	build := func() *Lyra {
+		return New().
+			Do("fetchUser", func(ctx context.Context, id int) (User, error) {
+				return User{ID: id, Name: "Alice"}, nil
+			}, UseRun("userID")).
+			Do("charge", func(ctx context.Context, name string, token string) error {
+				return errTaskFailed
+			}, Use("fetchUser", "Name"), Secret(UseRun("token")))
+	}
+	runInputs := map[string]any{"userID": 7, "token": "secret-token"}
+
+	_, err := build().Run(context.Background(), runInputs)
+	require.ErrorIs(t, err, errTaskFailed)
+	var target *errors.TaskInputsError
+	require.False(t, stderr.As(err, &target), "snapshots are off by default")
+
+	_, err = build().Run(context.Background(), runInputs, WithInputSnapshots())
+	require.ErrorIs(t, err, errTaskFailed)
+	require.ErrorAs(t, err, &target)
+	require.Equal(t, "charge", target.TaskID)
+	require.Equal(t, []errors.InputSnapshot{
+		{Param: 2, Source: "fetchUser", Field: []string{"Name"}, Value: "Alice"},
+		{Param: 3, Source: "token", Value: Redacted},
+	}, target.Inputs)
+	require.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestRunWithRetainedOutputs(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		opts []RunOption
+		keys []string
+	}{
+		{name: "all outputs by default", keys: []string{"a", "b", "c", "d", "seed"}},
+		{name: "retained outputs", opts: []RunOption{WithRetainedOutputs("b", "d")}, keys: []string{"b", "d", "seed"}},
+		{name: "no outputs", opts: []RunOption{WithRetainedOutputs()}, keys: []string{"seed"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			add := func(ctx context.Context, x, y int) (int, error) { return x + y, nil }
+			l := New().
+				Do("a", func(ctx context.Context, seed int) (int, error) { return seed, nil }, UseRun("seed")).
+				Do("b", add, Use("a"), Use("a")).
+				Do("c", add, Use("a"), UseRun("seed")).
+				Do("d", add, Use("b"), Use("c"))
+
+			result, err := l.Run(context.Background(), map[string]any{"seed": 1}, tc.opts...)
+
+			require.NoError(t, err)
+			all := map[string]any{"seed": 1, "a": 1, "b": 2, "c": 2, "d": 4}
+			want := make(map[string]any, len(tc.keys))
+			for _, key := range tc.keys {
+				want[key] = all[key]
+			}
+			require.Equal(t, want, result.Redacted())
+		})
+	}
+}
+
+func TestRetainedOutputsEvictedAfterConsumers(t *testing.T) {
+	t.Parallel()
+
+	deps := map[string][]string{"a": nil, "b": {"a"}, "c": {"a"}}
+	state := newRunState(newRunConfig([]RunOption{WithRetainedOutputs()}), NewResult(), deps, [][]string{{"a"}, {"b", "c"}})
+
+	state.markCompleted(0, "a", 1, true)
+	require.Equal(t, map[string]any{"a": 1}, state.result.Redacted())
+
+	state.markCompleted(1, "b", 2, true)
+	require.Equal(t, map[string]any{"a": 1}, state.result.Redacted(), "c has not read a yet and b has no consumer")
+
+	state.markFailed(1, "c", errTaskFailed)
+	require.Empty(t, state.result.Redacted())
+}
+
+func TestRetainedOutputsEvictedAfterUnstartedConsumers(t *testing.T) {
+	t.Parallel()
+
+	deps := map[string][]string{"a": nil, "b": {"a"}, "c": {"a"}}
+	state := newRunState(newRunConfig([]RunOption{WithRetainedOutputs()}), NewResult(), deps, [][]string{{"a"}, {"b", "c"}})
+
+	state.markCompleted(0, "a", 1, true)
+	state.markCompleted(1, "b", 2, true)
+	require.Equal(t, map[string]any{"a": 1}, state.result.Redacted(), "c has not read a yet")
+
+	require.Equal(t, []string{"c"}, state.markCanceled())
+	require.Empty(t, state.result.Redacted())
+}
+
+func TestRunWithCopiedInputs(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		opts []RunOption
+		want []int
+	}{
+		{name: "shared by default", want: []int{-1, 2, 3}},
+		{name: "copied", opts: []RunOption{WithCopiedInputs()}, want: []int{1, 2, 3}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().
+				Do("ids", func(ctx context.Context) ([]int, error) {
+					return []int{1, 2, 3}, nil
+				}).
+				Do("negate", func(ctx context.Context, ids []int) error {
+					ids[0] = -ids[0]
+					return nil
+				}, Use("ids"))
+
+			result, err := l.Run(context.Background(), nil, tc.opts...)
+
+			require.NoError(t, err)
+			ids, err := result.Get("ids")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, ids)
+		})
+	}
+}
+
+func TestRunWithInputProvider(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]any{"region": "eu", "greeting": "Hi", "unused": true}
+	var lookups []string
+	provider := InputProviderFunc(func(key string) (any, bool) {
+		lookups = append(lookups, key)
+		value, ok := config[key]
+		return value, ok
+	})
+
+	l := New().Do("message", func(ctx context.Context, region, greeting, name string) (string, error) {
+		return greeting + " " + name + " from " + region, nil
+	}, UseRun("region"), UseRun("greeting"), UseRun("name"))
+
+	inputs := map[string]any{"name": "Alice", "greeting": "Hello"}
+	result, err := l.Run(context.Background(), inputs, WithInputProvider(provider))
+
+	require.NoError(t, err)
+	message, err := result.Get("message")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Alice from eu", message, "run inputs take precedence")
+	require.Equal(t, []string{"region"}, lookups)
+	require.Len(t, inputs, 2, "run inputs are not modified")
+
+	_, err = l.Run(context.Background(), nil, WithInputProvider(provider))
+	require.ErrorIs(t, err, errors.ErrMissingRunInput)
+}
@@ -0,0 +1,184 @@
+package lyra
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// OutputLimit bounds the size of the results stored by a run, protecting
+// the process from a task accidentally returning a huge value.
+type OutputLimit struct {
+	// MaxBytes is the largest size of a result; zero or less means no
+	// limit. Tasks override it with WithMaxOutputSize.
+	MaxBytes int64
+	// Sizer returns the size of a result in bytes; nil uses EstimateSize.
+	Sizer func(output any) int64
+	// OnExceed is called with the result of a task that exceeds the limit
+	// and returns the value stored instead, for example a truncated result
+	// or a reference to a copy spilled to a blob store. The value must be
+	// assignable to the task's result type. If OnExceed is nil or returns
+	// an error, the task fails.
+	OnExceed func(ctx context.Context, taskID string, output any, size int64) (any, error)
+}
+
+// WithOutputLimit bounds the size of every task result of the run. Results
+// exceeding it are handled by limit.OnExceed, and otherwise fail their task
+// with ErrOutputTooLarge.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs, lyra.WithOutputLimit(lyra.OutputLimit{MaxBytes: 64 << 20}))
+func WithOutputLimit(limit OutputLimit) RunOption {
+	return func(cfg *runConfig) {
+		cfg.outputLimit = limit
+	}
+}
+
+// WithMaxOutputSize bounds the size of the task's result to n bytes,
+// overriding OutputLimit.MaxBytes. Without WithOutputLimit the size is
+// estimated with EstimateSize and a larger result fails the task with
+// ErrOutputTooLarge.
+func WithMaxOutputSize(n int64) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.MaxOutputSize = n
+	})
+}
+
+// limitOutput returns the output of the task to store, checked against the
+// output limit of the run.
+func limitOutput(ctx context.Context, task *compiledTask, limit OutputLimit, output any) (any, error) {
+	maxBytes := limit.MaxBytes
+	if taskMax := task.GetOptions().MaxOutputSize; taskMax > 0 {
+		maxBytes = taskMax
+	}
+	if maxBytes <= 0 {
+		return output, nil
+	}
+
+	sizer := limit.Sizer
+	if sizer == nil {
+		sizer = EstimateSize
+	}
+	size := sizer(output)
+	if size <= maxBytes {
+		return output, nil
+	}
+	if limit.OnExceed == nil {
+		return nil, errors.Wrapf(errors.ErrOutputTooLarge, "%d bytes, limit %d", size, maxBytes)
+	}
+
+	replacement, err := limit.OnExceed(ctx, task.GetID(), output, size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%d bytes exceed limit %d", size, maxBytes)
+	}
+	replacement, err = typedOutput(replacement, task.GetOutputParams())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid replacement of output of %d bytes", size)
+	}
+	return replacement, nil
+}
+
+// EstimateSize estimates the memory held by v in bytes: the size of its
+// type plus the memory its strings, slices, maps, pointers and interfaces
+// refer to, counting shared pointers once. Maps are estimated from their
+// entries, ignoring their internal overhead.
+func EstimateSize(v any) int64 {
+	if v == nil {
+		return 0
+	}
+	value := reflect.ValueOf(v)
+	return int64(value.Type().Size()) + indirectSize(value, make(map[uintptr]struct{}))
+}
+
+// indirectSize returns the memory value refers to beyond its own size.
+func indirectSize(value reflect.Value, seen map[uintptr]struct{}) int64 {
+	switch value.Kind() {
+	case reflect.String:
+		return int64(value.Len())
+	case reflect.Pointer:
+		if value.IsNil() || visited(value.Pointer(), seen) {
+			return 0
+		}
+		return int64(value.Type().Elem().Size()) + indirectSize(value.Elem(), seen)
+	case reflect.Interface:
+		if value.IsNil() {
+			return 0
+		}
+		elem := value.Elem()
+		return int64(elem.Type().Size()) + indirectSize(elem, seen)
+	case reflect.Slice:
+		if value.IsNil() || visited(value.Pointer(), seen) {
+			return 0
+		}
+		size := int64(value.Cap()) * int64(value.Type().Elem().Size())
+		return size + elementsSize(value, seen)
+	case reflect.Array:
+		return elementsSize(value, seen)
+	case reflect.Map:
+		if value.IsNil() || visited(value.Pointer(), seen) {
+			return 0
+		}
+		typ := value.Type()
+		size := int64(value.Len()) * int64(typ.Key().Size()+typ.Elem().Size())
+		if refersToMemory(typ.Key()) || refersToMemory(typ.Elem()) {
+			iter := value.MapRange()
+			for iter.Next() {
+				size += indirectSize(iter.Key(), seen) + indirectSize(iter.Value(), seen)
+			}
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := range value.NumField() {
+			size += indirectSize(value.Field(i), seen)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// elementsSize returns the memory the elements of a slice or array refer
+// to.
+func elementsSize(value reflect.Value, seen map[uintptr]struct{}) int64 {
+	if !refersToMemory(value.Type().Elem()) {
+		return 0
+	}
+	var size int64
+	for i := range value.Len() {
+		size += indirectSize(value.Index(i), seen)
+	}
+	return size
+}
+
+// visited reports whether ptr was seen before, recording it otherwise.
+func visited(ptr uintptr, seen map[uintptr]struct{}) bool {
+	if _, ok := seen[ptr]; ok {
+		return true
+	}
+	seen[ptr] = struct{}{}
+	return false
+}
+
+// refersToMemory reports whether values of typ may refer to memory beyond
+// their own size.
+func refersToMemory(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Array:
+		return refersToMemory(typ.Elem())
+	case reflect.Struct:
+		for i := range typ.NumField() {
+			if refersToMemory(typ.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.String, reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	default:
+		return false // numbers, bools, and funcs, chans and unsafe pointers, which are not followed
+	}
+}
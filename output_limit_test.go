@@ -0,0 +1,132 @@
+package lyra
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestWithOutputLimit(t *testing.T) {
+	t.Parallel()
+
+	big := func(ctx context.Context) (string, error) { return strings.Repeat("x", 1024), nil }
+	small := func(ctx context.Context) (string, error) { return "ok", nil }
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+
+		l := New().Do("big", big).Do("small", small)
+		_, err := l.Run(context.Background(), nil, WithOutputLimit(OutputLimit{MaxBytes: 512}))
+		require.ErrorIs(t, err, errors.ErrOutputTooLarge)
+		require.ErrorContains(t, err, "limit 512")
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		t.Parallel()
+
+		l := New().Do("big", big).Do("small", small)
+		result, err := l.Run(context.Background(), nil, WithOutputLimit(OutputLimit{
+			MaxBytes: 512,
+			Sizer:    func(output any) int64 { return int64(len(output.(string))) },
+			OnExceed: func(ctx context.Context, taskID string, output any, size int64) (any, error) {
+				require.Equal(t, "big", taskID)
+				require.EqualValues(t, 1024, size)
+				return output.(string)[:512], nil
+			},
+		}))
+		require.NoError(t, err)
+
+		value, err := result.Get("big")
+		require.NoError(t, err)
+		require.Len(t, value, 512)
+		value, err = result.Get("small")
+		require.NoError(t, err)
+		require.Equal(t, "ok", value)
+	})
+
+	t.Run("invalid replacement", func(t *testing.T) {
+		t.Parallel()
+
+		l := New().Do("big", big)
+		_, err := l.Run(context.Background(), nil, WithOutputLimit(OutputLimit{
+			MaxBytes: 512,
+			OnExceed: func(ctx context.Context, taskID string, output any, size int64) (any, error) {
+				return 42, nil
+			},
+		}))
+		require.ErrorIs(t, err, errors.ErrInvalidParamType)
+	})
+
+	t.Run("nil replacement", func(t *testing.T) {
+		t.Parallel()
+
+		l := New().
+			Do("big", func(ctx context.Context) ([]string, error) {
+				return []string{strings.Repeat("x", 1024)}, nil
+			}).
+			Do("count", func(ctx context.Context, values []string) (int, error) {
+				return len(values), nil
+			}, Use("big"))
+		result, err := l.Run(context.Background(), nil, WithOutputLimit(OutputLimit{
+			MaxBytes: 512,
+			OnExceed: func(ctx context.Context, taskID string, output any, size int64) (any, error) {
+				return nil, nil // dropped
+			},
+		}))
+		require.NoError(t, err)
+
+		value, err := result.Get("big")
+		require.NoError(t, err)
+		require.Equal(t, []string(nil), value)
+		value, err = result.Get("count")
+		require.NoError(t, err)
+		require.Equal(t, 0, value)
+	})
+
+	t.Run("task override", func(t *testing.T) {
+		t.Parallel()
+
+		l := New().
+			Do("big", big, WithMaxOutputSize(4096)).
+			Do("small", small, WithMaxOutputSize(1))
+		_, err := l.Run(context.Background(), nil, WithOutputLimit(OutputLimit{MaxBytes: 512}))
+		require.ErrorIs(t, err, errors.ErrOutputTooLarge)
+		require.ErrorContains(t, err, "small")
+	})
+}
+
+func TestEstimateSize(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name string
+		Next *node
+	}
+	cycle := &node{Name: "abcd"}
+	cycle.Next = cycle
+
+	tcs := []struct {
+		name  string
+		value any
+		want  int64
+	}{
+		{name: "nil", value: nil, want: 0},
+		{name: "int", value: 1, want: 8},
+		{name: "string", value: "abcd", want: 16 + 4},
+		{name: "bytes", value: make([]byte, 10, 100), want: 24 + 100},
+		{name: "strings", value: []string{"ab", "cd"}, want: 24 + 2*16 + 4},
+		{name: "map", value: map[int64]int64{1: 1}, want: 8 + 16},
+		{name: "cycle", value: cycle, want: 8 + 24 + 4},
+		{name: "array", value: [2]string{"ab", "c"}, want: 32 + 3},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, EstimateSize(tc.value))
+		})
+	}
+}
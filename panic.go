@@ -0,0 +1,101 @@
+package lyra
+
+import (
+	stderr "errors"
+	"runtime/debug"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// PanicPolicy decides what a panic in a task does to the run. A panicking
+// task always fails with an *errors.PanicError holding the panic value and
+// the stack trace.
+type PanicPolicy int
+
+const (
+	// PanicFailRun fails the task without retrying it, which fails the
+	// run. It is the policy of tasks registered without WithPanicPolicy.
+	PanicFailRun PanicPolicy = iota
+	// PanicFailTaskOnly treats the panic like an error returned by the
+	// task, so it is retried as set with WithRetry.
+	PanicFailTaskOnly
+	// PanicSkipDependents fails the task and skips the tasks depending on
+	// it, while the rest of the run continues and can succeed. Tasks
+	// reading the task with Optional run without its output.
+	PanicSkipDependents
+)
+
+// String returns the name of the policy.
+func (p PanicPolicy) String() string {
+	switch p {
+	case PanicFailTaskOnly:
+		return "fail_task_only"
+	case PanicSkipDependents:
+		return "skip_dependents"
+	default:
+		return "fail_run"
+	}
+}
+
+// WithPanicPolicy sets what a panic in the task does to the run, for
+// example so that a best-effort enrichment task never takes down the
+// pipeline.
+//
+// Example:
+//
+//	l.Do("enrich", enrich, lyra.Use("order"), lyra.WithPanicPolicy(lyra.PanicSkipDependents))
+func WithPanicPolicy(p PanicPolicy) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.PanicPolicy = int(p)
+	})
+}
+
+// recoverPanic turns a panic of the task into its error, according to the
+// task's policy. It must be deferred.
+func recoverPanic(task *compiledTask, err *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	*err = &errors.PanicError{Value: value, Stack: debug.Stack()}
+	if PanicPolicy(task.GetOptions().PanicPolicy) != PanicFailTaskOnly {
+		*err = Permanent(*err)
+	}
+}
+
+// skipsDependents reports whether the failure of the task with err leaves
+// the run going, skipping the dependents of the task.
+func skipsDependents(task *compiledTask, err error) bool {
+	var panicErr *errors.PanicError
+	return PanicPolicy(task.GetOptions().PanicPolicy) == PanicSkipDependents && stderr.As(err, &panicErr)
+}
+
+// skipDependents records that the task failed with err without failing the
+// run, and marks every task that transitively depends on it, other than
+// through optional inputs, as skipped.
+func (s *runState) skipDependents(taskID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dependents := s.strictDependents()
+	queue := slices.Clone(dependents[taskID])
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if s.statuses[id] != TaskPending {
+			continue
+		}
+
+		s.statuses[id] = TaskSkipped
+		s.notifyFinished(id)
+		s.release(id)
+		s.emit(Event{Type: EventTaskSkipped, Stage: s.stageOf(id), TaskID: id})
+		queue = append(queue, dependents[id]...)
+	}
+
+	s.result.mu.Lock()
+	defer s.result.mu.Unlock()
+	s.result.setFailure(taskID, err)
+}
@@ -0,0 +1,91 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestPanicFailRun(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	l := New().Do("explode", func(ctx context.Context) error {
+		attempts++
+		panic("boom")
+	}, WithRetry(2))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrTaskPanicked)
+	require.Equal(t, 1, attempts)
+
+	var panicErr *errors.PanicError
+	require.True(t, stderr.As(err, &panicErr))
+	require.Equal(t, "boom", panicErr.Value)
+	require.NotEmpty(t, panicErr.Stack)
+}
+
+func TestPanicFailTaskOnly(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	l := New().Do("flaky", func(ctx context.Context) (int, error) {
+		attempts++
+		if attempts == 1 {
+			panic("boom")
+		}
+		return attempts, nil
+	}, WithRetry(1), WithPanicPolicy(PanicFailTaskOnly))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("flaky")
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestPanicSkipDependents(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("order", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("enrich", func(ctx context.Context, _ int) (string, error) {
+			panic("boom")
+		}, Use("order"), WithPanicPolicy(PanicSkipDependents)).
+		Do("tag", func(ctx context.Context, s string) (string, error) { return s, nil }, Use("enrich")).
+		Do("publish", func(ctx context.Context, s string) error { return nil }, Use("tag")).
+		Do("render", func(ctx context.Context, _ int, s string) (string, error) {
+			return "plain" + s, nil
+		}, Use("order"), Optional(Use("enrich")))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("render")
+	require.NoError(t, err)
+	require.Equal(t, "plain", value)
+	require.ErrorIs(t, result.Failures()["enrich"], errors.ErrTaskPanicked)
+
+	statuses := make(map[string]TaskStatus)
+	for _, task := range l.RecentRuns()[0].Tasks {
+		statuses[task.ID] = task.Status
+	}
+	require.Equal(t, map[string]TaskStatus{
+		"order":   TaskSucceeded,
+		"enrich":  TaskFailed,
+		"tag":     TaskSkipped,
+		"publish": TaskSkipped,
+		"render":  TaskSucceeded,
+	}, statuses)
+}
+
+func TestPanicPolicyString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "fail_run", PanicFailRun.String())
+	require.Equal(t, "fail_task_only", PanicFailTaskOnly.String())
+	require.Equal(t, "skip_dependents", PanicSkipDependents.String())
+}
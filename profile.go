@@ -0,0 +1,155 @@
+package lyra
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TaskProfile holds the timings of a task recorded with WithProfiling.
+type TaskProfile struct {
+	ID    string
+	Stage int
+	// Wait is how long the task waited to start once its stage started,
+	// for example for a worker or a concurrency slot.
+	Wait time.Duration
+	// Wall is how long the task ran, including waits for bulkheads and
+	// locks, retries and fallbacks.
+	Wall time.Duration
+	// Allocs and Bytes are the heap allocations made while the task ran,
+	// recorded with WithAllocationProfiling; zero otherwise.
+	Allocs uint64
+	Bytes  uint64
+}
+
+// RunProfile holds the timings of the tasks of a run recorded with
+// WithProfiling.
+//
+// It does not record CPU time per task: Go does not account CPU time per
+// goroutine, so wall time is the closest measure. To attribute CPU time to
+// tasks, take a CPU profile during the run and group it by the TaskLabel
+// profiler label.
+type RunProfile struct {
+	// Tasks holds every task that started, slowest first.
+	Tasks []TaskProfile
+}
+
+// Top returns the n slowest tasks, or every task if there are fewer.
+func (p *RunProfile) Top(n int) []TaskProfile {
+	return p.Tasks[:min(n, len(p.Tasks))]
+}
+
+// String returns a summary of the slowest tasks.
+func (p *RunProfile) String() string {
+	var b strings.Builder
+	b.WriteString("top slow tasks:")
+	for i, task := range p.Top(profileTopTasks) {
+		fmt.Fprintf(&b, "\n%3d. %s: %s (waited %s, stage %d", i+1, task.ID, task.Wall, task.Wait, task.Stage)
+		if task.Allocs > 0 {
+			fmt.Fprintf(&b, ", %d allocs, %d B", task.Allocs, task.Bytes)
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// profileTopTasks is the number of tasks listed by RunProfile.String.
+const profileTopTasks = 10
+
+// WithProfiling records how long every task of the run waited and ran,
+// reported by RunSummary.Profile, to show which tasks to optimize first.
+// CPU time is not recorded (see RunProfile).
+//
+// Example:
+//
+//	_, err := l.Run(ctx, inputs, lyra.WithProfiling())
+//	log.Print(l.RecentRuns()[0].Profile)
+func WithProfiling() RunOption {
+	return func(cfg *runConfig) {
+		cfg.profiling = true
+	}
+}
+
+// WithAllocationProfiling is WithProfiling, also recording the heap
+// allocations made while each task ran, to find the tasks using the most
+// memory. Allocations are sampled from the process-wide runtime.MemStats
+// around every task, which stops the world briefly, so it is meant for
+// diagnosis rather than production. The counts are exact only when tasks
+// run one at a time, for example with WithConcurrency(1); otherwise they
+// include the allocations of whatever else ran at the same time.
+func WithAllocationProfiling() RunOption {
+	return func(cfg *runConfig) {
+		cfg.profiling = true
+		cfg.allocations = true
+	}
+}
+
+// taskAllocations are the heap allocations made while a task ran.
+type taskAllocations struct {
+	allocs uint64
+	bytes  uint64
+}
+
+// callWithAllocations calls the task, recording the heap allocations made
+// meanwhile if the run profiles them.
+func callWithAllocations(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	if !state.cfg.allocations {
+		return callWithFallback(ctx, stageIdx, task, state)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	output, hasOutput, err = callWithFallback(ctx, stageIdx, task, state)
+	runtime.ReadMemStats(&after)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.allocations == nil {
+		state.allocations = make(map[string]taskAllocations)
+	}
+	state.allocations[task.GetID()] = taskAllocations{
+		allocs: after.Mallocs - before.Mallocs,
+		bytes:  after.TotalAlloc - before.TotalAlloc,
+	}
+	return output, hasOutput, err
+}
+
+// profile returns the timings of the tasks that started, as of end.
+// Callers must hold s.mu.
+func (s *runState) profile(end time.Time) *RunProfile {
+	profile := &RunProfile{Tasks: make([]TaskProfile, 0, len(s.started))}
+	for i, stage := range s.stages {
+		for _, taskID := range stage {
+			started, ok := s.started[taskID]
+			if !ok {
+				continue
+			}
+			finished, ok := s.finished[taskID]
+			if !ok {
+				finished = end
+			}
+			allocations := s.allocations[taskID]
+			profile.Tasks = append(profile.Tasks, TaskProfile{
+				ID:     taskID,
+				Stage:  i,
+				Wait:   max(started.Sub(s.stageSummaries[i].Start), 0),
+				Wall:   finished.Sub(started),
+				Allocs: allocations.allocs,
+				Bytes:  allocations.bytes,
+			})
+		}
+	}
+	slices.SortStableFunc(profile.Tasks, func(a, b TaskProfile) int {
+		return cmp.Compare(b.Wall, a.Wall)
+	})
+	return profile
+}
@@ -0,0 +1,73 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProfiling(t *testing.T) {
+	t.Parallel()
+
+	sleep := func(d time.Duration) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			time.Sleep(d)
+			return nil
+		}
+	}
+	l := New().
+		Do("fast", sleep(0)).
+		Do("slow", sleep(20*time.Millisecond)).
+		Do("medium", sleep(5*time.Millisecond))
+
+	_, err := l.Run(context.Background(), nil, WithConcurrency(1))
+	require.NoError(t, err)
+	require.Nil(t, l.RecentRuns()[0].Profile)
+
+	_, err = l.Run(context.Background(), nil, WithProfiling(), WithConcurrency(1))
+	require.NoError(t, err)
+	profile := l.RecentRuns()[0].Profile
+	require.NotNil(t, profile)
+	require.Len(t, profile.Tasks, 3)
+
+	top := profile.Top(2)
+	require.Len(t, top, 2)
+	require.Equal(t, "slow", top[0].ID)
+	require.Equal(t, "medium", top[1].ID)
+	require.GreaterOrEqual(t, top[0].Wall, 20*time.Millisecond)
+	require.Len(t, profile.Top(10), 3)
+
+	var waited time.Duration
+	for _, task := range profile.Tasks {
+		waited = max(waited, task.Wait)
+	}
+	require.GreaterOrEqual(t, waited, 5*time.Millisecond, "tasks queue behind each other with one worker")
+	require.Contains(t, profile.String(), "  1. slow: ")
+}
+
+func TestWithAllocationProfiling(t *testing.T) {
+	t.Parallel()
+
+	var sink []byte
+	l := New().
+		Do("hog", func(ctx context.Context) (int, error) {
+			sink = make([]byte, 1<<20)
+			return len(sink), nil
+		}).
+		Do("light", func(ctx context.Context, n int) (int, error) { return n, nil }, Use("hog"))
+
+	_, err := l.Run(context.Background(), nil, WithAllocationProfiling(), WithConcurrency(1))
+	require.NoError(t, err)
+	profile := l.RecentRuns()[0].Profile
+	require.NotNil(t, profile)
+
+	tasks := make(map[string]TaskProfile)
+	for _, task := range profile.Tasks {
+		tasks[task.ID] = task
+	}
+	require.GreaterOrEqual(t, tasks["hog"].Bytes, uint64(1<<20))
+	require.Positive(t, tasks["hog"].Allocs)
+	require.Contains(t, profile.String(), " allocs, ")
+}
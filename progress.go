@@ -0,0 +1,86 @@
+package lyra
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressFunc receives the number of completed tasks, the total number of
+// tasks in the run, and the ID of the task that just completed.
+type ProgressFunc func(done, total int, lastTask string)
+
+// Progress is a snapshot of a run's progress passed to WithProgressDetail.
+type Progress struct {
+	Done     int    // Number of tasks completed successfully
+	Total    int    // Number of tasks in the run
+	LastTask string // ID of the task that just completed
+
+	Elapsed time.Duration // Time since the run started
+	// Remaining is the expected time until the run finishes, computed as the
+	// longest chain of unfinished tasks weighted by their historical
+	// durations. It is zero without a DurationHistory.
+	Remaining time.Duration
+	// Estimated reports whether Remaining is backed by history for every
+	// unfinished task. Tasks without history count as taking no time.
+	Estimated bool
+}
+
+// DurationHistory provides the expected duration of tasks, typically derived
+// from previous runs, for estimating the remaining time of a run.
+type DurationHistory interface {
+	// ExpectedDuration returns the expected duration of the task and false
+	// if nothing is known about it.
+	ExpectedDuration(taskID string) (time.Duration, bool)
+}
+
+// DurationRecorder is implemented by a DurationHistory that learns from the
+// durations observed while running.
+type DurationRecorder interface {
+	// RecordDuration records an observed successful execution of the task.
+	RecordDuration(taskID string, d time.Duration)
+}
+
+// MovingAverageHistory is an in-memory DurationHistory that keeps an
+// exponential moving average of each task's observed durations.
+//
+// It is safe for concurrent use and is meant to be shared across runs.
+type MovingAverageHistory struct {
+	mu      sync.RWMutex
+	alpha   float64
+	average map[string]time.Duration
+}
+
+// NewMovingAverageHistory creates an empty MovingAverageHistory.
+//
+// alpha in (0, 1] is the weight given to the newest observation; values
+// outside that range are treated as 1, which remembers only the last run.
+func NewMovingAverageHistory(alpha float64) *MovingAverageHistory {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 1
+	}
+	return &MovingAverageHistory{
+		alpha:   alpha,
+		average: make(map[string]time.Duration),
+	}
+}
+
+// ExpectedDuration returns the moving average duration of the task.
+func (h *MovingAverageHistory) ExpectedDuration(taskID string) (time.Duration, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	d, ok := h.average[taskID]
+	return d, ok
+}
+
+// RecordDuration folds d into the task's moving average.
+func (h *MovingAverageHistory) RecordDuration(taskID string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	prev, ok := h.average[taskID]
+	if !ok {
+		h.average[taskID] = d
+		return
+	}
+	h.average[taskID] = prev + time.Duration(h.alpha*float64(d-prev))
+}
@@ -0,0 +1,167 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticHistory map[string]time.Duration
+
+func (h staticHistory) ExpectedDuration(taskID string) (time.Duration, bool) {
+	d, ok := h[taskID]
+	return d, ok
+}
+
+func TestMovingAverageHistory(t *testing.T) {
+	t.Parallel()
+
+	h := NewMovingAverageHistory(0.5)
+
+	_, ok := h.ExpectedDuration("task")
+	require.False(t, ok)
+
+	h.RecordDuration("task", 100*time.Millisecond)
+	d, ok := h.ExpectedDuration("task")
+	require.True(t, ok)
+	require.Equal(t, 100*time.Millisecond, d)
+
+	h.RecordDuration("task", 200*time.Millisecond)
+	d, _ = h.ExpectedDuration("task")
+	require.Equal(t, 150*time.Millisecond, d)
+
+	for _, alpha := range []float64{0, -1, 2} {
+		h = NewMovingAverageHistory(alpha)
+		h.RecordDuration("task", time.Second)
+		h.RecordDuration("task", 2*time.Second)
+		d, _ = h.ExpectedDuration("task")
+		require.Equal(t, 2*time.Second, d, "invalid alpha keeps only the last observation")
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	t.Parallel()
+
+	//     a(10) -> b(20) -> d(5)
+	//     a(10) -> c(40)
+	deps := map[string][]string{
+		"a": {},
+		"b": {"a"},
+		"c": {"a"},
+		"d": {"b"},
+	}
+	stages := [][]string{{"a"}, {"b", "c"}, {"d"}}
+	history := staticHistory{
+		"a": 10 * time.Second,
+		"b": 20 * time.Second,
+		"c": 40 * time.Second,
+		"d": 5 * time.Second,
+	}
+	now := time.Now()
+
+	tcs := []struct {
+		name      string
+		history   DurationHistory
+		completed []string
+		started   map[string]time.Time
+		remaining time.Duration
+		estimated bool
+	}{
+		{
+			name: "no history",
+		},
+		{
+			name:      "nothing started",
+			history:   history,
+			remaining: 50 * time.Second,
+			estimated: true,
+		},
+		{
+			name:      "critical path through c",
+			history:   history,
+			completed: []string{"a"},
+			remaining: 40 * time.Second,
+			estimated: true,
+		},
+		{
+			name:      "running task contributes what is left",
+			history:   history,
+			completed: []string{"a", "b"},
+			started:   map[string]time.Time{"c": now.Add(-30 * time.Second)},
+			remaining: 10 * time.Second,
+			estimated: true,
+		},
+		{
+			name:      "overrunning task contributes nothing",
+			history:   history,
+			completed: []string{"a", "b"},
+			started:   map[string]time.Time{"c": now.Add(-time.Minute)},
+			remaining: 5 * time.Second,
+			estimated: true,
+		},
+		{
+			name:      "unknown task",
+			history:   staticHistory{"a": 10 * time.Second, "c": 40 * time.Second},
+			remaining: 50 * time.Second,
+			estimated: false,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			state := newRunState(&runConfig{history: tc.history}, NewResult(), deps, stages)
+			for _, taskID := range tc.completed {
+				state.statuses[taskID] = TaskSucceeded
+			}
+			for taskID, startedAt := range tc.started {
+				state.statuses[taskID] = TaskRunning
+				state.started[taskID] = startedAt
+			}
+
+			remaining, estimated := state.estimateRemaining(now)
+			require.Equal(t, tc.estimated, estimated)
+			require.Equal(t, tc.remaining, remaining)
+		})
+	}
+}
+
+func TestRunWithProgressDetail(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("first", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("second", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("first"))
+
+	history := NewMovingAverageHistory(1)
+	history.RecordDuration("first", time.Hour)
+	history.RecordDuration("second", time.Hour)
+
+	var reports []Progress
+	_, err := l.Run(context.Background(), nil,
+		WithDurationHistory(history),
+		WithProgressDetail(func(p Progress) {
+			reports = append(reports, p)
+		}))
+
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	require.Equal(t, 1, reports[0].Done)
+	require.Equal(t, 2, reports[0].Total)
+	require.Equal(t, "first", reports[0].LastTask)
+	require.True(t, reports[0].Estimated)
+	require.Equal(t, time.Hour, reports[0].Remaining)
+
+	require.Equal(t, 2, reports[1].Done)
+	require.True(t, reports[1].Estimated)
+	require.Zero(t, reports[1].Remaining)
+	require.GreaterOrEqual(t, reports[1].Elapsed, reports[0].Elapsed)
+
+	d, _ := history.ExpectedDuration("first")
+	require.Less(t, d, time.Hour, "observed durations are recorded into the history")
+}
@@ -0,0 +1,73 @@
+package lyra
+
+import "maps"
+
+// InputProvider supplies the runtime inputs missing from the map passed to
+// Run, for example from a configuration source, so long-lived services can
+// change pipeline parameters without code changes.
+//
+// It is asked for every key read by UseRun, UseTemplate or declared with
+// Require, on every run. Implementations must be safe for concurrent use.
+//
+// Adapting a viper or koanf instance takes a few lines:
+//
+//	provider := lyra.InputProviderFunc(func(key string) (any, bool) {
+//		return v.Get(key), v.IsSet(key)
+//	})
+type InputProvider interface {
+	// Lookup returns the value of key and whether it is set.
+	Lookup(key string) (any, bool)
+}
+
+// InputProviderFunc adapts a function to the InputProvider interface.
+type InputProviderFunc func(key string) (any, bool)
+
+// Lookup calls f.
+func (f InputProviderFunc) Lookup(key string) (any, bool) {
+	return f(key)
+}
+
+// WithInputProvider takes the runtime inputs missing from the map passed to
+// Run from provider. Inputs passed to Run take precedence.
+//
+// Example:
+//
+//	result, err := l.Run(ctx, map[string]any{"userID": id}, lyra.WithInputProvider(config))
+func WithInputProvider(provider InputProvider) RunOption {
+	return func(cfg *runConfig) {
+		cfg.inputProvider = provider
+	}
+}
+
+// provideInputs returns runInputs completed with the values provider has
+// for the required keys; runInputs itself is never modified.
+func provideInputs(
+	runInputs map[string]any,
+	provider InputProvider,
+	requirements map[string][]inputRequirement,
+) map[string]any {
+	if provider == nil {
+		return runInputs
+	}
+	var inputs map[string]any
+	for key := range requirements {
+		if _, ok := runInputs[key]; ok {
+			continue
+		}
+		value, ok := provider.Lookup(key)
+		if !ok {
+			continue
+		}
+		if inputs == nil {
+			inputs = maps.Clone(runInputs)
+			if inputs == nil {
+				inputs = make(map[string]any)
+			}
+		}
+		inputs[key] = value
+	}
+	if inputs == nil {
+		return runInputs
+	}
+	return inputs
+}
@@ -0,0 +1,94 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// Publisher publishes a message to a topic, for example a Kafka producer or
+// an SQS client sending to the queue named by topic.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// AsyncTransport implements Transport on top of queues without request/reply
+// support. Requests carry a correlation ID and the reply topic; the
+// application consumes the reply topic and passes each message to Deliver.
+//
+// It is safe for concurrent use.
+type AsyncTransport struct {
+	publisher  Publisher
+	replyTopic string
+
+	mu      sync.Mutex
+	pending map[string]chan []byte
+}
+
+// NewAsyncTransport returns an AsyncTransport publishing requests with p and
+// asking workers to reply to replyTopic.
+func NewAsyncTransport(p Publisher, replyTopic string) *AsyncTransport {
+	return &AsyncTransport{
+		publisher:  p,
+		replyTopic: replyTopic,
+		pending:    make(map[string]chan []byte),
+	}
+}
+
+// Request publishes payload to subject and waits for the matching reply
+// delivered through Deliver, or for ctx to be done.
+func (t *AsyncTransport) Request(ctx context.Context, subject string, payload []byte) ([]byte, error) {
+	var req request
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("decode request: %w", err)
+	}
+	req.ID = lyra.NewRunID()
+	req.ReplyTo = t.replyTopic
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	replies := make(chan []byte, 1)
+	t.mu.Lock()
+	t.pending[req.ID] = replies
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, req.ID)
+		t.mu.Unlock()
+	}()
+
+	if err := t.publisher.Publish(ctx, subject, payload); err != nil {
+		return nil, fmt.Errorf("publish to %q: %w", subject, err)
+	}
+
+	select {
+	case data := <-replies:
+		return data, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+// Deliver hands a message consumed from the reply topic to the request
+// waiting for it. It reports false for messages that match no pending
+// request, such as late replies of canceled requests.
+func (t *AsyncTransport) Deliver(payload []byte) bool {
+	var rep reply
+	if err := json.Unmarshal(payload, &rep); err != nil {
+		return false
+	}
+
+	t.mu.Lock()
+	replies, ok := t.pending[rep.ID]
+	delete(t.pending, rep.ID)
+	t.mu.Unlock()
+	if ok {
+		replies <- payload
+	}
+	return ok
+}
@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// Transport sends a request to the workers listening on subject and
+// returns their reply.
+type Transport interface {
+	Request(ctx context.Context, subject string, payload []byte) ([]byte, error)
+}
+
+// ExecutorOption configures an executor created by NewExecutor.
+type ExecutorOption func(*executor)
+
+// WithSubject sets the subject a task is published to. The default is
+// "lyra.tasks." followed by the task ID.
+func WithSubject(subject func(taskID string) string) ExecutorOption {
+	return func(e *executor) {
+		e.subject = subject
+	}
+}
+
+// NewExecutor returns a lyra.Executor publishing task calls through t.
+func NewExecutor(t Transport, opts ...ExecutorOption) lyra.Executor {
+	e := &executor{
+		transport: t,
+		subject:   func(taskID string) string { return "lyra.tasks." + taskID },
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type executor struct {
+	transport Transport
+	subject   func(taskID string) string
+}
+
+func (e *executor) Execute(ctx context.Context, call lyra.TaskCall) (any, error) {
+	req := request{TaskID: call.TaskID, Args: make([]json.RawMessage, 0, len(call.Args))}
+	for i, arg := range call.Args {
+		raw, err := json.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("encode argument %d of task %q: %w", i, call.TaskID, err)
+		}
+		req.Args = append(req.Args, raw)
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode request for task %q: %w", call.TaskID, err)
+	}
+
+	data, err := e.transport.Request(ctx, e.subject(call.TaskID), payload)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch task %q: %w", call.TaskID, err)
+	}
+
+	var rep reply
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("decode reply for task %q: %w", call.TaskID, err)
+	}
+	if rep.Error != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRemoteTask, rep.Error)
+	}
+	if call.OutputType == nil {
+		return nil, nil //nolint:nilnil // tasks returning only an error have no output.
+	}
+	if len(rep.Result) == 0 {
+		return reflect.Zero(call.OutputType).Interface(), nil
+	}
+
+	result := reflect.New(call.OutputType)
+	if err := json.Unmarshal(rep.Result, result.Interface()); err != nil {
+		return nil, fmt.Errorf("decode result of task %q: %w", call.TaskID, err)
+	}
+	return result.Elem().Interface(), nil
+}
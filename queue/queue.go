@@ -0,0 +1,52 @@
+// Package queue dispatches lyra tasks to worker processes over a message
+// queue, so a single DAG can span several processes.
+//
+// The coordinator registers tasks with lyra.WithExecutor(queue.NewExecutor(t))
+// and workers serve them with a Worker. Messages are JSON encoded, so task
+// inputs and outputs must round-trip through encoding/json.
+//
+// Queues with native request/reply, such as NATS, implement Transport
+// directly:
+//
+//	type natsTransport struct{ nc *nats.Conn }
+//
+//	func (t natsTransport) Request(ctx context.Context, subject string, payload []byte) ([]byte, error) {
+//		msg, err := t.nc.RequestWithContext(ctx, subject, payload)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return msg.Data, nil
+//	}
+//
+// Queues without it, such as Kafka or SQS, use AsyncTransport, which
+// correlates replies published to a reply topic.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrRemoteTask is returned by the executor when the worker reports that
+// the task failed; the message of the remote error follows it.
+var ErrRemoteTask = errors.New("remote task failed")
+
+// ErrUnknownTask is reported by a Worker for tasks it has no function for.
+var ErrUnknownTask = errors.New("unknown task")
+
+// request is the message sent to workers.
+type request struct {
+	// ID correlates the reply with the request for AsyncTransport.
+	ID string `json:"id,omitempty"`
+	// ReplyTo is the topic the reply is published to for AsyncTransport.
+	ReplyTo string            `json:"reply_to,omitempty"`
+	TaskID  string            `json:"task_id"`
+	Args    []json.RawMessage `json:"args"`
+}
+
+// reply is the message sent back by workers.
+type reply struct {
+	ID     string          `json:"id,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
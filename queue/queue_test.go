@@ -0,0 +1,214 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+type Order struct {
+	ID    int     `json:"id"`
+	Total float64 `json:"total"`
+}
+
+// requestReply routes requests synchronously to a worker, like NATS.
+type requestReply struct {
+	worker   *Worker
+	subjects []string
+}
+
+func (r *requestReply) Request(ctx context.Context, subject string, payload []byte) ([]byte, error) {
+	r.subjects = append(r.subjects, subject)
+	data, _, err := r.worker.Handle(ctx, payload)
+	return data, err
+}
+
+// topics hands published requests to a worker goroutine and delivers its
+// replies back to the transport, like a Kafka or SQS consumer would.
+type topics struct {
+	worker    *Worker
+	transport *AsyncTransport
+}
+
+func (q *topics) Publish(ctx context.Context, topic string, payload []byte) error {
+	go func() {
+		data, replyTo, err := q.worker.Handle(ctx, payload)
+		if err == nil && replyTo == "replies" {
+			q.transport.Deliver(data)
+		}
+	}()
+	return nil
+}
+
+func newWorker(t *testing.T) *Worker {
+	t.Helper()
+	w := NewWorker()
+	require.NoError(t, w.Register("total", func(ctx context.Context, o Order, tax float64) (float64, error) {
+		return o.Total * (1 + tax), nil
+	}))
+	require.NoError(t, w.Register("fail", func(ctx context.Context) error {
+		return errors.New("out of stock") //nolint:err113 // test case.
+	}))
+	require.NoError(t, w.Register("panic", func(ctx context.Context) error {
+		panic("boom")
+	}))
+	return w
+}
+
+func newLyra(e lyra.Executor) *lyra.Lyra {
+	return lyra.New().
+		Do("order", func(ctx context.Context, id int) (Order, error) {
+			return Order{ID: id, Total: 100}, nil
+		}, lyra.UseRun("orderID")).
+		Do("total", func(ctx context.Context, o Order, tax float64) (float64, error) {
+			panic("executed by the worker")
+		}, lyra.Use("order"), lyra.UseRun("tax"), lyra.WithExecutor(e))
+}
+
+func TestExecutorRequestReply(t *testing.T) {
+	t.Parallel()
+
+	transport := &requestReply{worker: newWorker(t)}
+
+	result, err := newLyra(NewExecutor(transport)).Run(context.Background(), map[string]any{"orderID": 1, "tax": 0.5})
+
+	require.NoError(t, err)
+	total, err := result.Get("total")
+	require.NoError(t, err)
+	require.InDelta(t, 150.0, total, 1e-9)
+	require.Equal(t, []string{"lyra.tasks.total"}, transport.subjects)
+}
+
+func TestExecutorAsyncTransport(t *testing.T) {
+	t.Parallel()
+
+	q := &topics{worker: newWorker(t)}
+	q.transport = NewAsyncTransport(q, "replies")
+
+	result, err := newLyra(NewExecutor(q.transport)).Run(context.Background(), map[string]any{"orderID": 1, "tax": 0.25})
+
+	require.NoError(t, err)
+	total, err := result.Get("total")
+	require.NoError(t, err)
+	require.InDelta(t, 125.0, total, 1e-9)
+}
+
+func TestExecutorRemoteErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		taskID   string
+		contains string
+	}{
+		{name: "task error", taskID: "fail", contains: "out of stock"},
+		{name: "unknown task", taskID: "missing", contains: ErrUnknownTask.Error()},
+		{name: "panic", taskID: "panic", contains: `task "panic" panicked: boom`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			transport := &requestReply{worker: newWorker(t)}
+			e := NewExecutor(transport, WithSubject(func(taskID string) string {
+				return "workers." + strings.ToUpper(taskID)
+			}))
+
+			_, err := e.Execute(context.Background(), lyra.TaskCall{TaskID: tc.taskID})
+
+			require.ErrorIs(t, err, ErrRemoteTask)
+			require.Contains(t, err.Error(), tc.contains)
+			require.Equal(t, []string{"workers." + strings.ToUpper(tc.taskID)}, transport.subjects)
+		})
+	}
+}
+
+func TestAsyncTransportCanceled(t *testing.T) {
+	t.Parallel()
+
+	transport := NewAsyncTransport(publisherFunc(func(ctx context.Context, topic string, payload []byte) error {
+		return nil
+	}), "replies")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := transport.Request(ctx, "lyra.tasks.total", []byte(`{"task_id":"total"}`))
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, transport.Deliver([]byte(`{"id":"unknown"}`)))
+}
+
+// valueContext is a concrete type implementing context.Context.
+type valueContext struct {
+	context.Context
+}
+
+func TestWorkerRegisterInvalid(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		fn   any
+	}{
+		{name: "no context", fn: func(id int) int { return id }},
+		{name: "concrete context", fn: func(ctx valueContext) error { return nil }},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewWorker().Register("bad", tc.fn)
+
+			require.ErrorIs(t, err, errInvalidTaskFunc)
+		})
+	}
+}
+
+type publisherFunc func(ctx context.Context, topic string, payload []byte) error
+
+func (f publisherFunc) Publish(ctx context.Context, topic string, payload []byte) error {
+	return f(ctx, topic, payload)
+}
+
+type transportFunc func(ctx context.Context, subject string, payload []byte) ([]byte, error)
+
+func (f transportFunc) Request(ctx context.Context, subject string, payload []byte) ([]byte, error) {
+	return f(ctx, subject, payload)
+}
+
+func TestExecutorEmptyResult(t *testing.T) {
+	t.Parallel()
+
+	e := NewExecutor(transportFunc(func(context.Context, string, []byte) ([]byte, error) {
+		return []byte(`{}`), nil
+	}))
+
+	tcs := []struct {
+		name       string
+		outputType reflect.Type
+		want       any
+	}{
+		{name: "error only", outputType: nil, want: nil},
+		{name: "pointer", outputType: reflect.TypeOf(&Order{}), want: (*Order)(nil)},
+		{name: "number", outputType: reflect.TypeOf(0.0), want: 0.0},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			output, err := e.Execute(context.Background(), lyra.TaskCall{TaskID: "task", OutputType: tc.outputType})
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, output)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// errInvalidTaskFunc is returned by Register for functions that are not
+// lyra task functions.
+var errInvalidTaskFunc = errors.New("task function must be func(context.Context, ...) (T, error) or error")
+
+// Worker executes the task calls published by the executor. The
+// application consumes requests from the queue, passes them to Handle and
+// sends the reply: back through the request/reply mechanism of the queue,
+// or published to the returned reply topic with AsyncTransport.
+//
+// It is safe for concurrent use once all tasks are registered.
+type Worker struct {
+	mu    sync.RWMutex
+	tasks map[string]reflect.Value
+}
+
+// NewWorker returns a Worker without tasks.
+func NewWorker() *Worker {
+	return &Worker{tasks: make(map[string]reflect.Value)}
+}
+
+// Register serves the task with fn, which has the same signature as the
+// task function registered with lyra.Do.
+func (w *Worker) Register(taskID string, fn any) error {
+	v := reflect.ValueOf(fn)
+	if !isTaskFunc(v) {
+		return fmt.Errorf("register task %q: %w", taskID, errInvalidTaskFunc)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tasks[taskID] = v
+	return nil
+}
+
+// Handle executes the task call in payload and returns the reply and, for
+// requests sent through AsyncTransport, the topic to publish it to. Task
+// failures are reported in the reply; the returned error is only set when
+// the reply itself cannot be encoded.
+func (w *Worker) Handle(ctx context.Context, payload []byte) (data []byte, replyTo string, err error) {
+	var req request
+	rep := reply{}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		rep.Error = fmt.Sprintf("decode request: %v", err)
+	} else {
+		rep.ID = req.ID
+		rep.Result, err = w.call(ctx, req)
+		if err != nil {
+			rep.Error = err.Error()
+		}
+	}
+
+	data, err = json.Marshal(rep)
+	if err != nil {
+		return nil, req.ReplyTo, fmt.Errorf("encode reply: %w", err)
+	}
+	return data, req.ReplyTo, nil
+}
+
+// call executes the task call, turning a panic of the task function into
+// an error so it fails the call instead of the worker.
+func (w *Worker) call(ctx context.Context, req request) (result json.RawMessage, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("task %q panicked: %v", req.TaskID, r)
+		}
+	}()
+
+	w.mu.RLock()
+	fn, ok := w.tasks[req.TaskID]
+	w.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTask, req.TaskID)
+	}
+
+	fnType := fn.Type()
+	if len(req.Args) != fnType.NumIn()-1 {
+		return nil, fmt.Errorf("task %q takes %d arguments, got %d", req.TaskID, fnType.NumIn()-1, len(req.Args))
+	}
+	args := make([]reflect.Value, fnType.NumIn())
+	args[0] = reflect.ValueOf(ctx)
+	for i, raw := range req.Args {
+		arg := reflect.New(fnType.In(i + 1))
+		if err := json.Unmarshal(raw, arg.Interface()); err != nil {
+			return nil, fmt.Errorf("decode argument %d of task %q: %w", i, req.TaskID, err)
+		}
+		args[i+1] = arg.Elem()
+	}
+
+	out := fn.Call(args)
+	if errValue := out[len(out)-1]; !errValue.IsNil() {
+		return nil, errValue.Interface().(error) //nolint:forcetypeassert // checked by isTaskFunc
+	}
+	if len(out) == 1 {
+		return nil, nil
+	}
+	result, err = json.Marshal(out[0].Interface())
+	if err != nil {
+		return nil, fmt.Errorf("encode result of task %q: %w", req.TaskID, err)
+	}
+	return result, nil
+}
+
+func isTaskFunc(v reflect.Value) bool {
+	if v.Kind() != reflect.Func {
+		return false
+	}
+	t := v.Type()
+	// The context is passed as a context.Context, so the parameter must
+	// have exactly that type.
+	if t.IsVariadic() || t.NumIn() < 1 || t.In(0) != contextType {
+		return false
+	}
+	switch t.NumOut() {
+	case 1, 2:
+		return t.Out(t.NumOut() - 1).Implements(errorType)
+	default:
+		return false
+	}
+}
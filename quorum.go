@@ -0,0 +1,208 @@
+package lyra
+
+import (
+	stderr "errors"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithQuorum runs the task once at least k of the tasks whose results it
+// reads have succeeded, for redundant data sources. Parameters bound to
+// tasks that failed or were canceled receive their zero value, or their
+// default (see Default), as if read with Optional. With fewer than k
+// successful sources the task fails with ErrQuorumNotMet.
+//
+// Once k sources succeeded, the sources still running or pending are
+// canceled, so a slow or hung source does not hold the task back. A source
+// is only canceled when every task reading it has a quorum that is met.
+// Tasks still start stage by stage, so the task starts once the canceled
+// sources returned: sources must honor their context for the task to start
+// early.
+//
+// A source task read only by tasks with a quorum does not fail the run
+// when it fails; its failure is reported by Result.Failures.
+//
+// Example:
+//
+//	l.Do("price", medianPrice, lyra.Use("priceA"), lyra.Use("priceB"), lyra.Use("priceC"),
+//		lyra.WithQuorum(2))
+func WithQuorum(k int) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Quorum = k
+		o.QuorumSet = true
+	})
+}
+
+// withQuorumInputs marks the task results read by tasks with a quorum as
+// optional, in place.
+func withQuorumInputs(tasks map[string]*internal.Task) {
+	for taskID, task := range tasks {
+		if task.GetOptions().Quorum <= 0 {
+			continue
+		}
+		specs, _ := task.GetInputParams()
+		specs = slices.Clone(specs)
+		for i, spec := range specs {
+			if spec.Type == internal.TaskResultInputSpec {
+				specs[i] = Optional(spec)
+			}
+		}
+		tasks[taskID] = task.WithInputSpecs(specs)
+	}
+}
+
+// checkQuorums returns ErrInvalidQuorum if a task has a quorum it can
+// never meet, or below 1.
+func checkQuorums(tasks map[string]*internal.Task) error {
+	var errs []error
+	for taskID, task := range tasks {
+		opts := task.GetOptions()
+		if !opts.QuorumSet {
+			continue
+		}
+		if sources := len(quorumSources(task)); opts.Quorum < 1 || opts.Quorum > sources {
+			errs = append(errs, errors.Wrapf(
+				errors.ErrInvalidQuorum,
+				"task %q: quorum %d of %d sources",
+				taskID,
+				opts.Quorum,
+				sources,
+			))
+		}
+	}
+	return stderr.Join(errs...)
+}
+
+// quorumSources returns the distinct tasks whose results the task reads
+// toward its quorum.
+func quorumSources(task *internal.Task) []string {
+	specs, _ := task.GetInputParams()
+	var sources []string
+	for _, spec := range specs {
+		if spec.Type == internal.TaskResultInputSpec && !slices.Contains(sources, spec.Source) {
+			sources = append(sources, spec.Source)
+		}
+	}
+	return sources
+}
+
+// quorumReaders returns the sources of the tasks with a quorum mapped to
+// those tasks.
+func quorumReaders(tasks map[string]*compiledTask) map[string][]string {
+	readers := make(map[string][]string)
+	for taskID, task := range tasks {
+		if task.GetOptions().Quorum <= 0 {
+			continue
+		}
+		for _, source := range quorumSources(task.Task) {
+			readers[source] = append(readers[source], taskID)
+		}
+	}
+	return readers
+}
+
+// checkQuorum returns ErrQuorumNotMet if fewer of the sources of the task
+// succeeded than its quorum requires.
+func (s *runState) checkQuorum(task *compiledTask) error {
+	quorum := task.GetOptions().Quorum
+	if quorum <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if succeeded := s.succeededSources(task); succeeded < quorum {
+		return errors.Wrapf(
+			errors.ErrQuorumNotMet,
+			"%d of %d sources succeeded, need %d",
+			succeeded,
+			len(quorumSources(task.Task)),
+			quorum,
+		)
+	}
+	return nil
+}
+
+// succeededSources returns the number of sources of the task that
+// succeeded. Callers must hold s.mu.
+func (s *runState) succeededSources(task *compiledTask) int {
+	succeeded := 0
+	for _, source := range quorumSources(task.Task) {
+		if s.statuses[source] == TaskSucceeded {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// meetQuorums cancels the unfinished sources of the tasks with a quorum
+// reading taskID, which just succeeded, once their quorum is met. A source
+// is canceled only if every task reading it has a quorum that is met.
+func (s *runState) meetQuorums(taskID string) {
+	readers := s.quorumReaders[taskID]
+	if len(readers) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stragglers []string
+	for _, id := range readers {
+		if !s.quorumMet(id) {
+			continue
+		}
+		for _, source := range quorumSources(s.tasks[id].Task) {
+			if !s.statuses[source].isFinal() && s.readByMetQuorums(source) && !slices.Contains(stragglers, source) {
+				stragglers = append(stragglers, source)
+			}
+		}
+	}
+	s.cancelTasks(stragglers)
+}
+
+// quorumMet reports whether enough sources of the task with a quorum
+// succeeded. Callers must hold s.mu.
+func (s *runState) quorumMet(taskID string) bool {
+	task := s.tasks[taskID]
+	return s.succeededSources(task) >= task.GetOptions().Quorum
+}
+
+// readByMetQuorums reports whether the task is read only by tasks with a
+// quorum that is met. Callers must hold s.mu.
+func (s *runState) readByMetQuorums(taskID string) bool {
+	for id, deps := range s.deps {
+		if !slices.Contains(deps, taskID) {
+			continue
+		}
+		if !slices.Contains(s.quorumReaders[taskID], id) || slices.Contains(s.strictDeps[id], taskID) ||
+			!s.quorumMet(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowSourceFailure reports whether the task is read only by tasks with a
+// quorum, in which case its failure with err does not fail the run.
+func (s *runState) allowSourceFailure(taskID string, err error) bool {
+	readers := 0
+	for id, deps := range s.deps {
+		if !slices.Contains(deps, taskID) {
+			continue
+		}
+		if s.tasks[id].GetOptions().Quorum <= 0 || slices.Contains(s.strictDeps[id], taskID) {
+			return false
+		}
+		readers++
+	}
+	if readers == 0 {
+		return false
+	}
+
+	s.result.mu.Lock()
+	defer s.result.mu.Unlock()
+	s.result.setFailure(taskID, err)
+	return true
+}
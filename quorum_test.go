@@ -0,0 +1,148 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func newQuorumLyra(quorum int, failing ...string) *Lyra {
+	l := New()
+	for i, source := range []string{"priceA", "priceB", "priceC"} {
+		fails := false
+		for _, id := range failing {
+			fails = fails || id == source
+		}
+		price := i + 1
+		l.Do(source, func(ctx context.Context) (int, error) {
+			if fails {
+				return 0, errTaskFailed
+			}
+			// Let failing sources finish first, before the quorum is met
+			// and the sources left are canceled.
+			time.Sleep(10 * time.Millisecond)
+			return price, nil
+		})
+	}
+	return l.Do("sum", func(ctx context.Context, a, b, c int) (int, error) {
+		return a + b + c, nil
+	}, Use("priceA"), Use("priceB"), Use("priceC"), WithQuorum(quorum))
+}
+
+func TestWithQuorum(t *testing.T) {
+	t.Parallel()
+
+	result, err := newQuorumLyra(2, "priceB").Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("sum")
+	require.NoError(t, err)
+	require.Equal(t, 4, value)
+	require.ErrorIs(t, result.Failures()["priceB"], errTaskFailed)
+
+	result, err = newQuorumLyra(3).Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err = result.Get("sum")
+	require.NoError(t, err)
+	require.Equal(t, 6, value)
+	require.Empty(t, result.Failures())
+}
+
+func TestWithQuorumNotMet(t *testing.T) {
+	t.Parallel()
+
+	_, err := newQuorumLyra(2, "priceA", "priceC").Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrQuorumNotMet)
+	require.ErrorContains(t, err, "1 of 3 sources succeeded, need 2")
+}
+
+func TestWithQuorumSourceReadStrictly(t *testing.T) {
+	t.Parallel()
+
+	l := newQuorumLyra(2, "priceB").Do("audit", func(ctx context.Context, v int) error {
+		return nil
+	}, Use("priceB"))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+}
+
+func TestWithQuorumCancelsStragglers(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("priceA", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("priceB", func(ctx context.Context) (int, error) { return 2, nil }).
+		Do("priceC", func(ctx context.Context) (int, error) {
+			<-ctx.Done() // hangs until canceled
+			return 0, ctx.Err()
+		}).
+		Do("sum", func(ctx context.Context, a, b, c int) (int, error) {
+			return a + b + c, nil
+		}, Use("priceA"), Use("priceB"), Use("priceC"), WithQuorum(2))
+
+	run := l.RunAsync(context.Background(), nil)
+	result, err := run.Wait()
+	require.NoError(t, err)
+	value, err := result.Get("sum")
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+	require.Empty(t, result.Failures())
+
+	status, _ := run.TaskStatus("priceC")
+	require.Equal(t, TaskCanceled, status)
+	require.Len(t, eventsOfType(collectEvents(run), EventTaskCanceled), 1)
+}
+
+func TestWithQuorumKeepsSourcesReadStrictly(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("priceA", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("priceB", func(ctx context.Context) (int, error) { return 2, nil }).
+		Do("priceC", func(ctx context.Context) (int, error) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return 3, nil
+			}
+		}).
+		Do("sum", func(ctx context.Context, a, b, c int) (int, error) {
+			return a + b + c, nil
+		}, Use("priceA"), Use("priceB"), Use("priceC"), WithQuorum(2)).
+		Do("audit", func(ctx context.Context, c int) (int, error) { return c, nil }, Use("priceC"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("sum")
+	require.NoError(t, err)
+	require.Equal(t, 6, value)
+}
+
+func TestWithQuorumInvalid(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name   string
+		quorum int
+		msg    string
+	}{
+		{name: "zero", quorum: 0, msg: `task "sum": quorum 0 of 3 sources`},
+		{name: "negative", quorum: -1, msg: `task "sum": quorum -1 of 3 sources`},
+		{name: "above sources", quorum: 4, msg: `task "sum": quorum 4 of 3 sources`},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newQuorumLyra(tc.quorum).Run(context.Background(), nil)
+			require.ErrorIs(t, err, errors.ErrInvalidQuorum)
+			require.ErrorContains(t, err, tc.msg)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Alternative is a function computing the result of a task registered with
+// DoRace. Every alternative of a task must have the same signature.
+type Alternative any
+
+// DoRace adds a task whose result is computed by the first of alternatives
+// to succeed. The alternatives are called concurrently with the same
+// inputs; once one succeeds, the contexts of the others are canceled and
+// the task returns without waiting for them. If every alternative fails,
+// the task fails with their errors joined. A panicking alternative fails
+// like one returning an *errors.PanicError.
+//
+// It accepts the same input specs and task options as Do.
+//
+// Example:
+//
+//	l.DoRace("getPrice", []lyra.Alternative{primaryPrice, cachedPrice}, lyra.Use("product"))
+func (l *Lyra) DoRace(taskID string, alternatives []Alternative, inputs ...internal.InputSpec) *Lyra {
+	fn, err := race(alternatives)
+	if err != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.error = errors.Wrapf(err, "failed to add task %q", taskID)
+		return l
+	}
+	return l.Do(taskID, fn, inputs...)
+}
+
+// raceOutcome is what an alternative returned.
+type raceOutcome struct {
+	values []reflect.Value
+	err    error
+}
+
+// race returns a function with the signature of the alternatives calling
+// them concurrently. A first alternative that is not a function is
+// returned as is, for Do to report.
+func race(alternatives []Alternative) (any, error) {
+	if len(alternatives) == 0 {
+		return nil, fmt.Errorf("%w: no alternatives", errors.ErrMustBeFunction)
+	}
+	fnType := reflect.TypeOf(alternatives[0])
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return alternatives[0], nil
+	}
+	fns := make([]reflect.Value, len(alternatives))
+	for i, alternative := range alternatives {
+		if typ := reflect.TypeOf(alternative); typ != fnType {
+			return nil, errors.Wrapf(errors.ErrInvalidParamType, "alternative %d is %v, want %s", i+1, typ, fnType)
+		}
+		fns[i] = reflect.ValueOf(alternative)
+	}
+	if len(fns) == 1 {
+		return alternatives[0], nil
+	}
+
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		//nolint:forcetypeassert // the first parameter of a task is its context.
+		ctx, cancel := context.WithCancel(args[0].Interface().(context.Context))
+		defer cancel()
+
+		outcomes := make(chan raceOutcome, len(fns))
+		for _, fn := range fns {
+			altArgs := append([]reflect.Value{reflect.ValueOf(ctx)}, args[1:]...)
+			go func() { outcomes <- callAlternative(fn, altArgs) }()
+		}
+
+		errs := make([]error, 0, len(fns))
+		for range fns {
+			outcome := <-outcomes
+			if outcome.err == nil {
+				return outcome.values
+			}
+			errs = append(errs, outcome.err)
+		}
+		return errorValues(fnType, stderr.Join(errs...))
+	}).Interface(), nil
+}
+
+// callAlternative calls fn with args and returns its results and error,
+// recovering a panic as an *errors.PanicError.
+func callAlternative(fn reflect.Value, args []reflect.Value) (outcome raceOutcome) {
+	defer func() {
+		if value := recover(); value != nil {
+			outcome.err = &errors.PanicError{Value: value, Stack: debug.Stack()}
+		}
+	}()
+	outcome.values = fn.Call(args)
+	if last := outcome.values[len(outcome.values)-1]; !last.IsNil() {
+		// revive:disable-next-line:unchecked-type-assertion // It's always error
+		outcome.err, _ = last.Interface().(error)
+	}
+	return outcome
+}
+
+// errorValues returns the results of a function of type fnType returning
+// err: the zero value followed by err.
+func errorValues(fnType reflect.Type, err error) []reflect.Value {
+	values := make([]reflect.Value, fnType.NumOut())
+	for i := range values {
+		values[i] = reflect.Zero(fnType.Out(i))
+	}
+	values[len(values)-1] = reflect.ValueOf(&err).Elem()
+	return values
+}
@@ -0,0 +1,88 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestDoRace(t *testing.T) {
+	t.Parallel()
+
+	canceled := make(chan struct{})
+	slow := func(ctx context.Context, id int) (string, error) {
+		<-ctx.Done()
+		close(canceled)
+		return "", ctx.Err()
+	}
+	failing := func(ctx context.Context, id int) (string, error) {
+		return "", errTaskFailed
+	}
+	fast := func(ctx context.Context, id int) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "fast", nil
+	}
+
+	l := New().
+		Do("product", func(ctx context.Context) (int, error) { return 1, nil }).
+		DoRace("getPrice", []Alternative{slow, failing, fast}, Use("product"))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("getPrice")
+	require.NoError(t, err)
+	require.Equal(t, "fast", value)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("losing alternative was not canceled")
+	}
+}
+
+func TestDoRaceAllFail(t *testing.T) {
+	t.Parallel()
+
+	l := New().DoRace("notify", []Alternative{
+		func(ctx context.Context) error { return errTaskFailed },
+		func(ctx context.Context) error { panic("boom") },
+	})
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+	require.ErrorIs(t, err, errors.ErrTaskPanicked)
+}
+
+func TestDoRaceErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name         string
+		alternatives []Alternative
+		err          error
+	}{
+		{name: "no alternatives", err: errors.ErrMustBeFunction},
+		{name: "not a function", alternatives: []Alternative{1, 2}, err: errors.ErrMustBeFunction},
+		{
+			name: "mismatched signatures",
+			alternatives: []Alternative{
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) (int, error) { return 0, nil },
+			},
+			err: errors.ErrInvalidParamType,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New().DoRace("task", tc.alternatives).Run(context.Background(), nil)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
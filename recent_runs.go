@@ -0,0 +1,114 @@
+package lyra
+
+import (
+	"slices"
+	"time"
+)
+
+// recentRunsLimit is the number of runs kept by Lyra.RecentRuns.
+const recentRunsLimit = 20
+
+// RunSummary describes a finished run.
+type RunSummary struct {
+	ID       string
+	Start    time.Time
+	Duration time.Duration
+	// InputsHash is a digest of the runtime inputs of the run; runs with
+	// the same non-sensitive inputs share it.
+	InputsHash string
+	// Err is the error returned by the run, if any.
+	Err error
+	// Tasks holds every task of the run in stage order.
+	Tasks []TaskSummary
+	// Stages holds every stage of the run in order.
+	Stages []StageSummary
+	// Profile holds the timings of the tasks of runs started with
+	// WithProfiling; nil otherwise. It does not include CPU time (see
+	// RunProfile).
+	Profile *RunProfile
+}
+
+// TaskSummary describes a task of a finished run.
+type TaskSummary struct {
+	ID     string
+	Stage  int
+	Status TaskStatus
+	// Duration is how long the task ran; zero for tasks that never started.
+	Duration time.Duration
+	// Tags and Meta are those of the task (see WithTag and WithMeta); they
+	// must not be modified.
+	Tags []string
+	Meta map[string]string
+	// Attempts lists the calls of the task and of its fallbacks, in order;
+	// nil for tasks without fallbacks (see WithFallbacks).
+	Attempts []TaskAttempt
+}
+
+// RecentRuns returns summaries of the last runs of l, most recent first.
+// Only a bounded number of runs is kept.
+func (l *Lyra) RecentRuns() []RunSummary {
+	l.recentMu.Lock()
+	defer l.recentMu.Unlock()
+
+	runs := slices.Clone(l.recentRuns)
+	slices.Reverse(runs)
+	return runs
+}
+
+// recordRun keeps the summary of a finished run, dropping the oldest one
+// once recentRunsLimit runs are kept, and appends it to the history store.
+func (l *Lyra) recordRun(summary RunSummary, logger Logger) {
+	l.recentMu.Lock()
+	if len(l.recentRuns) == recentRunsLimit {
+		l.recentRuns = slices.Delete(l.recentRuns, 0, 1)
+	}
+	l.recentRuns = append(l.recentRuns, summary)
+	store := l.historyStore
+	l.recentMu.Unlock()
+
+	if store == nil {
+		return
+	}
+	if err := store.Append(summary); err != nil && logger != nil {
+		logger.Error("lyra: history store failed", "run_id", summary.ID, "error", err)
+	}
+}
+
+// summary describes the run as of end.
+func (s *runState) summary(end time.Time, err error) RunSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make([]TaskSummary, 0, len(s.deps))
+	for i, stage := range s.stages {
+		for _, taskID := range stage {
+			task := TaskSummary{ID: taskID, Stage: i, Status: s.statuses[taskID], Attempts: s.attempts[taskID]}
+			if compiled, ok := s.tasks[taskID]; ok {
+				opts := compiled.GetOptions()
+				task.Tags, task.Meta = opts.Tags, opts.Meta
+			}
+			if started, ok := s.started[taskID]; ok {
+				finished, ok := s.finished[taskID]
+				if !ok {
+					finished = end
+				}
+				task.Duration = finished.Sub(started)
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	var profile *RunProfile
+	if s.cfg.profiling {
+		profile = s.profile(end)
+	}
+	return RunSummary{
+		ID:         s.cfg.runID,
+		Start:      s.start,
+		Duration:   end.Sub(s.start),
+		InputsHash: s.inputsHash,
+		Err:        err,
+		Tasks:      tasks,
+		Stages:     slices.Clone(s.stageSummaries),
+		Profile:    profile,
+	}
+}
@@ -0,0 +1,68 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentRuns(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("first", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("second", func(ctx context.Context, n int) (int, error) {
+			if n < 0 {
+				return 0, errTaskFailed
+			}
+			return n, nil
+		}, Use("first"))
+
+	require.Empty(t, l.RecentRuns())
+
+	for i := range recentRunsLimit + 2 {
+		_, err := l.Run(context.Background(), nil, WithRunID(fmt.Sprintf("run-%d", i)))
+		require.NoError(t, err)
+	}
+
+	runs := l.RecentRuns()
+	require.Len(t, runs, recentRunsLimit)
+	require.Equal(t, fmt.Sprintf("run-%d", recentRunsLimit+1), runs[0].ID, "most recent first")
+	require.Equal(t, "run-2", runs[len(runs)-1].ID, "oldest runs are dropped")
+
+	last := runs[0]
+	require.NoError(t, last.Err)
+	require.Len(t, last.Tasks, 2)
+	require.Equal(t, "first", last.Tasks[0].ID)
+	require.Equal(t, 0, last.Tasks[0].Stage)
+	require.Equal(t, TaskSucceeded, last.Tasks[0].Status)
+	require.Equal(t, "second", last.Tasks[1].ID)
+	require.Equal(t, 1, last.Tasks[1].Stage)
+	require.GreaterOrEqual(t, last.Duration, last.Tasks[1].Duration)
+}
+
+func TestRecentRunsRecordsFailures(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fail", func(ctx context.Context) (int, error) {
+			return 0, errTaskFailed
+		}).
+		Do("never", func(ctx context.Context, n int) error {
+			return nil
+		}, Use("fail"))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+
+	runs := l.RecentRuns()
+	require.Len(t, runs, 1)
+	require.ErrorIs(t, runs[0].Err, errTaskFailed)
+	require.Equal(t, TaskFailed, runs[0].Tasks[0].Status)
+	require.Equal(t, TaskSkipped, runs[0].Tasks[1].Status)
+	require.Zero(t, runs[0].Tasks[1].Duration)
+}
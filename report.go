@@ -0,0 +1,72 @@
+package lyra
+
+import "context"
+
+// reporter publishes the values reported by one task execution.
+type reporter struct {
+	state    *runState
+	stageIdx int
+	taskID   string
+}
+
+// Report publishes an intermediate progress value of the task that owns
+// ctx, such as a completion ratio or an arbitrary payload, so observers can
+// follow long-running tasks before their results are available. The value
+// is delivered as an EventTaskProgress event and the latest one is returned
+// by Run.Reported.
+//
+// Returns false, and does nothing, if ctx was not passed to a task by Lyra
+// or the task is no longer running.
+//
+// Example:
+//
+//	for i, row := range rows {
+//		...
+//		lyra.Report(ctx, float64(i+1)/float64(len(rows)))
+//	}
+func Report(ctx context.Context, value any) bool {
+	r, ok := ctx.Value(reporterKey).(reporter)
+	if !ok {
+		return false
+	}
+	return r.state.report(r.stageIdx, r.taskID, value)
+}
+
+func contextWithReporter(ctx context.Context, state *runState, stageIdx int, taskID string) context.Context {
+	return context.WithValue(ctx, reporterKey, reporter{state: state, stageIdx: stageIdx, taskID: taskID})
+}
+
+// report records value as the latest reported by the task and emits it,
+// unless the task is no longer running.
+func (s *runState) report(stageIdx int, taskID string, value any) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.statuses[taskID] != TaskRunning {
+		return false
+	}
+	if s.reports == nil {
+		s.reports = make(map[string]any)
+	}
+	s.reports[taskID] = value
+	s.emit(Event{Type: EventTaskProgress, Stage: stageIdx, TaskID: taskID, Value: value})
+	return true
+}
+
+// reported returns the latest value reported by the task.
+func (s *runState) reported(taskID string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.reports[taskID]
+	return value, ok
+}
+
+// Reported returns the latest value the task reported with Report in this
+// run, which is kept after the task finished, or false if it reported none.
+func (r *Run) Reported(taskID string) (any, bool) {
+	if r.state == nil {
+		return nil, false
+	}
+	return r.state.reported(taskID)
+}
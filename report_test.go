@@ -0,0 +1,59 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("import", func(ctx context.Context) (int, error) {
+		for _, done := range []float64{0.5, 1} {
+			assert.True(t, Report(ctx, done))
+		}
+		return 2, nil
+	})
+
+	run := l.RunAsync(context.Background(), nil)
+	var values []any
+	for e := range run.Events() {
+		if e.Type == EventTaskProgress {
+			require.Equal(t, "import", e.TaskID)
+			values = append(values, e.Value)
+		}
+	}
+	_, err := run.Wait()
+	require.NoError(t, err)
+
+	require.Equal(t, []any{0.5, 1.0}, values)
+	latest, ok := run.Reported("import")
+	require.True(t, ok)
+	require.Equal(t, 1.0, latest)
+	_, ok = run.Reported("unknown")
+	require.False(t, ok)
+}
+
+func TestReportOutsideTask(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Report(context.Background(), 0.5))
+}
+
+func TestReportAfterTaskFinished(t *testing.T) {
+	t.Parallel()
+
+	var taskCtx context.Context
+	l := New().Do("task", func(ctx context.Context) error {
+		taskCtx = ctx
+		return nil
+	})
+
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	require.False(t, Report(taskCtx, "late"))
+}
@@ -0,0 +1,181 @@
+package lyra
+
+import (
+	stderr "errors"
+	"reflect"
+	"slices"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Require declares a runtime input that every Run must provide, with a value
+// assignable to typ. A nil typ only requires the key to be present.
+//
+// Run also infers requirements from UseRun specs: every referenced key must
+// be present and, when the whole value is bound to a parameter, assignable
+// to the parameter type. Require adds keys no task references yet and
+// checks the type of values whose fields are accessed with a field path.
+//
+// All requirements are checked before any task executes, so a bad input
+// never fails the run halfway after side effects have happened.
+//
+// Returns the same Lyra instance for method chaining.
+//
+// Example:
+//
+//	l.Require("userID", reflect.TypeOf(0))
+//	l.Require("config", reflect.TypeOf(Config{}))
+func (l *Lyra) Require(key string, typ reflect.Type) *Lyra {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to require %q", key)
+		return l
+	}
+	if l.required == nil {
+		l.required = make(map[string]reflect.Type)
+	}
+	l.required[key] = typ
+	return l
+}
+
+// inputRequirement is a type a runtime input must be assignable to, and
+// who asked for it.
+type inputRequirement struct {
+	typ    reflect.Type
+	source string
+	// optional is set for keys read with Optional, which may be missing.
+	optional bool
+}
+
+// checkRunInputs validates runInputs against the declared and inferred
+// requirements and reports every problem at once.
+func checkRunInputs(
+	runInputs map[string]any,
+	requirements map[string][]inputRequirement,
+	secrets map[string]struct{},
+) error {
+	keys := make([]string, 0, len(requirements))
+	for key := range requirements {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var errs []error
+	for _, key := range keys {
+		value, ok := runInputs[key]
+		if !ok {
+			if !slices.ContainsFunc(requirements[key], isMandatory) {
+				continue
+			}
+			errs = append(errs, errors.Wrapf(errors.ErrMissingRunInput, "key %q", key))
+			continue
+		}
+		if value == nil {
+			errs = append(errs, checkNilRunInput(key, requirements[key])...)
+			continue
+		}
+		actual := reflect.TypeOf(value)
+		_, secret := secrets[key]
+		for _, req := range requirements[key] {
+			if req.typ != nil && !actual.AssignableTo(req.typ) {
+				errs = append(errs, errors.Wrapf(
+					errors.ErrInvalidParamType,
+					"run input %q required by %s -> expected type %s, got %s",
+					key,
+					req.source,
+					req.typ,
+					typeName(actual, secret),
+				))
+			}
+		}
+	}
+
+	//nolint:wrapcheck // stderr points to standard errors.
+	return stderr.Join(errs...)
+}
+
+func isMandatory(req inputRequirement) bool {
+	return !req.optional
+}
+
+// checkNilRunInput checks an untyped nil run input against requirements,
+// which only types that can be nil accept.
+func checkNilRunInput(key string, requirements []inputRequirement) []error {
+	var errs []error
+	for _, req := range requirements {
+		if req.typ == nil {
+			continue
+		}
+		if _, ok := nilValue(req.typ); !ok {
+			errs = append(errs, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"run input %q required by %s -> expected type %s, got untyped nil",
+				key,
+				req.source,
+				req.typ,
+			))
+		}
+	}
+	return errs
+}
+
+// inputRequirements collects the requirements of every runtime input key
+// from Require calls and UseRun specs.
+func (l *Lyra) inputRequirements() map[string][]inputRequirement {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	requirements := make(map[string][]inputRequirement, len(l.required))
+	for key, typ := range l.required {
+		requirements[key] = append(requirements[key], inputRequirement{typ: typ, source: "Require"})
+	}
+
+	for taskID, task := range l.tasks {
+		specs, types := task.GetInputParams()
+		for i, spec := range specs {
+			if spec.Type == internal.TemplateInputSpec {
+				for _, ref := range spec.Refs {
+					if _, ok := l.tasks[ref]; !ok {
+						requirements[ref] = append(requirements[ref], inputRequirement{source: "task " + taskID})
+					}
+				}
+				continue
+			}
+			if spec.Type == internal.TaggedInputSpec {
+				structType := types[i+1] // +1 to skip context
+				if structType.Kind() == reflect.Ptr {
+					structType = structType.Elem()
+				}
+				for _, binding := range spec.Bindings {
+					if binding.Spec.Type != internal.RuntimeInputSpec {
+						continue
+					}
+					field, _ := structType.FieldByName(binding.Name)
+					req := inputRequirement{source: "task " + taskID}
+					if !hasFieldPath(binding.Spec.Field) {
+						req.typ = field.Type
+					}
+					requirements[binding.Spec.Source] = append(requirements[binding.Spec.Source], req)
+				}
+				continue
+			}
+			if spec.Type != internal.RuntimeInputSpec {
+				continue
+			}
+			req := inputRequirement{source: "task " + taskID, optional: spec.Optional}
+			if !hasFieldPath(spec.Field) {
+				req.typ = types[i+1] // +1 to skip context
+			}
+			requirements[spec.Source] = append(requirements[spec.Source], req)
+		}
+	}
+	return requirements
+}
+
+// hasFieldPath reports whether fields selects anything below the value.
+func hasFieldPath(fields []string) bool {
+	return slices.ContainsFunc(fields, func(f string) bool { return f != "" })
+}
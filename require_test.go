@@ -0,0 +1,128 @@
+package lyra
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestCheckRunInputs(t *testing.T) {
+	t.Parallel()
+
+	build := func() *Lyra {
+		return New().
+			Do("greet", func(ctx context.Context, name string) (string, error) {
+				return "hi " + name, nil
+			}, UseRun("name")).
+			Do("city", func(ctx context.Context, city string) (string, error) {
+				return city, nil
+			}, UseRun("user", "Address", "City"))
+	}
+	user := User{Address: Address{City: "Boston"}}
+
+	tcs := []struct {
+		name        string
+		lyra        *Lyra
+		runInputs   map[string]any
+		expectedErr error
+		contains    []string
+	}{
+		{
+			name:      "all inputs present",
+			lyra:      build(),
+			runInputs: map[string]any{"name": "Alice", "user": user},
+		},
+		{
+			name:        "missing inputs reported together",
+			lyra:        build(),
+			runInputs:   map[string]any{},
+			expectedErr: errors.ErrMissingRunInput,
+			contains:    []string{`key "name"`, `key "user"`},
+		},
+		{
+			name:        "inferred type mismatch",
+			lyra:        build(),
+			runInputs:   map[string]any{"name": 42, "user": user},
+			expectedErr: errors.ErrInvalidParamType,
+			contains:    []string{`run input "name" required by task greet -> expected type string, got int`},
+		},
+		{
+			name:      "field path values are not type checked by inference",
+			lyra:      build(),
+			runInputs: map[string]any{"name": "Alice", "user": &user},
+		},
+		{
+			name:        "declared type mismatch",
+			lyra:        build().Require("user", reflect.TypeOf(User{})),
+			runInputs:   map[string]any{"name": "Alice", "user": &user},
+			expectedErr: errors.ErrInvalidParamType,
+			contains:    []string{`run input "user" required by Require -> expected type lyra.User, got *lyra.User`},
+		},
+		{
+			name:        "declared key not referenced by tasks",
+			lyra:        build().Require("apiKey", nil),
+			runInputs:   map[string]any{"name": "Alice", "user": user},
+			expectedErr: errors.ErrMissingRunInput,
+			contains:    []string{`key "apiKey"`},
+		},
+		{
+			name:      "interface requirement",
+			lyra:      New().Require("ctx", reflect.TypeOf((*context.Context)(nil)).Elem()),
+			runInputs: map[string]any{"ctx": context.Background()},
+		},
+		{
+			name:      "nil values are not type checked",
+			lyra:      build(),
+			runInputs: map[string]any{"name": "Alice", "user": nil},
+		},
+		{
+			name:        "untyped nil for a non-nilable type",
+			lyra:        build(),
+			runInputs:   map[string]any{"name": nil, "user": user},
+			expectedErr: errors.ErrInvalidParamType,
+			contains:    []string{`run input "name" required by task greet -> expected type string, got untyped nil`},
+		},
+		{
+			name:      "untyped nil for a nilable type",
+			lyra:      build().Require("tags", reflect.TypeOf([]string{})),
+			runInputs: map[string]any{"name": "Alice", "user": user, "tags": nil},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkRunInputs(tc.runInputs, tc.lyra.inputRequirements(), tc.lyra.secretKeys())
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+			for _, s := range tc.contains {
+				require.Contains(t, err.Error(), s)
+			}
+		})
+	}
+}
+
+func TestRunValidatesInputsBeforeExecution(t *testing.T) {
+	t.Parallel()
+
+	sideEffects := 0
+	l := New().
+		Do("charge", func(ctx context.Context) error {
+			sideEffects++
+			return nil
+		}).
+		Do("notify", func(ctx context.Context, email string) error {
+			return nil
+		}, UseRun("email"))
+
+	result, err := l.Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errors.ErrMissingRunInput)
+	require.Nil(t, result)
+	require.Zero(t, sideEffects)
+}
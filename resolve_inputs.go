@@ -5,57 +5,345 @@ import (
 	stderr "errors"
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/sourabh-kumar2/lyra/errors"
 	"github.com/sourabh-kumar2/lyra/internal"
 )
 
+// resolveInputs resolves the arguments of a task that was not compiled
+// against a DAG snapshot, checking every value as it is read.
 func resolveInputs(
 	ctx context.Context,
 	task *internal.Task,
 	results *Result,
 ) ([]reflect.Value, error) {
-	specs, types := task.GetInputParams()
-	args := make([]reflect.Value, len(types))
-	args[0] = reflect.ValueOf(ctx) // First arg is always context
+	return compileTask(task, nil, nil).resolve(ctx, results)
+}
+
+// argResolver produces the argument of one task parameter from the results
+// of a run.
+type argResolver func(results *Result) (reflect.Value, error)
 
+// compiledTask is a task with the resolvers of its arguments, compiled once
+// per DAG snapshot so that runs do not repeat the reflection work.
+type compiledTask struct {
+	*internal.Task
+	args []argResolver
+}
+
+// compileTask compiles the argument resolvers of task. outputTypes maps the
+// task IDs of the DAG to their output types. A spec is compiled to a fixed
+// field index path and skips type checks when the output type of its source
+// task guarantees the value fits the parameter; any other spec, including
+// every runtime input, is resolved and checked dynamically. Field paths
+// select fields with match, see MatchFields.
+func compileTask(task *internal.Task, outputTypes map[string]reflect.Type, match FieldMatcher) *compiledTask {
+	specs, types := task.GetInputParams()
+	args := make([]argResolver, len(specs))
 	for i, spec := range specs {
-		value, err := results.Get(spec.Source)
+		param := i + 2             // array offset (1) + first param is context (1) = 2
+		expectedType := types[i+1] // +1 to skip context
+		switch spec.Type {
+		case internal.TemplateInputSpec:
+			args[i] = templateArg(task.GetID(), spec, param)
+			continue
+		case internal.FieldsInputSpec:
+			args[i] = fieldsArg(task.GetID(), spec, param, expectedType, match)
+			continue
+		case internal.TaggedInputSpec:
+			args[i] = taggedArg(task.GetID(), spec, param, expectedType, match)
+			continue
+		}
+		if path, ok := compileFieldPath(outputTypes[spec.Source], spec.Field, expectedType, match); ok {
+			args[i] = staticArg(task.GetID(), spec, param, expectedType, path)
+			continue
+		}
+		args[i] = dynamicArg(task.GetID(), spec, param, expectedType, match)
+	}
+	return &compiledTask{Task: task, args: args}
+}
+
+// resolve returns the arguments to call the task with, starting with ctx.
+func (t *compiledTask) resolve(ctx context.Context, results *Result) ([]reflect.Value, error) {
+	values := make([]reflect.Value, len(t.args)+1)
+	values[0] = reflect.ValueOf(ctx) // First arg is always context
+	for i, arg := range t.args {
+		value, err := arg(results)
 		if err != nil {
-			return nil, errors.Wrapf(
-				err,
-				"failed to get %v for task %q, did you miss to set in run config",
-				spec.Source,
-				task.GetID(),
-			)
+			return nil, err
+		}
+		values[i+1] = value
+	}
+	return values, nil
+}
+
+// fieldStep selects a struct field by index, dereferencing a pointer first
+// when deref is set.
+type fieldStep struct {
+	name  string
+	deref bool
+	index []int
+}
+
+// compileFieldPath resolves fields against typ and reports whether the
+// selected field is always assignable to expectedType. It fails for unknown
+// or interface source types and for paths that can only fail at run time.
+func compileFieldPath(typ reflect.Type, fields []string, expectedType reflect.Type, match FieldMatcher) ([]fieldStep, bool) {
+	if typ == nil || typ.Kind() == reflect.Interface {
+		return nil, false
+	}
+	var path []fieldStep
+	for _, name := range fields {
+		if name == "" { // Skipping empty path fields
+			continue
+		}
+		step := fieldStep{name: name}
+		if typ.Kind() == reflect.Ptr {
+			step.deref = true
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := lookupField(typ, name, match)
+		if !ok || !field.IsExported() {
+			return nil, false
+		}
+		step.index = field.Index
+		path = append(path, step)
+		typ = field.Type
+	}
+	if typ.Kind() == reflect.Interface || !typ.AssignableTo(expectedType) {
+		return nil, false
+	}
+	return path, true
+}
+
+// staticArg reads the source value and walks the compiled field path.
+//
+//nolint:err113 // static error because its too specific
+func staticArg(taskID string, spec internal.InputSpec, param int, expectedType reflect.Type, path []fieldStep) argResolver {
+	return func(results *Result) (reflect.Value, error) {
+		value, err := getSource(results, taskID, spec)
+		if err != nil {
+			if fallback, ok := optionalValue(spec, expectedType); ok {
+				return fallback, nil
+			}
+			return reflect.Value{}, err
+		}
+		current := reflect.ValueOf(value)
+		for _, step := range path {
+			// An untyped nil stands for a nil value of the source type.
+			if !current.IsValid() || (step.deref && current.IsNil()) {
+				return reflect.Value{}, errors.Wrapf(
+					fmt.Errorf("nil pointer encountered while accessing field %q", step.name),
+					"parameter %d",
+					param,
+				)
+			}
+			if step.deref {
+				current = current.Elem()
+			}
+			current, err = current.FieldByIndexErr(step.index)
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "parameter %d", param)
+			}
+		}
+		if !current.IsValid() {
+			// An untyped nil output of a nilable type, assignable to
+			// expectedType since the path compiled.
+			return reflect.Zero(expectedType), nil
+		}
+		return current, nil
+	}
+}
+
+// dynamicArg reads the source value, extracts the field path by name and
+// checks the type of the result.
+func dynamicArg(taskID string, spec internal.InputSpec, param int, expectedType reflect.Type, match FieldMatcher) argResolver {
+	return func(results *Result) (reflect.Value, error) {
+		value, err := getSource(results, taskID, spec)
+		if err != nil {
+			if fallback, ok := optionalValue(spec, expectedType); ok {
+				return fallback, nil
+			}
+			return reflect.Value{}, err
 		}
 		if len(spec.Field) > 0 {
-			value, err = extractNestedField(value, spec.Field)
+			value, err = extractNestedField(value, spec.Field, match)
 			if err != nil {
-				return nil, errors.Wrapf(err, "parameter %d", i+2)
+				return reflect.Value{}, errors.Wrapf(err, "parameter %d", param)
 			}
 		}
 
-		expectedType := types[i+1] // +1 to skip context
 		actualValue := reflect.ValueOf(value)
+		if !actualValue.IsValid() {
+			if zero, ok := nilValue(expectedType); ok {
+				return zero, nil
+			}
+			return reflect.Value{}, errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"parameter %d -> expected type %s, got untyped nil",
+				param,
+				expectedType,
+			)
+		}
 		if !actualValue.Type().AssignableTo(expectedType) {
-			return nil, errors.Wrapf(
+			return reflect.Value{}, errors.Wrapf(
 				errors.ErrInvalidParamType,
 				"parameter %d -> exptected type %s, got %s",
-				i+2, // array offset (1) + first param is context (1) = 2
+				param,
 				expectedType,
-				actualValue.Type(),
+				typeName(actualValue.Type(), spec.Secret || results.IsSecret(spec.Source)),
 			)
 		}
-		args[i+1] = actualValue
+		return actualValue, nil
+	}
+}
+
+// nilValue returns the zero value of typ to pass for an untyped nil, such as
+// a nil run input. ok is false if typ cannot be nil.
+func nilValue(typ reflect.Type) (zero reflect.Value, ok bool) {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return reflect.Zero(typ), true
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+// fieldsArg reads the source value and copies the projected fields into a
+// new value of the parameter struct.
+func fieldsArg(taskID string, spec internal.InputSpec, param int, expectedType reflect.Type, match FieldMatcher) argResolver {
+	return func(results *Result) (reflect.Value, error) {
+		value, err := getSource(results, taskID, spec)
+		if err != nil {
+			if fallback, ok := optionalValue(spec, expectedType); ok {
+				return fallback, nil
+			}
+			return reflect.Value{}, err
+		}
+
+		structType := expectedType
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+		target := reflect.New(structType)
+		secret := spec.Secret || results.IsSecret(spec.Source)
+		for name, path := range spec.Projection {
+			fieldValue, err := extractNestedField(value, path, match)
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "parameter %d field %s", param, name)
+			}
+			field := target.Elem().FieldByName(name)
+			actual := reflect.ValueOf(fieldValue)
+			if !actual.IsValid() || !actual.Type().AssignableTo(field.Type()) {
+				actualType := "nil"
+				if actual.IsValid() {
+					actualType = typeName(actual.Type(), secret)
+				}
+				return reflect.Value{}, errors.Wrapf(
+					errors.ErrInvalidParamType,
+					"parameter %d field %s -> expected type %s, got %s",
+					param,
+					name,
+					field.Type(),
+					actualType,
+				)
+			}
+			field.Set(actual)
+		}
+
+		if expectedType.Kind() == reflect.Ptr {
+			return target, nil
+		}
+		return target.Elem(), nil
+	}
+}
+
+// taggedArg resolves every tagged field like a parameter of its own and
+// sets it in a new value of the parameter struct.
+func taggedArg(taskID string, spec internal.InputSpec, param int, expectedType reflect.Type, match FieldMatcher) argResolver {
+	structType := expectedType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	fields := make([]argResolver, len(spec.Bindings))
+	for i, binding := range spec.Bindings {
+		field, _ := structType.FieldByName(binding.Name)
+		fields[i] = dynamicArg(taskID, binding.Spec, param, field.Type, match)
+	}
+
+	return func(results *Result) (reflect.Value, error) {
+		target := reflect.New(structType)
+		for i, binding := range spec.Bindings {
+			value, err := fields[i](results)
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "field %s", binding.Name)
+			}
+			target.Elem().FieldByName(binding.Name).Set(value)
+		}
+
+		if expectedType.Kind() == reflect.Ptr {
+			return target, nil
+		}
+		return target.Elem(), nil
+	}
+}
+
+func getSource(results *Result, taskID string, spec internal.InputSpec) (any, error) {
+	value, err := results.Get(spec.Source)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"failed to get %v for task %q, did you miss to set in run config",
+			spec.Source,
+			taskID,
+		)
+	}
+	return value, nil
+}
+
+// snapshotInputs wraps the error of a failed task with the inputs it was
+// called with, masking secret values. Errors of tasks whose inputs could
+// not be resolved are returned unchanged.
+func snapshotInputs(ctx context.Context, task *compiledTask, results *Result, err error) error {
+	inputs, ok := inputSnapshots(ctx, task, results)
+	if !ok {
+		return err
+	}
+	return &errors.TaskInputsError{TaskID: task.GetID(), Inputs: inputs, Err: err}
+}
+
+// inputSnapshots resolves the inputs of the task again and describes them,
+// masking secret values. ok is false if the inputs cannot be resolved.
+func inputSnapshots(ctx context.Context, task *compiledTask, results *Result) (inputs []errors.InputSnapshot, ok bool) {
+	args, err := task.resolve(ctx, results)
+	if err != nil {
+		return nil, false
 	}
 
-	return args, nil
+	specs, _ := task.GetInputParams()
+	inputs = make([]errors.InputSnapshot, 0, len(specs))
+	for i, spec := range specs {
+		var value any = Redacted
+		if !spec.Secret && !results.IsSecret(spec.Source) && !slices.ContainsFunc(spec.Refs, results.IsSecret) {
+			value = args[i+1].Interface() // +1 to skip context
+		}
+		inputs = append(inputs, errors.InputSnapshot{
+			Param:  i + 2, // array offset (1) + first param is context (1) = 2
+			Source: spec.Source,
+			Field:  spec.Field,
+			Value:  value,
+		})
+	}
+	return inputs, true
 }
 
 //nolint:err113 // static error because its too specific
 //revive:disable-next-line:cognitive-complexity // struct walking algo is complex.
-func extractNestedField(value any, fields []string) (any, error) {
+func extractNestedField(value any, fields []string, match FieldMatcher) (any, error) {
 	if value == nil {
 		return nil, stderr.New("value is nil")
 	}
@@ -79,15 +367,19 @@ func extractNestedField(value any, fields []string) (any, error) {
 			return nil, fmt.Errorf("field %q is not a struct (found %s)", fieldName, current.Kind())
 		}
 
-		fieldValue := current.FieldByName(fieldName)
-		if !fieldValue.IsValid() {
+		field, ok := lookupField(current.Type(), fieldName, match)
+		if !ok {
 			return nil, fmt.Errorf("field %q not found in type %v", fieldName, current.Type())
 		}
 
-		if !fieldValue.CanInterface() {
+		if !field.IsExported() {
 			return nil, fmt.Errorf("field %q is not exported in type %v", fieldName, current.Type())
 		}
 
+		fieldValue, err := current.FieldByIndexErr(field.Index)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", fieldName, err)
+		}
 		current = fieldValue
 	}
 
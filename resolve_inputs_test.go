@@ -2,6 +2,7 @@ package lyra
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -301,3 +302,191 @@ func TestLyraResolveInputsNilValue(t *testing.T) {
 	require.Len(t, args, 2)
 	require.True(t, args[1].IsNil())
 }
+
+func TestLyraResolveInputsUntypedNil(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		fn   any
+		err  error
+	}{
+		{
+			name: "pointer",
+			fn:   func(ctx context.Context, user *User) error { return nil },
+		},
+		{
+			name: "map",
+			fn:   func(ctx context.Context, labels map[string]string) error { return nil },
+		},
+		{
+			name: "interface",
+			fn:   func(ctx context.Context, value any) error { return nil },
+		},
+		{
+			name: "non-nilable",
+			fn:   func(ctx context.Context, userID int) error { return nil },
+			err:  errors.ErrInvalidParamType,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			task, err := internal.NewTask("untypedNil", tc.fn, []internal.InputSpec{UseRun("value")})
+			require.NoError(t, err)
+			results := NewResult()
+			results.set("value", nil)
+
+			args, err := resolveInputs(context.Background(), task, results)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				require.Len(t, args, 2)
+				require.True(t, args[1].IsZero())
+			}
+		})
+	}
+}
+
+func TestCompileFieldPath(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		City  string
+		Any   any
+		email string
+	}
+	type Outer struct {
+		Inner *Inner
+		Name  string
+	}
+
+	tcs := []struct {
+		name     string
+		typ      reflect.Type
+		fields   []string
+		expected reflect.Type
+		compiled bool
+		path     []fieldStep
+	}{
+		{
+			name:     "whole value",
+			typ:      reflect.TypeOf(Outer{}),
+			expected: reflect.TypeOf(Outer{}),
+			compiled: true,
+		},
+		{
+			name:     "nested through pointer",
+			typ:      reflect.TypeOf(&Outer{}),
+			fields:   []string{"Inner", "", "City"},
+			expected: reflect.TypeOf(""),
+			compiled: true,
+			path: []fieldStep{
+				{name: "Inner", deref: true, index: []int{0}},
+				{name: "City", deref: true, index: []int{0}},
+			},
+		},
+		{
+			name:     "unknown source type",
+			fields:   []string{"Name"},
+			expected: reflect.TypeOf(""),
+		},
+		{
+			name:     "interface field",
+			typ:      reflect.TypeOf(Outer{}),
+			fields:   []string{"Inner", "Any"},
+			expected: reflect.TypeOf(""),
+		},
+		{
+			name:     "unexported field",
+			typ:      reflect.TypeOf(Outer{}),
+			fields:   []string{"Inner", "email"},
+			expected: reflect.TypeOf(""),
+		},
+		{
+			name:     "missing field",
+			typ:      reflect.TypeOf(Outer{}),
+			fields:   []string{"Missing"},
+			expected: reflect.TypeOf(""),
+		},
+		{
+			name:     "not assignable",
+			typ:      reflect.TypeOf(Outer{}),
+			fields:   []string{"Name"},
+			expected: reflect.TypeOf(0),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, ok := compileFieldPath(tc.typ, tc.fields, tc.expected, nil)
+			require.Equal(t, tc.compiled, ok)
+			require.Equal(t, tc.path, path)
+		})
+	}
+}
+
+func TestCompiledTaskResolve(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string
+	}
+	type User struct {
+		Address *Address
+	}
+
+	task, err := internal.NewTask("city",
+		func(ctx context.Context, city string) (string, error) { return city, nil },
+		[]internal.InputSpec{Use("fetchUser", "Address", "City")})
+	require.NoError(t, err)
+	compiled := compileTask(task, map[string]reflect.Type{"fetchUser": reflect.TypeOf(User{})}, nil)
+
+	results := NewResult()
+	results.set("fetchUser", User{Address: &Address{City: "Boston"}})
+	args, err := compiled.resolve(context.Background(), results)
+	require.NoError(t, err)
+	require.Equal(t, "Boston", args[1].Interface())
+
+	results.set("fetchUser", User{})
+	_, err = compiled.resolve(context.Background(), results)
+	require.ErrorContains(t, err, `parameter 2: nil pointer encountered while accessing field "City"`)
+
+	_, err = compiled.resolve(context.Background(), NewResult())
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestCompiledTaskResolveUntypedNil(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string
+	}
+	type User struct {
+		Address *Address
+	}
+	outputTypes := map[string]reflect.Type{"fetchUser": reflect.TypeOf(&User{})}
+	results := NewResult()
+	results.set("fetchUser", nil) // an output stored without its type
+
+	task, err := internal.NewTask("user",
+		func(ctx context.Context, user *User) error { return nil },
+		[]internal.InputSpec{Use("fetchUser")})
+	require.NoError(t, err)
+	args, err := compileTask(task, outputTypes, nil).resolve(context.Background(), results)
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	require.True(t, args[1].IsNil())
+	require.Equal(t, reflect.TypeOf(&User{}), args[1].Type())
+
+	task, err = internal.NewTask("city",
+		func(ctx context.Context, city *Address) error { return nil },
+		[]internal.InputSpec{Use("fetchUser", "Address")})
+	require.NoError(t, err)
+	_, err = compileTask(task, outputTypes, nil).resolve(context.Background(), results)
+	require.ErrorContains(t, err, `parameter 2: nil pointer encountered while accessing field "Address"`)
+}
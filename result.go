@@ -2,17 +2,41 @@ package lyra
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/sourabh-kumar2/lyra/errors"
 )
 
+// resultSlot holds the value of a task of the DAG a Result was created
+// for, so it is read and written without locks.
+type resultSlot struct {
+	value atomic.Pointer[any] // nil while the task has no value
+}
+
 // Result holds the results of DAG execution in a thread-safe manner.
 // Results are stored as interface{} and require type assertion when retrieved.
 //
 // The zero value is not usable; Result instances are created by Lyra.Run().
 type Result struct {
-	mu   sync.RWMutex
-	data map[string]any
+	// slots holds the values of the tasks of the DAG, at the indexes in
+	// slotIndexes; both are fixed when the Result is created by Lyra.Run.
+	slots       []resultSlot
+	slotIndexes map[string]int
+
+	// mu guards the fields below.
+	mu sync.RWMutex
+	// data holds the values without a slot: runtime inputs, and every
+	// value of a Result not created by Lyra.Run.
+	data    map[string]any
+	secrets map[string]struct{}
+	// final holds the IDs of the tasks no other task depends on; nil
+	// unless the Result was created by Lyra.Run.
+	final map[string]struct{}
+	// groups holds the outcome of every group of tasks (see WithGroup).
+	groups map[string]GroupOutcome
+	// failures holds the errors of the tasks that failed without failing
+	// the run.
+	failures map[string]error
 }
 
 // NewResult creates a new Result instance for storing task execution results.
@@ -39,18 +63,37 @@ func NewResult() *Result {
 // For safer type handling, consider storing results in typed variables
 // immediately after retrieval.
 func (r *Result) Get(taskID string) (any, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	data, ok := r.data[taskID]
+	data, ok := r.load(taskID)
 	if !ok {
 		return nil, errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
 	}
 	return data, nil
 }
 
+// load returns the value stored under key.
+func (r *Result) load(key string) (any, bool) {
+	if i, ok := r.slotIndexes[key]; ok {
+		value := r.slots[i].value.Load()
+		if value == nil {
+			return nil, false
+		}
+		return *value, true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.data[key]
+	return value, ok
+}
+
 // set stores a result for the given task ID. Initializes internal storage if needed.
 func (r *Result) set(taskID string, result any) {
+	if i, ok := r.slotIndexes[taskID]; ok {
+		r.slots[i].value.Store(&result)
+		return
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.data == nil {
@@ -58,3 +101,70 @@ func (r *Result) set(taskID string, result any) {
 	}
 	r.data[taskID] = result
 }
+
+// remove deletes the result of the task.
+func (r *Result) remove(taskID string) {
+	if i, ok := r.slotIndexes[taskID]; ok {
+		r.slots[i].value.Store(nil)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.data, taskID)
+}
+
+// values returns a copy of all stored values, built on demand from the
+// slots and data.
+func (r *Result) values() map[string]any {
+	values := make(map[string]any, len(r.slotIndexes))
+	for taskID, i := range r.slotIndexes {
+		if value := r.slots[i].value.Load(); value != nil {
+			values[taskID] = *value
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for key, value := range r.data {
+		values[key] = value
+	}
+	return values
+}
+
+// IsSecret reports whether the value stored under key was marked as
+// sensitive with Secret or WithSecretOutput.
+func (r *Result) IsSecret(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.secrets[key]
+	return ok
+}
+
+// Redacted returns a copy of all stored values with sensitive values
+// replaced by Redacted, for logging or exporting the results of a run.
+func (r *Result) Redacted() map[string]any {
+	data := r.values()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for key := range data {
+		if _, ok := r.secrets[key]; ok {
+			data[key] = Redacted
+		}
+	}
+	return data
+}
+
+// slotIndexes assigns result slots to the tasks of stages, in execution
+// order.
+func slotIndexes(stages [][]string) map[string]int {
+	slots := make(map[string]int)
+	for _, stage := range stages {
+		for _, taskID := range stage {
+			slots[taskID] = len(slots)
+		}
+	}
+	return slots
+}
@@ -0,0 +1,77 @@
+package lyra
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// Decode stores the results of tasks into the fields of the struct dst
+// points to, so a pipeline's results can be read through a typed struct
+// instead of Get and type assertions.
+//
+// Each exported field receives the result of the task named by its `lyra`
+// struct tag, defaulting to the field name. The result must be assignable
+// to the field. A field tagged with the omitempty option is left untouched
+// when the task has no result, such as a skipped task:
+//
+//	type CheckoutResults struct {
+//		FetchUser User    `lyra:"fetchUser"`
+//		Total     float64 `lyra:"computeTotal"`
+//		Coupon    Coupon  `lyra:"applyCoupon,omitempty"`
+//		Debug     string  `lyra:"-"` // never decoded
+//	}
+//
+//	var out CheckoutResults
+//	if err := results.Decode(&out); err != nil {
+//		return err
+//	}
+//
+// Returns ErrInvalidParamType if dst is not a non-nil pointer to a struct
+// or a result does not fit its field, and ErrTaskNotFound if a result is
+// missing.
+func (r *Result) Decode(dst any) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.Wrapf(errors.ErrInvalidParamType, "decode into %T, want a non-nil pointer to a struct", dst)
+	}
+	value = value.Elem()
+
+	structType := value.Type()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		tag := field.Tag.Get(inputTag)
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		taskID, flags, _ := strings.Cut(tag, ",")
+		if taskID == "" {
+			taskID = field.Name
+		}
+
+		result, ok := r.load(taskID)
+		if !ok {
+			if flags == "omitempty" {
+				continue
+			}
+			return errors.Wrapf(errors.ErrTaskNotFound, "field %s: taskID:%s", field.Name, taskID)
+		}
+		if result == nil {
+			value.Field(i).SetZero()
+			continue
+		}
+		if !reflect.TypeOf(result).AssignableTo(field.Type) {
+			return errors.Wrapf(
+				errors.ErrInvalidParamType,
+				"field %s: result of task %q is %T, want %s",
+				field.Name,
+				taskID,
+				result,
+				field.Type,
+			)
+		}
+		value.Field(i).Set(reflect.ValueOf(result))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package lyra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestResultDecode(t *testing.T) {
+	t.Parallel()
+
+	type checkoutResults struct {
+		FetchUser User    `lyra:"fetchUser"`
+		Total     float64 `lyra:"computeTotal"`
+		Coupon    string  `lyra:"applyCoupon,omitempty"`
+		Ignored   string  `lyra:"-"`
+		Notify    error   `lyra:"notify"`
+		Region    string
+		hidden    int
+	}
+
+	r := NewResult()
+	r.set("fetchUser", User{ID: 1, Name: "Ada"})
+	r.set("computeTotal", 9.5)
+	r.set("notify", nil)
+	r.set("Region", "eu")
+
+	out := checkoutResults{Coupon: "kept", Ignored: "kept", hidden: 3}
+	require.NoError(t, r.Decode(&out))
+	require.Equal(t, checkoutResults{
+		FetchUser: User{ID: 1, Name: "Ada"},
+		Total:     9.5,
+		Coupon:    "kept",
+		Ignored:   "kept",
+		Region:    "eu",
+		hidden:    3,
+	}, out)
+}
+
+func TestResultDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	r := NewResult()
+	r.set("total", "not a number")
+
+	tcs := []struct {
+		name string
+		dst  any
+		err  error
+	}{
+		{name: "not a pointer", dst: struct{}{}, err: errors.ErrInvalidParamType},
+		{name: "nil pointer", dst: (*struct{})(nil), err: errors.ErrInvalidParamType},
+		{name: "pointer to non-struct", dst: new(int), err: errors.ErrInvalidParamType},
+		{name: "type mismatch", dst: &struct {
+			Total float64 `lyra:"total"`
+		}{}, err: errors.ErrInvalidParamType},
+		{name: "missing result", dst: &struct {
+			User User `lyra:"fetchUser"`
+		}{}, err: errors.ErrTaskNotFound},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, r.Decode(tc.dst), tc.err)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package lyra
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeKind describes how a value differs between two Results.
+type ChangeKind string
+
+const (
+	// ChangeAdded marks a value missing from the baseline.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved marks a value missing from the compared Result.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified marks a value present in both but not equal.
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a value that differs between a baseline and a Result.
+type Change struct {
+	// Key is the task ID or runtime input key of the value.
+	Key  string
+	Kind ChangeKind
+	// Baseline and Value hold the value in each Result, nil when missing.
+	// Sensitive values are replaced by Redacted.
+	Baseline any
+	Value    any
+}
+
+// Diff compares r against baseline, typically the Result of an earlier run
+// of the same DAG, and returns the values that changed sorted by key.
+// Values are compared with reflect.DeepEqual.
+//
+// Example:
+//
+//	for _, c := range result.Diff(baseline) {
+//		t.Errorf("%s %s: %v -> %v", c.Key, c.Kind, c.Baseline, c.Value)
+//	}
+func (r *Result) Diff(baseline *Result) []Change {
+	return diffValues(baseline.Redacted(), r.Redacted())
+}
+
+// DiffJSON is like Diff, with the baseline recorded by MarshalJSON or
+// ToJSON. Values are compared by their JSON encoding, so they must be
+// encodable; the Baseline of a Change holds the decoded JSON value.
+func (r *Result) DiffJSON(baseline []byte) ([]Change, error) {
+	var recorded map[string]any
+	if err := json.Unmarshal(baseline, &recorded); err != nil {
+		return nil, err //nolint:wrapcheck // the baseline is the caller's.
+	}
+
+	current := r.Redacted()
+	normalized := make(map[string]any, len(current))
+	for key, value := range current {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // the value is the caller's.
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, err //nolint:wrapcheck // the value is the caller's.
+		}
+		normalized[key] = decoded
+	}
+
+	changes := diffValues(recorded, normalized)
+	for i, change := range changes {
+		if change.Kind != ChangeRemoved {
+			changes[i].Value = current[change.Key]
+		}
+	}
+	return changes, nil
+}
+
+// diffValues returns the changes from baseline to current sorted by key.
+func diffValues(baseline, current map[string]any) []Change {
+	changes := make([]Change, 0)
+	for key, value := range current {
+		before, ok := baseline[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: key, Kind: ChangeAdded, Value: value})
+		case !reflect.DeepEqual(before, value):
+			changes = append(changes, Change{Key: key, Kind: ChangeModified, Baseline: before, Value: value})
+		}
+	}
+	for key, before := range baseline {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, Change{Key: key, Kind: ChangeRemoved, Baseline: before})
+		}
+	}
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
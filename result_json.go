@@ -0,0 +1,76 @@
+package lyra
+
+import (
+	"encoding/json"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// MarshalJSON encodes the stored values as a JSON object keyed by task ID
+// or runtime input key. Sensitive values are replaced by Redacted.
+//
+// Example:
+//
+//	result, err := l.Run(r.Context(), inputs)
+//	...
+//	json.NewEncoder(w).Encode(result.Final())
+func (r *Result) MarshalJSON() ([]byte, error) {
+	//nolint:wrapcheck // the value errors are the caller's to handle.
+	return json.Marshal(r.Redacted())
+}
+
+// ToJSON encodes the values stored under taskIDs like MarshalJSON, or every
+// stored value when no task ID is given. It returns ErrTaskNotFound if a
+// task ID has no value.
+func (r *Result) ToJSON(taskIDs ...string) ([]byte, error) {
+	if len(taskIDs) == 0 {
+		return r.MarshalJSON()
+	}
+
+	redacted := r.Redacted()
+	selected := make(map[string]any, len(taskIDs))
+	for _, taskID := range taskIDs {
+		value, ok := redacted[taskID]
+		if !ok {
+			return nil, errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
+		}
+		selected[taskID] = value
+	}
+	//nolint:wrapcheck // the value errors are the caller's to handle.
+	return json.Marshal(selected)
+}
+
+// Final returns a Result holding only the outputs of the tasks no other task
+// depends on, omitting runtime inputs and intermediate outputs. A Result not
+// returned by Lyra.Run keeps all its values.
+func (r *Result) Final() *Result {
+	values := r.values()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	final := &Result{
+		secrets: r.secrets,
+		final:   r.final,
+	}
+	for key, value := range values {
+		if _, ok := r.final[key]; ok || r.final == nil {
+			final.set(key, value)
+		}
+	}
+	return final
+}
+
+// leafTasks returns the IDs of the tasks no other task depends on.
+func leafTasks(deps map[string][]string) map[string]struct{} {
+	leaves := make(map[string]struct{}, len(deps))
+	for taskID := range deps {
+		leaves[taskID] = struct{}{}
+	}
+	for _, taskDeps := range deps {
+		for _, dep := range taskDeps {
+			delete(leaves, dep)
+		}
+	}
+	return leaves
+}
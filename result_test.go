@@ -1,6 +1,7 @@
 package lyra
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -250,10 +251,125 @@ func TestResultsConcurrency(t *testing.T) {
 
 	wg.Wait()
 
-	require.Len(t, r.data, numGoroutines+1)
+	require.Len(t, r.values(), numGoroutines+1)
 }
 
 type testStruct struct {
 	Name string
 	ID   int
 }
+
+func TestResultJSON(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("user", func(ctx context.Context, id int) (string, error) {
+			return fmt.Sprintf("user-%d", id), nil
+		}, UseRun("userID")).
+		Do("token", func(ctx context.Context) (string, error) {
+			return "tok", nil
+		}, WithSecretOutput()).
+		Do("greeting", func(ctx context.Context, name string) (string, error) {
+			return "Hello " + name, nil
+		}, Use("user"))
+
+	result, err := l.Run(context.Background(), map[string]any{"userID": 7})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		marshal  func() ([]byte, error)
+		expected string
+	}{
+		{
+			name:     "all values",
+			marshal:  result.MarshalJSON,
+			expected: `{"greeting":"Hello user-7","token":"[REDACTED]","user":"user-7","userID":7}`,
+		},
+		{
+			name:     "selected tasks",
+			marshal:  func() ([]byte, error) { return result.ToJSON("user", "greeting") },
+			expected: `{"greeting":"Hello user-7","user":"user-7"}`,
+		},
+		{
+			name:     "final outputs",
+			marshal:  result.Final().MarshalJSON,
+			expected: `{"greeting":"Hello user-7","token":"[REDACTED]"}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tc.marshal()
+
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(data))
+		})
+	}
+
+	_, err = result.ToJSON("missing")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestResultDiff(t *testing.T) {
+	t.Parallel()
+
+	build := func(scale int) *Lyra {
+		l := New().
+			Do("base", func(ctx context.Context, n int) (int, error) { return n, nil }, UseRun("n")).
+			Do("scaled", func(ctx context.Context, n int) ([]int, error) { return []int{n, n * scale}, nil }, Use("base"))
+		if scale > 1 {
+			l.Do("extra", func(ctx context.Context) (string, error) { return "new", nil })
+		}
+		return l
+	}
+
+	baseline, err := build(1).Run(context.Background(), map[string]any{"n": 2})
+	require.NoError(t, err)
+	current, err := build(3).Run(context.Background(), map[string]any{"n": 2})
+	require.NoError(t, err)
+
+	expected := []Change{
+		{Key: "extra", Kind: ChangeAdded, Value: "new"},
+		{Key: "scaled", Kind: ChangeModified, Baseline: []int{2, 2}, Value: []int{2, 6}},
+	}
+	require.Equal(t, expected, current.Diff(baseline))
+	require.Empty(t, baseline.Diff(baseline))
+
+	recorded, err := baseline.MarshalJSON()
+	require.NoError(t, err)
+	changes, err := current.DiffJSON(recorded)
+	require.NoError(t, err)
+	expected[1].Baseline = []any{2.0, 2.0}
+	require.Equal(t, expected, changes)
+
+	changes, err = baseline.DiffJSON([]byte(`{"n":2,"base":2,"scaled":[2,2],"old":true}`))
+	require.NoError(t, err)
+	require.Equal(t, []Change{{Key: "old", Kind: ChangeRemoved, Baseline: true}}, changes)
+}
+
+func TestResultSlots(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("a", func(ctx context.Context, n int) (int, error) { return n + 1, nil }, UseRun("n")).
+		Do("b", func(ctx context.Context, a int) (int, error) { return a * 2, nil }, Use("a"))
+
+	r, err := l.Run(context.Background(), map[string]any{"n": 1})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 0, "b": 1}, r.slotIndexes)
+	require.Len(t, r.slots, 2)
+	require.Equal(t, map[string]any{"n": 1}, r.data)
+
+	got, err := r.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, 4, got)
+	require.Equal(t, map[string]any{"n": 1, "a": 2, "b": 4}, r.values())
+
+	r.remove("a")
+	_, err = r.Get("a")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+	require.Equal(t, map[string]any{"n": 1, "b": 4}, r.values())
+}
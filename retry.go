@@ -0,0 +1,87 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// RetryableError is implemented by errors that know whether the failed
+// operation can succeed if attempted again. Task retries (see WithRetry)
+// stop at the first error whose Retryable method returns false, such as a
+// validation error. Errors that do not implement it anywhere in their
+// chain are retried.
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// Permanent marks err as not worth retrying: a task returning it fails
+// without using its remaining retries. It returns nil if err is nil.
+//
+// Example:
+//
+//	if order.Amount <= 0 {
+//		return nil, lyra.Permanent(fmt.Errorf("invalid amount %d", order.Amount))
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err}
+}
+
+// Transient marks err as worth retrying, overriding the classification of
+// the errors it wraps. It returns nil if err is nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, retryable: true}
+}
+
+// classifiedError is an error classified with Permanent or Transient.
+type classifiedError struct {
+	err       error
+	retryable bool
+}
+
+func (e *classifiedError) Error() string   { return e.err.Error() }
+func (e *classifiedError) Unwrap() error   { return e.err }
+func (e *classifiedError) Retryable() bool { return e.retryable }
+
+// isRetryable reports whether err may succeed if attempted again, as
+// classified by the first RetryableError in its chain.
+func isRetryable(err error) bool {
+	var classified RetryableError
+	if stderr.As(err, &classified) {
+		return classified.Retryable()
+	}
+	return true
+}
+
+// callWithRetries calls the task until it succeeds, fails with an error
+// that is not retryable (see RetryableError), its retries or the run's
+// retry budget are used up, or ctx is done.
+func callWithRetries(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	retries := task.GetOptions().Retries
+	for attempt := 1; ; attempt++ {
+		output, hasOutput, err = callWithTimeout(contextWithTask(ctx, task.GetID(), attempt), stageIdx, task, state)
+		if err == nil || attempt > retries || ctx.Err() != nil || !isRetryable(err) {
+			return output, hasOutput, err
+		}
+		if !state.takeRetry() {
+			return nil, hasOutput, errors.Wrapf(err, "%w", errors.ErrRetryBudgetExhausted)
+		}
+		state.emit(Event{Type: EventTaskRetrying, Stage: stageIdx, TaskID: task.GetID(), Err: err})
+		if !waitBackoff(ctx, task, attempt) {
+			return nil, hasOutput, err
+		}
+	}
+}
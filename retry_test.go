@@ -0,0 +1,149 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestRunWithRetry(t *testing.T) {
+	t.Parallel()
+
+	var attempts []int
+	l := New().Do("flaky", func(ctx context.Context) (int, error) {
+		attempt, _ := AttemptFromContext(ctx)
+		attempts = append(attempts, attempt)
+		if attempt < 3 {
+			return 0, errTaskFailed
+		}
+		return attempt, nil
+	}, WithRetry(2))
+
+	run := l.RunAsync(context.Background(), nil)
+	result, err := run.Wait()
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, attempts)
+	value, err := result.Get("flaky")
+	require.NoError(t, err)
+	require.Equal(t, 3, value)
+
+	retrying := eventsOfType(collectEvents(run), EventTaskRetrying)
+	require.Len(t, retrying, 2)
+	require.ErrorIs(t, retrying[0].Err, errTaskFailed)
+}
+
+func TestRunWithRetryGivesUp(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	l := New().Do("broken", func(ctx context.Context) error {
+		calls.Add(1)
+		return errTaskFailed
+	}, WithRetry(2))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errTaskFailed)
+	require.NotErrorIs(t, err, errors.ErrRetryBudgetExhausted)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRunWithRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name          string
+		budget        int
+		expectedCalls int32
+		exhausted     bool
+	}{
+		{
+			name:          "budget shared by all tasks",
+			budget:        3,
+			expectedCalls: 5, // 2 first attempts + 3 retries
+			exhausted:     true,
+		},
+		{
+			name:          "zero budget disables retries",
+			budget:        0,
+			expectedCalls: 2,
+			exhausted:     true,
+		},
+		{
+			name:          "negative budget is unlimited",
+			budget:        -1,
+			expectedCalls: 6,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			broken := func(ctx context.Context) error {
+				calls.Add(1)
+				return errTaskFailed
+			}
+			l := New().
+				Do("first", broken, WithRetry(2)).
+				Do("second", broken, WithRetry(2))
+
+			_, err := l.Run(context.Background(), nil, WithRetryBudget(tc.budget))
+
+			require.ErrorIs(t, err, errTaskFailed)
+			if tc.exhausted {
+				require.ErrorIs(t, err, errors.ErrRetryBudgetExhausted)
+			} else {
+				require.NotErrorIs(t, err, errors.ErrRetryBudgetExhausted)
+			}
+			require.Equal(t, tc.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestRunWithRetryClassifiedErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name          string
+		err           error
+		expectedCalls int32
+	}{
+		{name: "unclassified", err: errTaskFailed, expectedCalls: 3},
+		{name: "permanent", err: Permanent(errTaskFailed), expectedCalls: 1},
+		{name: "wrapped permanent", err: fmt.Errorf("validate: %w", Permanent(errTaskFailed)), expectedCalls: 1},
+		{name: "transient", err: Transient(errTaskFailed), expectedCalls: 3},
+		{name: "transient over permanent", err: Transient(Permanent(errTaskFailed)), expectedCalls: 3},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			l := New().Do("task", func(ctx context.Context) error {
+				calls.Add(1)
+				return tc.err
+			}, WithRetry(2))
+
+			_, err := l.Run(context.Background(), nil)
+
+			require.ErrorIs(t, err, errTaskFailed)
+			require.Equal(t, tc.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestClassifyNilError(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Permanent(nil))
+	require.NoError(t, Transient(nil))
+}
@@ -0,0 +1,151 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// Run is a handle to an execution started with Lyra.RunAsync.
+//
+// All methods are safe for concurrent use.
+type Run struct {
+	id     string
+	state  *runState
+	events *eventLog
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	result *Result
+	err    error
+}
+
+// RunAsync starts executing the DAG in the background and returns a handle
+// to observe and wait for the run. It accepts the same inputs and options
+// as Run.
+//
+// Example:
+//
+//	run := l.RunAsync(ctx, inputs)
+//	go func() {
+//		for event := range run.Events() {
+//			log.Printf("%s %s", event.Type, event.TaskID)
+//		}
+//	}()
+//	results, err := run.Wait()
+func (l *Lyra) RunAsync(ctx context.Context, runInputs map[string]any, opts ...RunOption) *Run {
+	cfg := newRunConfig(opts)
+	run := &Run{
+		id:     cfg.runID,
+		events: newEventLog(),
+		done:   make(chan struct{}),
+	}
+
+	ctx, run.cancel = context.WithCancel(ctx)
+	run.state, run.err = l.prepare(runInputs, cfg)
+	if run.err != nil {
+		run.cancel()
+		run.events.close()
+		close(run.done)
+		return run
+	}
+	run.state.events = run.events
+
+	go func() {
+		defer close(run.done)
+		defer run.events.close()
+		defer run.cancel()
+		run.result, run.err = l.execute(ctx, run.state)
+	}()
+
+	return run
+}
+
+// ID returns the run ID, which is also available to tasks through
+// RunIDFromContext.
+func (r *Run) ID() string {
+	return r.id
+}
+
+// Events returns a channel delivering every execution event of the run in
+// order, starting from the first one regardless of when Events is called.
+// The channel is closed after the run has finished and the last event has
+// been delivered.
+//
+// Each call returns a new, independent channel. Events are buffered without
+// limit, so slow consumers never block task execution, but every channel
+// must be drained to release its goroutine.
+func (r *Run) Events() <-chan Event {
+	return r.events.subscribe()
+}
+
+// Done returns a channel that is closed when the run has finished.
+func (r *Run) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the run has finished and returns the same values Run
+// would have returned.
+func (r *Run) Wait() (*Result, error) {
+	<-r.done
+	return r.result, r.err
+}
+
+// Cancel stops the run: tasks that have not started never run and running
+// tasks have their context canceled. Wait then returns an error wrapping
+// context.Canceled. Canceling a finished run has no effect.
+func (r *Run) Cancel() {
+	r.cancel()
+}
+
+// Status returns the current status of the run.
+func (r *Run) Status() RunStatus {
+	select {
+	case <-r.done:
+	default:
+		return RunRunning
+	}
+	switch {
+	case r.err == nil:
+		return RunSucceeded
+	case stderr.Is(r.err, context.Canceled):
+		return RunCanceled
+	default:
+		return RunFailed
+	}
+}
+
+// Result returns the results stored so far, without waiting. They grow as
+// tasks complete and, unlike the Result returned by Wait, stay available
+// when the run fails. Returns nil if the run could not start.
+func (r *Run) Result() *Result {
+	if r.state == nil {
+		return nil
+	}
+	return r.state.result
+}
+
+// CancelTask cancels the task and every task that transitively depends on
+// it, while independent branches keep running. Canceled tasks that have
+// not started never run; running ones have their context canceled and
+// their outcome is discarded. Canceled tasks have no entry in the Result
+// and do not fail the run.
+//
+// Returns ErrTaskNotFound for unknown tasks and ErrTaskAlreadyFinished if
+// the task has already completed, failed, or been canceled.
+func (r *Run) CancelTask(taskID string) error {
+	if r.state == nil {
+		return errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
+	}
+	return r.state.cancelSubtree(taskID)
+}
+
+// TaskStatus returns the current status of the task in this run, or false
+// if the run has no such task.
+func (r *Run) TaskStatus(taskID string) (TaskStatus, bool) {
+	if r.state == nil {
+		return TaskPending, false
+	}
+	return r.state.status(taskID)
+}
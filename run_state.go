@@ -0,0 +1,464 @@
+package lyra
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// runState holds the mutable state of a single call to Lyra.Run. Only the
+// read-only tasks, deps and stages of the DAG snapshot, and the atomic
+// metrics of the Lyra instance, are shared between runs.
+type runState struct {
+	cfg    *runConfig
+	result *Result
+	tasks  map[string]*compiledTask
+	deps   map[string][]string
+	// strictDeps are deps without optional inputs (see Optional); they
+	// default to deps.
+	strictDeps map[string][]string
+	stages     [][]string
+	start      time.Time
+	events     *eventLog
+	metrics    *engineMetrics
+	// inputsHash identifies the runtime inputs (see RunSummary.InputsHash).
+	inputsHash string
+	// bulkheads holds the slots each task must hold while it runs (see
+	// WithBulkhead and LimitTag); they are shared with concurrent runs.
+	bulkheads map[string][]chan struct{}
+	// quorumReaders maps the sources of tasks with a quorum to those tasks
+	// (see WithQuorum).
+	quorumReaders map[string][]string
+
+	mu       sync.Mutex
+	statuses map[string]TaskStatus
+	started  map[string]time.Time
+	finished map[string]time.Time
+	cancels  map[string]context.CancelFunc
+	done     int
+	retries  int
+	// allowedFailures counts the failures allowed by WithAllowedFailures.
+	allowedFailures int
+	// stageSummaries describes every stage.
+	stageSummaries []StageSummary
+	// reports holds the latest value reported by each task (see Report).
+	reports map[string]any
+	// failures holds the error of every failed task.
+	failures map[string]error
+	// attempts holds the calls of the tasks with fallbacks.
+	attempts map[string][]TaskAttempt
+	// allocations holds the allocations of every task when they are
+	// profiled (see WithAllocationProfiling).
+	allocations map[string]taskAllocations
+	// waiters holds the channels closed when a task finishes, for Await.
+	waiters map[string]chan struct{}
+	// seeded holds the outputs of the tasks that do not execute (see
+	// WithSeed and WithPrecomputed).
+	seeded map[string]any
+	// stream receives the outcome of every task for RunStreamResults; it
+	// is buffered for every task so sending never blocks.
+	stream chan<- TaskResult
+
+	// consumers counts the unfinished dependents of every task while
+	// outputs are evicted (see WithRetainedOutputs); nil otherwise.
+	consumers map[string]int
+}
+
+func newRunState(
+	cfg *runConfig,
+	result *Result,
+	deps map[string][]string,
+	stages [][]string,
+) *runState {
+	statuses := make(map[string]TaskStatus, len(deps))
+	for taskID := range deps {
+		statuses[taskID] = TaskPending
+	}
+	var consumers map[string]int
+	if cfg.retained != nil {
+		consumers = make(map[string]int, len(deps))
+		for _, taskDeps := range deps {
+			for _, dep := range distinct(taskDeps) {
+				consumers[dep]++
+			}
+		}
+	}
+	return &runState{
+		consumers:  consumers,
+		cfg:        cfg,
+		result:     result,
+		deps:       deps,
+		strictDeps: deps,
+		stages:     stages,
+		start:      time.Now(),
+		metrics:    &engineMetrics{},
+		statuses:   statuses,
+		started:    make(map[string]time.Time, len(deps)),
+		finished:   make(map[string]time.Time, len(deps)),
+		cancels:    make(map[string]context.CancelFunc),
+
+		stageSummaries: newStageSummaries(stages),
+	}
+}
+
+// emit stamps e with the run ID and the current time, logs it and
+// publishes it.
+func (s *runState) emit(e Event) {
+	if s.events == nil && s.cfg.logger == nil {
+		return
+	}
+	e.RunID = s.cfg.runID
+	e.Time = time.Now()
+	if task, ok := s.tasks[e.TaskID]; ok {
+		opts := task.GetOptions()
+		e.Tags, e.Meta = opts.Tags, opts.Meta
+	}
+	if s.cfg.logger != nil {
+		logEvent(s.cfg.logger, e)
+	}
+	s.events.publish(e)
+}
+
+// status returns the current status of the task.
+func (s *runState) status(taskID string) (TaskStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statuses[taskID]
+	return status, ok
+}
+
+// markStarted moves a pending task to running and returns the context the
+// task must run with. It returns false if the task was canceled before it
+// could start.
+func (s *runState) markStarted(
+	ctx context.Context,
+	stageIdx int,
+	taskID string,
+) (context.Context, context.CancelFunc, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.statuses[taskID] != TaskPending {
+		return nil, nil, false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.statuses[taskID] = TaskRunning
+	s.started[taskID] = time.Now()
+	s.cancels[taskID] = cancel
+
+	s.emit(Event{Type: EventTaskStarted, Stage: stageIdx, TaskID: taskID})
+	return ctx, cancel, true
+}
+
+// markFailed records that the task returned err. It returns false if the
+// task had been canceled while running, in which case err is the result of
+// the cancellation and not a failure.
+func (s *runState) markFailed(stageIdx int, taskID string, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cancels, taskID)
+	if s.statuses[taskID] == TaskCanceled {
+		return false
+	}
+	s.statuses[taskID] = TaskFailed
+	s.finished[taskID] = time.Now()
+	if s.failures == nil {
+		s.failures = make(map[string]error)
+	}
+	s.failures[taskID] = err
+	s.notifyFinished(taskID)
+	s.release(taskID)
+	if s.stream != nil {
+		s.stream <- TaskResult{TaskID: taskID, Err: err}
+	}
+	s.emit(Event{Type: EventTaskFailed, Stage: stageIdx, TaskID: taskID, Err: err})
+	return true
+}
+
+// markSkipped marks every task that never started as skipped.
+func (s *runState) markSkipped() {
+	s.markUnstarted(TaskSkipped, EventTaskSkipped)
+}
+
+// markCanceled marks every task that never started as canceled and returns
+// their sorted IDs.
+func (s *runState) markCanceled() []string {
+	return s.markUnstarted(TaskCanceled, EventTaskCanceled)
+}
+
+// markUnstarted gives every task that never started the status, emitting
+// eventType for each, and returns their sorted IDs.
+func (s *runState) markUnstarted(status TaskStatus, eventType EventType) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marked []string
+	for i, stage := range s.stages {
+		for _, taskID := range stage {
+			if s.statuses[taskID] == TaskPending {
+				s.statuses[taskID] = status
+				s.notifyFinished(taskID)
+				s.release(taskID)
+				s.emit(Event{Type: eventType, Stage: i, TaskID: taskID})
+				marked = append(marked, taskID)
+			}
+		}
+	}
+	slices.Sort(marked)
+	return marked
+}
+
+// markCompleted records that the task finished successfully, feeds its
+// duration to the configured history and reports progress. The progress
+// callbacks run under the state lock so that callers observe a
+// monotonically increasing done count. The output is stored only if the
+// task was not canceled while running.
+func (s *runState) markCompleted(stageIdx int, taskID string, output any, hasOutput bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cancels, taskID)
+	if s.statuses[taskID] == TaskCanceled {
+		return
+	}
+	if hasOutput && s.keep(taskID) {
+		s.result.set(taskID, output)
+	}
+	s.release(taskID)
+	if s.stream != nil {
+		s.stream <- TaskResult{TaskID: taskID, Value: output}
+	}
+
+	now := time.Now()
+	s.statuses[taskID] = TaskSucceeded
+	s.finished[taskID] = now
+	s.notifyFinished(taskID)
+	s.done++
+	s.emit(Event{Type: EventTaskFinished, Stage: stageIdx, TaskID: taskID})
+
+	if recorder, ok := s.cfg.history.(DurationRecorder); ok {
+		recorder.RecordDuration(taskID, now.Sub(s.started[taskID]))
+	}
+	s.reportProgress(now, taskID)
+}
+
+// reportProgress calls the progress callbacks after the task succeeded.
+// Callers must hold s.mu.
+func (s *runState) reportProgress(now time.Time, taskID string) {
+	if s.cfg.progress != nil {
+		s.cfg.progress(s.done, len(s.deps), taskID)
+	}
+	if s.cfg.progressDetail != nil {
+		remaining, estimated := s.estimateRemaining(now)
+		s.cfg.progressDetail(Progress{
+			Done:      s.done,
+			Total:     len(s.deps),
+			LastTask:  taskID,
+			Elapsed:   now.Sub(s.start),
+			Remaining: remaining,
+			Estimated: estimated,
+		})
+	}
+}
+
+// cancelSubtree marks the task and every task that transitively depends on
+// it as canceled, canceling the contexts of those already running. Tasks
+// that already finished, and tasks reading it only optionally, are left
+// untouched.
+func (s *runState) cancelSubtree(taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, ok := s.statuses[taskID]
+	if !ok {
+		return errors.Wrapf(errors.ErrTaskNotFound, "taskID:%s", taskID)
+	}
+	if status.isFinal() {
+		return errors.Wrapf(errors.ErrTaskAlreadyFinished, "task %q is %s", taskID, status)
+	}
+
+	s.cancelTasks([]string{taskID})
+	return nil
+}
+
+// cancelTasks marks the tasks and every task that transitively depends on
+// them, other than through optional inputs, as canceled, canceling the
+// contexts of those already running. Tasks that already finished are left
+// untouched. It returns the IDs of the canceled tasks. Callers must hold
+// s.mu.
+func (s *runState) cancelTasks(queue []string) []string {
+	dependents := s.strictDependents()
+	var canceled []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if s.statuses[id].isFinal() {
+			continue
+		}
+
+		s.statuses[id] = TaskCanceled
+		s.notifyFinished(id)
+		s.release(id)
+		if cancel, running := s.cancels[id]; running {
+			cancel()
+			s.finished[id] = time.Now()
+		}
+		s.emit(Event{Type: EventTaskCanceled, Stage: s.stageOf(id), TaskID: id})
+		canceled = append(canceled, id)
+		queue = append(queue, dependents[id]...)
+	}
+	return canceled
+}
+
+// strictDependents returns the IDs of tasks mapped to the IDs of the tasks
+// depending on them other than through optional inputs.
+func (s *runState) strictDependents() map[string][]string {
+	dependents := make(map[string][]string, len(s.strictDeps))
+	for id, deps := range s.strictDeps {
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+	return dependents
+}
+
+// keep reports whether the output of the task must be stored: always,
+// unless outputs are evicted and the task is neither retained nor read by
+// an unfinished dependent. Callers must hold s.mu.
+func (s *runState) keep(taskID string) bool {
+	if s.consumers == nil {
+		return true
+	}
+	if _, ok := s.cfg.retained[taskID]; ok {
+		return true
+	}
+	return s.consumers[taskID] > 0
+}
+
+// release records that the task finished reading its dependencies, evicting
+// the outputs no other dependent is waiting for. Callers must hold s.mu.
+func (s *runState) release(taskID string) {
+	if s.consumers == nil {
+		return
+	}
+	for _, dep := range distinct(s.deps[taskID]) {
+		s.consumers[dep]--
+		if !s.keep(dep) {
+			s.result.remove(dep)
+		}
+	}
+}
+
+// distinct returns ids without duplicates, keeping the first occurrence.
+func distinct(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// stageOf returns the index of the stage containing the task.
+func (s *runState) stageOf(taskID string) int {
+	for i, stage := range s.stages {
+		if slices.Contains(stage, taskID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// takeRetry reserves one retry from the run's retry budget and reports
+// whether the budget allowed it.
+func (s *runState) takeRetry() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.retryBudget >= 0 && s.retries >= s.cfg.retryBudget {
+		return false
+	}
+	s.retries++
+	return true
+}
+
+// dependencyChain returns the task followed by the longest chain of
+// dependencies that led to it. When several dependencies are equally deep,
+// the one with the smallest ID is followed.
+func (s *runState) dependencyChain(taskID string) []string {
+	chain := []string{taskID}
+	for {
+		var next string
+		deepest := -1
+		for _, dep := range s.deps[taskID] {
+			stage := s.stageOf(dep)
+			if stage > deepest || (stage == deepest && dep < next) {
+				next, deepest = dep, stage
+			}
+		}
+		if deepest < 0 {
+			return chain
+		}
+		chain = append(chain, next)
+		taskID = next
+	}
+}
+
+// pendingTasks returns the sorted IDs of the tasks that neither succeeded
+// nor were canceled.
+func (s *runState) pendingTasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]string, 0)
+	for taskID, status := range s.statuses {
+		if status != TaskSucceeded && status != TaskCanceled {
+			pending = append(pending, taskID)
+		}
+	}
+	slices.Sort(pending)
+	return pending
+}
+
+// estimateRemaining returns the expected remaining duration of the run: the
+// longest chain of unfinished tasks weighted by their historical durations.
+// Running tasks only contribute the part of their expected duration that
+// has not elapsed yet. estimated is false when there is no history or a
+// remaining task has no recorded duration. Callers must hold s.mu.
+func (s *runState) estimateRemaining(now time.Time) (remaining time.Duration, estimated bool) {
+	if s.cfg.history == nil {
+		return 0, false
+	}
+
+	estimated = true
+	finish := make(map[string]time.Duration, len(s.deps)-s.done)
+	for _, stage := range s.stages { // stages are in topological order
+		for _, taskID := range stage {
+			if s.statuses[taskID].isFinal() {
+				continue
+			}
+
+			expected, ok := s.cfg.history.ExpectedDuration(taskID)
+			if !ok {
+				estimated = false
+			}
+			if s.statuses[taskID] == TaskRunning {
+				expected = max(expected-now.Sub(s.started[taskID]), 0)
+			}
+
+			var ready time.Duration
+			for _, dep := range s.deps[taskID] {
+				ready = max(ready, finish[dep])
+			}
+			finish[taskID] = ready + expected
+			remaining = max(remaining, finish[taskID])
+		}
+	}
+	return remaining, estimated
+}
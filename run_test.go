@@ -0,0 +1,309 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func collectEvents(run *Run) []Event {
+	//nolint:prealloc // number of events is unknown.
+	var events []Event
+	for e := range run.Events() {
+		events = append(events, e)
+	}
+	return events
+}
+
+func eventsOfType(events []Event, eventType EventType) []Event {
+	//nolint:prealloc // number of events is unknown.
+	var filtered []Event
+	for _, e := range events {
+		if e.Type == eventType {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func TestRunAsyncWait(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	l := New().
+		Do("task", func(ctx context.Context, name string) (string, error) {
+			<-release
+			return "hello " + name, nil
+		}, UseRun("name"))
+
+	run := l.RunAsync(context.Background(), map[string]any{"name": "lyra"}, WithRunID("async-1"))
+	require.Equal(t, "async-1", run.ID())
+
+	select {
+	case <-run.Done():
+		t.Fatal("run finished before the task was released")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	result, err := run.Wait()
+	require.NoError(t, err)
+	got, err := result.Get("task")
+	require.NoError(t, err)
+	require.Equal(t, "hello lyra", got)
+
+	// Wait is repeatable.
+	again, err := run.Wait()
+	require.NoError(t, err)
+	require.Same(t, result, again)
+}
+
+func TestRunAsyncEvents(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("source", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("left", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("source")).
+		Do("right", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("source"))
+
+	run := l.RunAsync(context.Background(), nil)
+	events := collectEvents(run)
+	_, err := run.Wait()
+	require.NoError(t, err)
+
+	for _, e := range events {
+		require.Equal(t, run.ID(), e.RunID)
+		require.False(t, e.Time.IsZero())
+	}
+
+	require.Equal(t, EventStageStarted, events[0].Type)
+	require.Equal(t, []string{"source"}, events[0].TaskIDs)
+	require.Equal(t, EventStageFinished, events[len(events)-1].Type)
+	require.Equal(t, 1, events[len(events)-1].Stage)
+
+	require.Len(t, eventsOfType(events, EventStageStarted), 2)
+	require.Len(t, eventsOfType(events, EventTaskScheduled), 3)
+	require.Len(t, eventsOfType(events, EventTaskStarted), 3)
+	require.Len(t, eventsOfType(events, EventTaskFinished), 3)
+	require.Empty(t, eventsOfType(events, EventTaskFailed))
+	require.Empty(t, eventsOfType(events, EventTaskSkipped))
+
+	// A late subscriber still sees the full stream.
+	require.Equal(t, events, collectEvents(run))
+}
+
+func TestRunAsyncEventsOnFailure(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fail", func(ctx context.Context) (int, error) {
+			return 0, errTaskFailed
+		}).
+		Do("next", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("fail"))
+
+	run := l.RunAsync(context.Background(), nil)
+	result, err := run.Wait()
+	require.ErrorIs(t, err, errTaskFailed)
+	require.Nil(t, result)
+
+	events := collectEvents(run)
+
+	failed := eventsOfType(events, EventTaskFailed)
+	require.Len(t, failed, 1)
+	require.Equal(t, "fail", failed[0].TaskID)
+	require.ErrorIs(t, failed[0].Err, errTaskFailed)
+
+	finished := eventsOfType(events, EventStageFinished)
+	require.Len(t, finished, 1)
+	require.ErrorIs(t, finished[0].Err, errTaskFailed)
+
+	skipped := eventsOfType(events, EventTaskSkipped)
+	require.Len(t, skipped, 1)
+	require.Equal(t, "next", skipped[0].TaskID)
+	require.Equal(t, 1, skipped[0].Stage)
+}
+
+func TestRunAsyncBuildError(t *testing.T) {
+	t.Parallel()
+
+	run := New().Do("bad", invalidTask).RunAsync(context.Background(), nil)
+
+	result, err := run.Wait()
+	require.Error(t, err)
+	require.Nil(t, result)
+	require.Empty(t, collectEvents(run))
+}
+
+func TestRunCancelTask(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var branchRan bool
+
+	// source -> gate -> branch -> leaf
+	// source -> other
+	l := New().
+		Do("source", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}).
+		Do("gate", func(ctx context.Context, val int) (int, error) {
+			<-release
+			return val, nil
+		}, Use("source")).
+		Do("other", func(ctx context.Context, val int) (int, error) {
+			return val + 1, nil
+		}, Use("source")).
+		Do("branch", func(ctx context.Context, val int) (int, error) {
+			branchRan = true
+			return val, nil
+		}, Use("gate")).
+		Do("leaf", func(ctx context.Context, val int) (int, error) {
+			return val, nil
+		}, Use("branch"))
+
+	run := l.RunAsync(context.Background(), nil)
+
+	require.Eventually(t, func() bool {
+		status, _ := run.TaskStatus("gate")
+		return status == TaskRunning
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, run.CancelTask("branch"))
+	close(release)
+
+	result, err := run.Wait()
+	require.NoError(t, err)
+	require.False(t, branchRan)
+
+	for taskID, expected := range map[string]TaskStatus{
+		"source": TaskSucceeded,
+		"gate":   TaskSucceeded,
+		"other":  TaskSucceeded,
+		"branch": TaskCanceled,
+		"leaf":   TaskCanceled,
+	} {
+		status, ok := run.TaskStatus(taskID)
+		require.True(t, ok)
+		require.Equal(t, expected, status, taskID)
+	}
+
+	other, err := result.Get("other")
+	require.NoError(t, err)
+	require.Equal(t, 2, other)
+	_, err = result.Get("leaf")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+
+	canceled := eventsOfType(collectEvents(run), EventTaskCanceled)
+	require.Len(t, canceled, 2)
+	require.Equal(t, "branch", canceled[0].TaskID)
+	require.Equal(t, 2, canceled[0].Stage)
+	require.Equal(t, "leaf", canceled[1].TaskID)
+
+	require.ErrorIs(t, run.CancelTask("other"), errors.ErrTaskAlreadyFinished)
+	require.ErrorIs(t, run.CancelTask("missing"), errors.ErrTaskNotFound)
+}
+
+func TestRunCancelRunningTask(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("slow", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}).
+		Do("fast", func(ctx context.Context) (int, error) {
+			return 1, nil
+		})
+
+	run := l.RunAsync(context.Background(), nil)
+
+	require.Eventually(t, func() bool {
+		status, _ := run.TaskStatus("slow")
+		return status == TaskRunning
+	}, time.Second, time.Millisecond)
+	require.NoError(t, run.CancelTask("slow"))
+
+	result, err := run.Wait()
+	require.NoError(t, err, "a canceled task does not fail the run")
+	_, err = result.Get("slow")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+	fast, err := result.Get("fast")
+	require.NoError(t, err)
+	require.Equal(t, 1, fast)
+
+	require.Empty(t, eventsOfType(collectEvents(run), EventTaskFailed))
+}
+
+func TestRunCancelTaskAfterBuildError(t *testing.T) {
+	t.Parallel()
+
+	run := New().Do("bad", invalidTask).RunAsync(context.Background(), nil)
+
+	require.ErrorIs(t, run.CancelTask("bad"), errors.ErrTaskNotFound)
+	_, ok := run.TaskStatus("bad")
+	require.False(t, ok)
+}
+
+func TestRunCancel(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (string, error) { return "Alice", nil }).
+		Do("slow", func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}).
+		Do("after", func(ctx context.Context, _ int) error { return nil }, Use("slow"))
+
+	run := l.RunAsync(context.Background(), nil)
+	<-started
+	require.Equal(t, RunRunning, run.Status())
+
+	run.Cancel()
+	result, err := run.Wait()
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, result)
+	require.Equal(t, RunCanceled, run.Status())
+	status, _ := run.TaskStatus("after")
+	require.Equal(t, TaskCanceled, status)
+
+	// Results of completed tasks stay available.
+	user, err := run.Result().Get("fetchUser")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", user)
+
+	run.Cancel() // no effect once finished
+	require.Equal(t, RunCanceled, run.Status())
+}
+
+func TestRunStatus(t *testing.T) {
+	t.Parallel()
+
+	succeeded := New().Do("ok", func(ctx context.Context) error { return nil }).
+		RunAsync(context.Background(), nil)
+	_, err := succeeded.Wait()
+	require.NoError(t, err)
+	require.Equal(t, RunSucceeded, succeeded.Status())
+
+	failed := New().Do("bad", invalidTask).RunAsync(context.Background(), nil)
+	_, err = failed.Wait()
+	require.Error(t, err)
+	require.Equal(t, RunFailed, failed.Status())
+	require.Nil(t, failed.Result())
+	failed.Cancel()
+}
@@ -0,0 +1,50 @@
+package lyra
+
+import (
+	"slices"
+	"time"
+)
+
+// Profiler labels set on the goroutines executing tasks, so goroutine dumps
+// (for example /debug/pprof/goroutine?debug=1) and CPU profiles show which
+// run and task each goroutine belongs to. Goroutines started by a task
+// inherit the labels.
+const (
+	RunLabel  = "lyra_run"
+	TaskLabel = "lyra_task"
+)
+
+// RunningTask describes a task that is executing.
+type RunningTask struct {
+	RunID   string
+	TaskID  string
+	Stage   int
+	Started time.Time
+}
+
+type runningKey struct {
+	runID  string
+	taskID string
+}
+
+// RunningTasks returns the tasks executing in any run of l, longest
+// running first, to find out which tasks are stuck during an incident.
+func (l *Lyra) RunningTasks() []RunningTask {
+	var tasks []RunningTask
+	l.running.Range(func(_, value any) bool {
+		tasks = append(tasks, value.(RunningTask)) //nolint:forcetypeassert // only RunningTask is stored.
+		return true
+	})
+	slices.SortFunc(tasks, func(a, b RunningTask) int {
+		return a.Started.Compare(b.Started)
+	})
+	return tasks
+}
+
+// trackRunning registers the task as running until the returned function
+// is called.
+func (l *Lyra) trackRunning(runID string, stageIdx int, taskID string) func() {
+	key := runningKey{runID: runID, taskID: taskID}
+	l.running.Store(key, RunningTask{RunID: runID, TaskID: taskID, Stage: stageIdx, Started: time.Now()})
+	return func() { l.running.Delete(key) }
+}
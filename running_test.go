@@ -0,0 +1,45 @@
+package lyra
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunningTasks(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var labels map[string]string
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("slow", func(ctx context.Context, _ int) error {
+			labels = make(map[string]string)
+			pprof.ForLabels(ctx, func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+			close(started)
+			<-release
+			return nil
+		}, Use("fetchUser"))
+
+	run := l.RunAsync(context.Background(), nil, WithRunID("run-1"))
+	<-started
+
+	running := l.RunningTasks()
+	require.Len(t, running, 1)
+	require.Equal(t, "run-1", running[0].RunID)
+	require.Equal(t, "slow", running[0].TaskID)
+	require.Equal(t, 1, running[0].Stage)
+
+	close(release)
+	_, err := run.Wait()
+	require.NoError(t, err)
+
+	require.Empty(t, l.RunningTasks())
+	require.Equal(t, map[string]string{RunLabel: "run-1", TaskLabel: "slow"}, labels)
+}
@@ -0,0 +1,38 @@
+package lyra
+
+import (
+	"cmp"
+	"slices"
+)
+
+// prioritizeStages sorts the tasks of every stage in dispatch order: higher
+// priority first, then tasks heading the longest chain of dependents (the
+// critical path), then by task ID.
+func prioritizeStages(stages [][]string, deps map[string][]string, priorities map[string]int) {
+	heights := chainHeights(stages, deps)
+	for _, stage := range stages {
+		slices.SortFunc(stage, func(a, b string) int {
+			return cmp.Or(
+				cmp.Compare(priorities[b], priorities[a]),
+				cmp.Compare(heights[b], heights[a]),
+				cmp.Compare(a, b),
+			)
+		})
+	}
+}
+
+// chainHeights returns, for every task, the number of tasks on the longest
+// chain of dependents that starts at it.
+func chainHeights(stages [][]string, deps map[string][]string) map[string]int {
+	heights := make(map[string]int, len(deps))
+	for i := len(stages) - 1; i >= 0; i-- {
+		for _, taskID := range stages[i] {
+			height := heights[taskID] + 1
+			heights[taskID] = height
+			for _, dep := range deps[taskID] {
+				heights[dep] = max(heights[dep], height)
+			}
+		}
+	}
+	return heights
+}
@@ -0,0 +1,138 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrioritizeStages(t *testing.T) {
+	t.Parallel()
+
+	// a -> x -> y
+	// b
+	// c (priority 5)
+	// d -> z
+	deps := map[string][]string{
+		"a": {},
+		"b": {},
+		"c": {},
+		"d": {},
+		"x": {"a"},
+		"y": {"x"},
+		"z": {"d"},
+	}
+	stages := [][]string{{"d", "c", "b", "a"}, {"z", "x"}, {"y"}}
+
+	prioritizeStages(stages, deps, map[string]int{"c": 5})
+
+	require.Equal(t, [][]string{{"c", "a", "d", "b"}, {"x", "z"}, {"y"}}, stages)
+}
+
+func TestChainHeights(t *testing.T) {
+	t.Parallel()
+
+	deps := map[string][]string{
+		"a": {},
+		"b": {"a"},
+		"c": {"a"},
+		"d": {"b"},
+	}
+	stages := [][]string{{"a"}, {"b", "c"}, {"d"}}
+
+	require.Equal(t, map[string]int{"a": 3, "b": 2, "c": 1, "d": 1}, chainHeights(stages, deps))
+}
+
+func TestRunWithConcurrencyAndPriority(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		order   []string
+		running int
+		peak    int
+	)
+	record := func(taskID string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, taskID)
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	l := New().
+		Do("low", record("low"), WithPriority(-1)).
+		Do("default", record("default")).
+		Do("high", record("high"), WithPriority(10)).
+		Do("higher", record("higher"), WithPriority(20))
+
+	_, err := l.Run(context.Background(), nil, WithConcurrency(1))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"higher", "high", "default", "low"}, order)
+	require.Equal(t, 1, peak)
+}
+
+func TestRunWithStageWorkers(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name         string
+		opts         []RunOption
+		expectedPeak int
+	}{
+		{
+			name:         "stage workers",
+			opts:         []RunOption{WithStageWorkers(4)},
+			expectedPeak: 4,
+		},
+		{
+			name:         "concurrency below stage workers",
+			opts:         []RunOption{WithStageWorkers(4), WithConcurrency(2)},
+			expectedPeak: 2,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu      sync.Mutex
+				running int
+				peak    int
+			)
+			l := New()
+			for i := range 200 {
+				l.Do(fmt.Sprintf("task%d", i), func(ctx context.Context) (int, error) {
+					mu.Lock()
+					running++
+					peak = max(peak, running)
+					mu.Unlock()
+					time.Sleep(time.Millisecond)
+					mu.Lock()
+					running--
+					mu.Unlock()
+					return i, nil
+				})
+			}
+
+			result, err := l.Run(context.Background(), nil, tc.opts...)
+
+			require.NoError(t, err)
+			require.Len(t, result.Redacted(), 200)
+			require.LessOrEqual(t, peak, tc.expectedPeak)
+		})
+	}
+}
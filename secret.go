@@ -0,0 +1,62 @@
+package lyra
+
+import (
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// Redacted replaces sensitive values, and their types, wherever Lyra would
+// otherwise expose them.
+const Redacted = "[REDACTED]"
+
+// Secret marks the value bound by spec as sensitive.
+//
+// A key is sensitive for the whole run once any spec marks it, so errors
+// raised by other tasks that read the same key are masked as well. Lyra then
+// never includes the value or its type in error messages and masks it in
+// Result.Redacted.
+//
+// Example:
+//
+//	l.Do("login", login, lyra.Secret(lyra.UseRun("password")))
+func Secret(spec internal.InputSpec) internal.InputSpec {
+	spec.Secret = true
+	return spec
+}
+
+// WithSecretOutput marks the result of the task as sensitive, with the same
+// effect as wrapping every spec that reads it in Secret.
+func WithSecretOutput() TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.SecretOutput = true
+	})
+}
+
+// secretKeys returns the task IDs and runtime input keys marked as sensitive.
+func (l *Lyra) secretKeys() map[string]struct{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	secrets := make(map[string]struct{})
+	for taskID, task := range l.tasks {
+		if task.GetOptions().SecretOutput {
+			secrets[taskID] = struct{}{}
+		}
+		specs, _ := task.GetInputParams()
+		for _, spec := range specs {
+			if spec.Secret {
+				secrets[spec.Source] = struct{}{}
+			}
+		}
+	}
+	return secrets
+}
+
+// typeName describes typ for error messages unless it belongs to a secret.
+func typeName(typ reflect.Type, secret bool) string {
+	if secret {
+		return Redacted
+	}
+	return typ.String()
+}
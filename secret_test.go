@@ -0,0 +1,97 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestSecretMasksTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		lyra     *Lyra
+		contains string
+		masked   bool
+	}{
+		{
+			name: "plain run input",
+			lyra: New().Do("login", func(ctx context.Context, password string) error {
+				return nil
+			}, UseRun("password")),
+			contains: "got int",
+		},
+		{
+			name: "secret run input",
+			lyra: New().Do("login", func(ctx context.Context, password string) error {
+				return nil
+			}, Secret(UseRun("password"))),
+			contains: "got " + Redacted,
+			masked:   true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.lyra.Run(context.Background(), map[string]any{"password": 1234})
+
+			require.ErrorIs(t, err, errors.ErrInvalidParamType)
+			require.Contains(t, err.Error(), tc.contains)
+			if tc.masked {
+				require.NotContains(t, err.Error(), "got int")
+			}
+		})
+	}
+}
+
+func TestSecretOutputMasksDependents(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("token", func(ctx context.Context) (int, error) {
+			return 1234, nil
+		}, WithSecretOutput()).
+		Do("call", func(ctx context.Context, token string) error {
+			return nil
+		}, Use("token"))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.ErrorIs(t, err, errors.ErrInvalidParamType)
+	require.Contains(t, err.Error(), "got "+Redacted)
+}
+
+func TestResultRedacted(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("token", func(ctx context.Context, password string) (string, error) {
+			return "tok-" + password, nil
+		}, Secret(UseRun("password"))).
+		Do("user", func(ctx context.Context, name string) (string, error) {
+			return name, nil
+		}, UseRun("name"), WithSecretOutput())
+
+	result, err := l.Run(context.Background(), map[string]any{"password": "hunter2", "name": "Alice"})
+
+	require.NoError(t, err)
+	require.True(t, result.IsSecret("password"))
+	require.True(t, result.IsSecret("user"))
+	require.False(t, result.IsSecret("token"))
+	require.Equal(t, map[string]any{
+		"password": Redacted,
+		"name":     "Alice",
+		"token":    "tok-hunter2",
+		"user":     Redacted,
+	}, result.Redacted())
+
+	password, err := result.Get("password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", password)
+}
@@ -0,0 +1,202 @@
+package lyra
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// WithSeed starts the run with outputs of an earlier run, so multi-phase
+// workflows can reuse the expensive outputs of a previous phase: a task
+// whose output is seeded does not execute, and its dependents read the
+// seeded value. Tasks the DAG depends on still execute normally.
+//
+// Only the outputs of the listed tasks are seeded, or every output of prev
+// when no task ID is given. Values of prev that are not the output of a
+// task of the DAG, such as the runtime inputs of the earlier run, and tasks
+// missing from prev are ignored. A seeded value that does not fit the
+// output type of its task fails the run before any task executes.
+//
+// Seeded tasks are reported as succeeded, with an EventTaskSeeded event
+// instead of EventTaskStarted and EventTaskFinished.
+//
+// Example:
+//
+//	phase1, err := extract.Run(ctx, inputs)
+//	...
+//	phase2, err := transform.Run(ctx, inputs, lyra.WithSeed(phase1, "fetchUsers", "fetchOrders"))
+func WithSeed(prev *Result, taskIDs ...string) RunOption {
+	return func(cfg *runConfig) {
+		if prev == nil {
+			return
+		}
+		if cfg.seeds == nil {
+			cfg.seeds = make(map[string]any)
+		}
+		if len(taskIDs) == 0 {
+			for taskID, output := range prev.values() {
+				cfg.seeds[taskID] = output
+			}
+			return
+		}
+		for _, taskID := range taskIDs {
+			if output, ok := prev.load(taskID); ok {
+				cfg.seeds[taskID] = output
+			}
+		}
+	}
+}
+
+// WithPrecomputed starts the run with outputs computed outside of it, for
+// example cache hits: the tasks in outputs do not execute, and their
+// dependents read the given values. A task returning only an error can be
+// marked as done with a nil value. Tasks the DAG depends on still execute
+// normally.
+//
+// Unlike WithSeed, every key of outputs must be a task of the DAG and every
+// value must fit the output type of its task, or the run fails before any
+// task executes. Precomputed values take precedence over seeded ones.
+//
+// Example:
+//
+//	opts := []lyra.RunOption{}
+//	if user, ok := cache.Get(userID); ok {
+//		opts = append(opts, lyra.WithPrecomputed(map[string]any{"fetchUser": user}))
+//	}
+//	results, err := l.Run(ctx, inputs, opts...)
+func WithPrecomputed(outputs map[string]any) RunOption {
+	return func(cfg *runConfig) {
+		if cfg.precomputed == nil {
+			cfg.precomputed = make(map[string]any, len(outputs))
+		}
+		for taskID, output := range outputs {
+			cfg.precomputed[taskID] = output
+		}
+	}
+}
+
+// WithForce executes the listed tasks even when their outputs are seeded
+// or precomputed, for example to refresh stale values on operator request.
+// Every task ID must be a task of the DAG, or the run fails before any task
+// executes.
+//
+// Example:
+//
+//	results, err := l.Run(ctx, inputs,
+//		lyra.WithPrecomputed(cached),
+//		lyra.WithForce("fetchPrices"))
+func WithForce(taskIDs ...string) RunOption {
+	return func(cfg *runConfig) {
+		if cfg.forced == nil {
+			cfg.forced = make(map[string]struct{}, len(taskIDs))
+		}
+		for _, taskID := range taskIDs {
+			cfg.forced[taskID] = struct{}{}
+		}
+	}
+}
+
+// skippedOutputs returns the outputs of the tasks that do not execute in
+// the run configured by cfg.
+func skippedOutputs(cfg *runConfig, tasks map[string]*compiledTask) (map[string]any, error) {
+	for taskID := range cfg.forced {
+		if _, ok := tasks[taskID]; !ok {
+			return nil, errors.Wrapf(errors.ErrTaskNotFound, "forced task %q", taskID)
+		}
+	}
+	outputs, err := seededOutputs(cfg.seeds, cfg.forced, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for taskID, output := range cfg.precomputed {
+		task, ok := tasks[taskID]
+		if !ok {
+			return nil, errors.Wrapf(errors.ErrTaskNotFound, "precomputed output of task %q", taskID)
+		}
+		if task.GetOutputParams() == nil {
+			if output != nil {
+				return nil, errors.Wrapf(
+					errors.ErrInvalidParamType,
+					"precomputed output of task %q: the task returns only an error",
+					taskID,
+				)
+			}
+		} else if output, err = typedOutput(output, task.GetOutputParams()); err != nil {
+			return nil, errors.Wrapf(err, "precomputed output of task %q", taskID)
+		}
+		outputs[taskID] = output
+	}
+	for taskID := range cfg.forced {
+		delete(outputs, taskID)
+	}
+	return outputs, nil
+}
+
+// seededOutputs returns the seeds that are outputs of tasks not forced to
+// execute, checking that they fit the output types of the tasks.
+func seededOutputs(
+	seeds map[string]any,
+	forced map[string]struct{},
+	tasks map[string]*compiledTask,
+) (map[string]any, error) {
+	seeded := make(map[string]any, len(seeds))
+	for taskID, output := range seeds {
+		task, ok := tasks[taskID]
+		if !ok || task.GetOutputParams() == nil {
+			continue
+		}
+		if _, ok := forced[taskID]; ok {
+			continue // not checked, it is recomputed
+		}
+		output, err := typedOutput(output, task.GetOutputParams())
+		if err != nil {
+			return nil, errors.Wrapf(err, "seeded output of task %q", taskID)
+		}
+		seeded[taskID] = output
+	}
+	return seeded, nil
+}
+
+// typedOutput checks that output can be returned as a value of typ, and
+// returns it with an untyped nil replaced by the nil value of typ, so
+// stored outputs always keep their type.
+func typedOutput(output any, typ reflect.Type) (any, error) {
+	if output == nil {
+		if zero, ok := nilValue(typ); ok {
+			return zero.Interface(), nil
+		}
+		return nil, errors.Wrapf(errors.ErrInvalidParamType, "got untyped nil for %s", typ)
+	}
+	if !reflect.TypeOf(output).AssignableTo(typ) {
+		return nil, errors.Wrapf(errors.ErrInvalidParamType, "%T is not assignable to %s", output, typ)
+	}
+	return output, nil
+}
+
+// markSeeded records that a pending task succeeded with its seeded or
+// precomputed output without executing.
+func (s *runState) markSeeded(stageIdx int, taskID string, output any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.statuses[taskID] != TaskPending {
+		return // canceled before it could start
+	}
+	if s.tasks[taskID].GetOutputParams() != nil && s.keep(taskID) {
+		s.result.set(taskID, output)
+	}
+	s.release(taskID)
+	if s.stream != nil {
+		s.stream <- TaskResult{TaskID: taskID, Value: output}
+	}
+
+	now := time.Now()
+	s.statuses[taskID] = TaskSucceeded
+	s.started[taskID] = now
+	s.finished[taskID] = now
+	s.notifyFinished(taskID)
+	s.done++
+	s.emit(Event{Type: EventTaskSeeded, Stage: stageIdx, TaskID: taskID})
+	s.reportProgress(now, taskID)
+}
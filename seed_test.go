@@ -0,0 +1,259 @@
+package lyra
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestWithSeed(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	l := New().
+		Do("fetch", func(ctx context.Context, n int) (int, error) {
+			fetches.Add(1)
+			return n * 10, nil
+		}, UseRun("n")).
+		Do("double", func(ctx context.Context, v int) (int, error) {
+			return v * 2, nil
+		}, Use("fetch"))
+
+	phase1, err := l.Run(context.Background(), map[string]any{"n": 1})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), fetches.Load())
+
+	run := l.RunAsync(context.Background(), map[string]any{"n": 2}, WithSeed(phase1, "fetch"))
+	var seeded []string
+	for e := range run.Events() {
+		if e.Type == EventTaskStarted {
+			require.NotEqual(t, "fetch", e.TaskID)
+		}
+		if e.Type == EventTaskSeeded {
+			seeded = append(seeded, e.TaskID)
+		}
+	}
+	phase2, err := run.Wait()
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), fetches.Load())
+	require.Equal(t, []string{"fetch"}, seeded)
+	status, _ := run.TaskStatus("fetch")
+	require.Equal(t, TaskSucceeded, status)
+	double, err := phase2.Get("double")
+	require.NoError(t, err)
+	require.Equal(t, 20, double)
+}
+
+func TestWithSeedSelection(t *testing.T) {
+	t.Parallel()
+
+	prev := NewResult()
+	prev.set("a", 1)
+	prev.set("b", 2)
+	prev.set("input", "ignored")
+
+	tcs := []struct {
+		name    string
+		taskIDs []string
+		want    map[string]int
+	}{
+		{name: "every output", want: map[string]int{"a": 1, "b": 2}},
+		{name: "listed outputs", taskIDs: []string{"b"}, want: map[string]int{"a": 0, "b": 2}},
+		{name: "missing outputs", taskIDs: []string{"missing"}, want: map[string]int{"a": 0, "b": 0}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().
+				Do("a", func(ctx context.Context) (int, error) { return 0, nil }).
+				Do("b", func(ctx context.Context) (int, error) { return 0, nil })
+
+			result, err := l.Run(context.Background(), nil, WithSeed(prev, tc.taskIDs...))
+			require.NoError(t, err)
+			for taskID, want := range tc.want {
+				got, err := result.Get(taskID)
+				require.NoError(t, err)
+				assert.Equal(t, want, got, taskID)
+			}
+		})
+	}
+}
+
+func TestWithSeedTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	prev := NewResult()
+	prev.set("count", "ten")
+	l := New().Do("count", func(ctx context.Context) (int, error) { return 10, nil })
+
+	_, err := l.Run(context.Background(), nil, WithSeed(prev))
+	require.ErrorIs(t, err, errors.ErrInvalidParamType)
+}
+
+func TestWithPrecomputed(t *testing.T) {
+	t.Parallel()
+
+	var executed atomic.Int32
+	l := New().
+		Do("fetchUser", func(ctx context.Context) (User, error) {
+			executed.Add(1)
+			return User{Name: "fetched"}, nil
+		}).
+		Do("warmup", func(ctx context.Context) error {
+			executed.Add(1)
+			return nil
+		}).
+		Do("greet", func(ctx context.Context, name string) (string, error) {
+			return "hello " + name, nil
+		}, Use("fetchUser", "Name"))
+
+	result, err := l.Run(context.Background(), nil, WithPrecomputed(map[string]any{
+		"fetchUser": User{Name: "cached"},
+		"warmup":    nil,
+	}))
+	require.NoError(t, err)
+
+	require.Zero(t, executed.Load())
+	greeting, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hello cached", greeting)
+	_, err = result.Get("warmup")
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestWithPrecomputedInvalid(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		outputs map[string]any
+		wantErr error
+	}{
+		{name: "unknown task", outputs: map[string]any{"missing": 1}, wantErr: errors.ErrTaskNotFound},
+		{name: "wrong type", outputs: map[string]any{"count": "ten"}, wantErr: errors.ErrInvalidParamType},
+		{name: "untyped nil", outputs: map[string]any{"count": nil}, wantErr: errors.ErrInvalidParamType},
+		{name: "error-only task", outputs: map[string]any{"notify": 1}, wantErr: errors.ErrInvalidParamType},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().
+				Do("count", func(ctx context.Context) (int, error) { return 10, nil }).
+				Do("notify", func(ctx context.Context) error { return nil })
+
+			_, err := l.Run(context.Background(), nil, WithPrecomputed(tc.outputs))
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestWithPrecomputedOverridesSeed(t *testing.T) {
+	t.Parallel()
+
+	prev := NewResult()
+	prev.set("count", 1)
+	l := New().Do("count", func(ctx context.Context) (int, error) { return 0, nil })
+
+	result, err := l.Run(context.Background(), nil,
+		WithPrecomputed(map[string]any{"count": 2}),
+		WithSeed(prev))
+	require.NoError(t, err)
+
+	count, err := result.Get("count")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestWithForce(t *testing.T) {
+	t.Parallel()
+
+	prev := NewResult()
+	prev.set("a", 1)
+	prev.set("b", "stale")
+
+	var executed atomic.Int32
+	l := New().
+		Do("a", func(ctx context.Context) (int, error) {
+			executed.Add(1)
+			return 10, nil
+		}).
+		Do("b", func(ctx context.Context) (int, error) {
+			executed.Add(1)
+			return 20, nil
+		})
+
+	result, err := l.Run(context.Background(), nil,
+		WithSeed(prev),
+		WithPrecomputed(map[string]any{"a": 2}),
+		WithForce("a", "b"))
+	require.NoError(t, err)
+
+	require.Equal(t, int32(2), executed.Load())
+	for taskID, want := range map[string]int{"a": 10, "b": 20} {
+		got, err := result.Get(taskID)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
+
+func TestWithForceUnknownTask(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("a", func(ctx context.Context) (int, error) { return 1, nil })
+
+	_, err := l.Run(context.Background(), nil, WithForce("missing"))
+	require.ErrorIs(t, err, errors.ErrTaskNotFound)
+}
+
+func TestSkippedNilOutput(t *testing.T) {
+	t.Parallel()
+
+	newLyra := func() *Lyra {
+		return New().
+			Do("fetchUser", func(ctx context.Context) (*User, error) {
+				return &User{Name: "fetched"}, nil
+			}).
+			Do("greet", func(ctx context.Context, user *User) (string, error) {
+				if user == nil {
+					return "hi guest", nil
+				}
+				return "hi " + user.Name, nil
+			}, Use("fetchUser"))
+	}
+	prev := NewResult()
+	prev.set("fetchUser", nil)
+
+	tcs := []struct {
+		name string
+		opt  RunOption
+	}{
+		{name: "seed", opt: WithSeed(prev)},
+		{name: "precomputed", opt: WithPrecomputed(map[string]any{"fetchUser": nil})},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := newLyra().Run(context.Background(), nil, tc.opt)
+			require.NoError(t, err)
+
+			greeting, err := result.Get("greet")
+			require.NoError(t, err)
+			require.Equal(t, "hi guest", greeting)
+			user, err := result.Get("fetchUser")
+			require.NoError(t, err)
+			require.Equal(t, (*User)(nil), user)
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package service exposes a DAG as a lightweight HTTP workflow service:
+// callers submit runtime inputs, receive a run ID, poll the run's status
+// and fetch its results.
+//
+// Endpoints:
+//
+//	POST /runs              start a run; the body holds the runtime inputs
+//	GET  /runs/{id}         status of the run and of each of its tasks
+//	GET  /runs/{id}/result  results of a finished run, with secrets redacted
+//
+// Example:
+//
+//	svc := service.New(l, service.WithDecoder(func(r io.Reader) (map[string]any, error) {
+//		var req ReportRequest
+//		if err := json.NewDecoder(r).Decode(&req); err != nil {
+//			return nil, err
+//		}
+//		return lyra.StructInputs(req)
+//	}))
+//	http.Handle("/reports/", http.StripPrefix("/reports", svc))
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// defaultMaxRuns is the number of runs a Service keeps by default.
+const defaultMaxRuns = 100
+
+// defaultMaxBodyBytes is the largest request body a Service reads by
+// default.
+const defaultMaxBodyBytes = 1 << 20
+
+// Decoder reads the runtime inputs of a run from a request body.
+type Decoder func(body io.Reader) (map[string]any, error)
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithDecoder sets how request bodies are turned into runtime inputs. The
+// default decodes a JSON object, so numbers arrive as float64; decode into
+// a typed struct and use lyra.StructInputs to bind exact types.
+func WithDecoder(decode Decoder) Option {
+	return func(s *Service) {
+		s.decode = decode
+	}
+}
+
+// WithMaxRuns sets how many runs are kept for polling. Once exceeded, the
+// oldest run is forgotten, whether it finished or not. The default is 100.
+func WithMaxRuns(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxRuns = n
+		}
+	}
+}
+
+// WithMaxBodyBytes sets the largest request body read when starting a
+// run; larger bodies are rejected with 413 Request Entity Too Large. The
+// default is 1 MiB.
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.maxBodyBytes = n
+		}
+	}
+}
+
+// WithBaseContext sets the context runs are started with, since runs
+// outlive the request that started them. Canceling it cancels every run.
+// The default is context.Background().
+func WithBaseContext(ctx context.Context) Option {
+	return func(s *Service) {
+		s.ctx = ctx
+	}
+}
+
+// WithRunOptions sets the options every run is started with. Runs are
+// identified by IDs the service generates, overriding lyra.WithRunID.
+func WithRunOptions(opts ...lyra.RunOption) Option {
+	return func(s *Service) {
+		s.runOpts = opts
+	}
+}
+
+// Service is an http.Handler executing runs of one DAG.
+type Service struct {
+	lyra    *lyra.Lyra
+	decode  Decoder
+	maxRuns int
+	ctx     context.Context //nolint:containedctx // runs outlive requests.
+	runOpts []lyra.RunOption
+	mux     *http.ServeMux
+	// taskIDs lists the tasks of the DAG, or graphErr why they are unknown.
+	taskIDs  []string
+	graphErr error
+	// maxBodyBytes bounds the request bodies of started runs.
+	maxBodyBytes int64
+
+	mu    sync.Mutex
+	runs  map[string]*lyra.Run
+	order []string
+}
+
+// New returns a Service executing runs of l, whose tasks must all be
+// added before.
+func New(l *lyra.Lyra, opts ...Option) *Service {
+	s := &Service{
+		lyra:         l,
+		decode:       decodeJSON,
+		maxRuns:      defaultMaxRuns,
+		ctx:          context.Background(),
+		maxBodyBytes: defaultMaxBodyBytes,
+		mux:          http.NewServeMux(),
+		runs:         make(map[string]*lyra.Run),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if graph, err := l.Graph(); err != nil {
+		s.graphErr = err
+	} else {
+		for _, node := range graph.Nodes {
+			s.taskIDs = append(s.taskIDs, node.ID)
+		}
+	}
+	s.mux.HandleFunc("POST /runs", s.start)
+	s.mux.HandleFunc("GET /runs/{id}", s.status)
+	s.mux.HandleFunc("GET /runs/{id}/result", s.result)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type startResponse struct {
+	ID string `json:"id"`
+}
+
+type statusResponse struct {
+	ID    string            `json:"id"`
+	State string            `json:"state"`
+	Error string            `json:"error,omitempty"`
+	Tasks map[string]string `json:"tasks"`
+}
+
+// Run states reported by the status endpoint.
+const (
+	stateRunning   = "running"
+	stateSucceeded = "succeeded"
+	stateFailed    = "failed"
+)
+
+func (s *Service) start(w http.ResponseWriter, r *http.Request) {
+	inputs, err := s.decode(http.MaxBytesReader(w, r.Body, s.maxBodyBytes))
+	if err != nil {
+		code := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	// Runs rejected before starting, for example for missing inputs, are
+	// stored too and report their error when polled.
+	opts := append(slices.Clip(s.runOpts), lyra.WithRunID(lyra.NewRunID()))
+	run := s.lyra.RunAsync(s.ctx, inputs, opts...)
+	s.store(run)
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, startResponse{ID: run.ID()})
+}
+
+func (s *Service) status(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if s.graphErr != nil {
+		http.Error(w, s.graphErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := statusResponse{ID: run.ID(), State: stateRunning, Tasks: make(map[string]string, len(s.taskIDs))}
+	for _, taskID := range s.taskIDs {
+		if status, ok := run.TaskStatus(taskID); ok {
+			resp.Tasks[taskID] = status.String()
+		}
+	}
+	select {
+	case <-run.Done():
+		resp.State = stateSucceeded
+		if _, err := run.Wait(); err != nil {
+			resp.State = stateFailed
+			resp.Error = err.Error()
+		}
+	default:
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Service) result(w http.ResponseWriter, r *http.Request) {
+	run, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	select {
+	case <-run.Done():
+	default:
+		http.Error(w, "run has not finished", http.StatusConflict)
+		return
+	}
+	result, err := run.Wait()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, result.Redacted())
+}
+
+func (s *Service) store(run *lyra.Run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.order) == s.maxRuns {
+		delete(s.runs, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.runs[run.ID()] = run
+	s.order = append(s.order, run.ID())
+}
+
+func (s *Service) lookup(w http.ResponseWriter, r *http.Request) (*lyra.Run, bool) {
+	s.mu.Lock()
+	run, ok := s.runs[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "run not found", http.StatusNotFound)
+	}
+	return run, ok
+}
+
+func decodeJSON(body io.Reader) (map[string]any, error) {
+	var inputs map[string]any
+	if err := json.NewDecoder(body).Decode(&inputs); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err //nolint:wrapcheck // reported to the caller as is.
+	}
+	return inputs, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
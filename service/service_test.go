@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func startRun(t *testing.T, h http.Handler, body string) string {
+	t.Helper()
+	rec := do(t, h, http.MethodPost, "/runs", body)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var resp startResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.ID)
+	return resp.ID
+}
+
+func waitStatus(t *testing.T, h http.Handler, id string) statusResponse {
+	t.Helper()
+	var resp statusResponse
+	require.Eventually(t, func() bool {
+		rec := do(t, h, http.MethodGet, "/runs/"+id, "")
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &resp) != nil {
+			return false
+		}
+		return resp.State != stateRunning
+	}, time.Second, time.Millisecond)
+	return resp
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	l := lyra.New().
+		Do("greet", func(ctx context.Context, name string) (string, error) {
+			<-release
+			return "hi " + name, nil
+		}, lyra.UseRun("name")).
+		Do("token", func(ctx context.Context) (string, error) {
+			return "secret", nil
+		}, lyra.WithSecretOutput())
+	svc := New(l)
+
+	id := startRun(t, svc, `{"name": "Alice"}`)
+
+	rec := do(t, svc, http.MethodGet, "/runs/"+id+"/result", "")
+	require.Equal(t, http.StatusConflict, rec.Code, "result of a running run")
+
+	close(release)
+	status := waitStatus(t, svc, id)
+	require.Equal(t, stateSucceeded, status.State)
+	require.Equal(t, map[string]string{"greet": "succeeded", "token": "succeeded"}, status.Tasks)
+
+	rec = do(t, svc, http.MethodGet, "/runs/"+id+"/result", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	require.Equal(t, map[string]any{"name": "Alice", "greet": "hi Alice", "token": lyra.Redacted}, result)
+}
+
+func TestServiceFailedRun(t *testing.T) {
+	t.Parallel()
+
+	l := lyra.New().Do("double", func(ctx context.Context, n int) (int, error) {
+		return n * 2, nil
+	}, lyra.UseRun("n"))
+	svc := New(l)
+
+	// JSON numbers decode to float64 by default.
+	id := startRun(t, svc, `{"n": 2}`)
+	status := waitStatus(t, svc, id)
+	require.Equal(t, stateFailed, status.State)
+	require.Contains(t, status.Error, "expected type int, got float64")
+
+	rec := do(t, svc, http.MethodGet, "/runs/"+id+"/result", "")
+	require.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestServiceWithDecoder(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		N int `lyra:"n"`
+	}
+	l := lyra.New().Do("double", func(ctx context.Context, n int) (int, error) {
+		return n * 2, nil
+	}, lyra.UseRun("n"))
+	svc := New(l, WithDecoder(func(body io.Reader) (map[string]any, error) {
+		var req request
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, err
+		}
+		return lyra.StructInputs(req)
+	}))
+
+	id := startRun(t, svc, `{"N": 2}`)
+	require.Equal(t, stateSucceeded, waitStatus(t, svc, id).State)
+
+	rec := do(t, svc, http.MethodPost, "/runs", `not json`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServiceUnknownRun(t *testing.T) {
+	t.Parallel()
+
+	svc := New(lyra.New())
+
+	require.Equal(t, http.StatusNotFound, do(t, svc, http.MethodGet, "/runs/missing", "").Code)
+	require.Equal(t, http.StatusNotFound, do(t, svc, http.MethodGet, "/runs/missing/result", "").Code)
+}
+
+func TestServiceMaxRuns(t *testing.T) {
+	t.Parallel()
+
+	svc := New(lyra.New(), WithMaxRuns(2))
+
+	first := startRun(t, svc, `{}`)
+	second := startRun(t, svc, `{}`)
+	third := startRun(t, svc, `{}`)
+
+	require.Equal(t, http.StatusNotFound, do(t, svc, http.MethodGet, "/runs/"+first, "").Code)
+	require.Equal(t, http.StatusOK, do(t, svc, http.MethodGet, "/runs/"+second, "").Code)
+	require.Equal(t, http.StatusOK, do(t, svc, http.MethodGet, "/runs/"+third, "").Code)
+}
+
+func TestServiceWithRunIDOption(t *testing.T) {
+	t.Parallel()
+
+	svc := New(lyra.New(), WithMaxRuns(2), WithRunOptions(lyra.WithRunID("fixed")))
+
+	first := startRun(t, svc, `{}`)
+	second := startRun(t, svc, `{}`)
+	third := startRun(t, svc, `{}`)
+
+	require.NotEqual(t, "fixed", first)
+	require.NotEqual(t, first, second)
+	require.Equal(t, http.StatusNotFound, do(t, svc, http.MethodGet, "/runs/"+first, "").Code)
+	require.Equal(t, http.StatusOK, do(t, svc, http.MethodGet, "/runs/"+second, "").Code)
+	require.Equal(t, http.StatusOK, do(t, svc, http.MethodGet, "/runs/"+third, "").Code)
+}
+
+func TestServiceWithMaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	svc := New(lyra.New(), WithMaxBodyBytes(16))
+
+	rec := do(t, svc, http.MethodPost, "/runs", `{"name": "a much longer name"}`)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	startRun(t, svc, `{"name": "ann"}`)
+}
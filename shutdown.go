@@ -0,0 +1,78 @@
+package lyra
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// errShutdownCanceled is the cause of the runs canceled by Shutdown; it
+// matches both errors.ErrShutdown and context.Canceled.
+var errShutdownCanceled = fmt.Errorf("%w: %w", errors.ErrShutdown, context.Canceled)
+
+// Shutdown stops l from starting new runs, which fail with
+// errors.ErrShutdown, and waits for the runs in flight to finish, for
+// example to roll out a service driving background pipelines cleanly.
+//
+// If ctx is done first, the remaining runs are canceled and Shutdown returns
+// the cause of ctx without waiting for them to return. Calling Shutdown
+// more than once is allowed.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := l.Shutdown(ctx); err != nil {
+//		log.Printf("runs canceled: %v", err)
+//	}
+func (l *Lyra) Shutdown(ctx context.Context) error {
+	l.runsMu.Lock()
+	l.shutdown = true
+	l.runsMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		l.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.runsMu.Lock()
+	for _, cancel := range l.runCancels {
+		cancel(errShutdownCanceled)
+	}
+	l.runsMu.Unlock()
+	return context.Cause(ctx)
+}
+
+// admit registers a run starting with ctx and returns the context it must
+// run with and the function to call once it finished. It fails once l is
+// shut down.
+func (l *Lyra) admit(ctx context.Context, state *runState) (context.Context, func(), error) {
+	l.runsMu.Lock()
+	defer l.runsMu.Unlock()
+
+	if l.shutdown {
+		return nil, nil, errors.Wrapf(errors.ErrShutdown, "run %s not started", state.cfg.runID)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	if l.runCancels == nil {
+		l.runCancels = make(map[*runState]context.CancelCauseFunc)
+	}
+	l.runCancels[state] = cancel
+	l.inflight.Add(1)
+
+	return ctx, func() {
+		l.runsMu.Lock()
+		delete(l.runCancels, state)
+		l.runsMu.Unlock()
+		cancel(nil)
+		l.inflight.Done()
+	}, nil
+}
@@ -0,0 +1,72 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestShutdownDrainsRuns(t *testing.T) {
+	t.Parallel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	l := New().Do("task", func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+
+	run := l.RunAsync(context.Background(), nil)
+	<-started
+
+	shutdown := make(chan error)
+	go func() { shutdown <- l.Shutdown(context.Background()) }()
+	require.Eventually(t, func() bool {
+		l.runsMu.Lock()
+		defer l.runsMu.Unlock()
+		return l.shutdown
+	}, time.Second, time.Millisecond)
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrShutdown)
+
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned with a run in flight")
+	default:
+	}
+	close(release)
+	require.NoError(t, <-shutdown)
+
+	result, err := run.Wait()
+	require.NoError(t, err)
+	value, err := result.Get("task")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+	require.NoError(t, l.Shutdown(context.Background()))
+}
+
+func TestShutdownCancelsRemainingRuns(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	l := New().Do("stuck", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	run := l.RunAsync(context.Background(), nil)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.Shutdown(ctx), context.DeadlineExceeded)
+
+	_, err := run.Wait()
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, RunCanceled, run.Status())
+}
@@ -0,0 +1,150 @@
+package lyra
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Signal is an external event a DAG can wait for, for example a human
+// approval or another system becoming ready. Code outside the DAG fires or
+// fails it; its Task completes once that happens.
+//
+// Example:
+//
+//	approval := lyra.NewSignal[string]()
+//	l.Do("approval", approval.Task()).
+//		Do("deploy", deploy, lyra.Use("build"), lyra.Use("approval"))
+//
+//	// in the approval HTTP handler
+//	approval.Fire(r.FormValue("approver"))
+type Signal[T any] struct {
+	once  sync.Once
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// NewSignal returns a Signal that has not fired yet.
+func NewSignal[T any]() *Signal[T] {
+	return &Signal[T]{done: make(chan struct{})}
+}
+
+// Fire completes the signal with value. Only the first call to Fire or Fail
+// has an effect; it can be used directly as a callback.
+func (s *Signal[T]) Fire(value T) {
+	s.once.Do(func() {
+		s.value = value
+		close(s.done)
+	})
+}
+
+// Fail completes the signal with err, failing the tasks waiting for it, for
+// example when an approval is rejected. Only the first call to Fire or Fail
+// has an effect.
+func (s *Signal[T]) Fail(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
+
+// Task returns a task function that waits for the signal and returns its
+// value, or fails when the signal fails or the task's context is done. A
+// signal can be waited for by any number of tasks and runs; once fired it
+// stays fired.
+func (s *Signal[T]) Task() func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		select {
+		case <-s.done:
+			return s.value, s.err
+		case <-ctx.Done():
+			var zero T
+			return zero, context.Cause(ctx)
+		}
+	}
+}
+
+// WaitChan returns a task function that waits for a value on ch, or for ch
+// to be closed, in which case it returns the zero value.
+//
+// Example:
+//
+//	l.Do("ready", lyra.WaitChan(cacheWarmed))
+func WaitChan[T any](ch <-chan T) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		select {
+		case value := <-ch:
+			return value, nil
+		case <-ctx.Done():
+			var zero T
+			return zero, context.Cause(ctx)
+		}
+	}
+}
+
+// WaitUntil returns a task function that calls cond every interval, starting
+// immediately, until it reports true. The task fails with the error of cond,
+// or when its context is done.
+//
+// Example:
+//
+//	l.Do("replicaReady", lyra.WaitUntil(5*time.Second, func(ctx context.Context) (bool, error) {
+//		return replica.Healthy(ctx)
+//	}))
+func WaitUntil(interval time.Duration, cond func(ctx context.Context) (bool, error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			ok, err := cond(ctx)
+			if err != nil || ok {
+				return err
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+	}
+}
+
+// Delay returns a task function that waits for d, or fails when its context
+// is done first.
+//
+// Example:
+//
+//	l.Do("cooldown", lyra.Delay(time.Second))
+func Delay(d time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// DelayValue is like Delay, but the task reads a value and returns it
+// unchanged after the wait. Binding it to the output of a task places the
+// wait between that task and the tasks reading the delayed value.
+//
+// Example:
+//
+//	l.Do("page1", fetchPage, lyra.UseRun("url")).
+//		Do("page1Delayed", lyra.DelayValue[Page](time.Second), lyra.Use("page1")).
+//		Do("page2", fetchNext, lyra.Use("page1Delayed"))
+func DelayValue[T any](d time.Duration) func(ctx context.Context, value T) (T, error) {
+	wait := Delay(d)
+	return func(ctx context.Context, value T) (T, error) {
+		if err := wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		return value, nil
+	}
+}
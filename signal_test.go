@@ -0,0 +1,122 @@
+package lyra
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignal(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		complete func(s *Signal[string])
+		expected string
+		err      error
+	}{
+		{
+			name:     "fired",
+			complete: func(s *Signal[string]) { s.Fire("alice"); s.Fail(errTaskFailed) },
+			expected: "deployed by alice",
+		},
+		{
+			name:     "failed",
+			complete: func(s *Signal[string]) { s.Fail(errTaskFailed); s.Fire("alice") },
+			err:      errTaskFailed,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			approval := NewSignal[string]()
+			l := New().
+				Do("approval", approval.Task()).
+				Do("deploy", func(ctx context.Context, approver string) (string, error) {
+					return "deployed by " + approver, nil
+				}, Use("approval"))
+
+			time.AfterFunc(10*time.Millisecond, func() { tc.complete(approval) })
+			result, err := l.Run(context.Background(), nil)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				deploy, err := result.Get("deploy")
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, deploy)
+			}
+		})
+	}
+}
+
+func TestSignalCanceled(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("approval", NewSignal[bool]().Task())
+
+	_, err := l.Run(context.Background(), nil, WithRunTimeout(10*time.Millisecond))
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitChan(t *testing.T) {
+	t.Parallel()
+
+	ready := make(chan int)
+	close(ready)
+	l := New().Do("ready", WaitChan(ready))
+
+	result, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	value, err := result.Get("ready")
+	require.NoError(t, err)
+	require.Equal(t, 0, value)
+}
+
+func TestWaitUntil(t *testing.T) {
+	t.Parallel()
+
+	var polls atomic.Int32
+	l := New().Do("ready", WaitUntil(time.Millisecond, func(ctx context.Context) (bool, error) {
+		return polls.Add(1) == 3, nil
+	}))
+
+	_, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, int32(3), polls.Load())
+
+	failing := New().Do("ready", WaitUntil(time.Millisecond, func(ctx context.Context) (bool, error) {
+		return false, errTaskFailed
+	}))
+	_, err = failing.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errTaskFailed)
+}
+
+func TestDelay(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("first", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("delayed", DelayValue[int](20*time.Millisecond), Use("first")).
+		Do("second", func(ctx context.Context, n int) (int, error) { return n + 1, nil }, Use("delayed"))
+
+	start := time.Now()
+	result, err := l.Run(context.Background(), nil)
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	second, err := result.Get("second")
+	require.NoError(t, err)
+	require.Equal(t, 2, second)
+
+	canceled := New().Do("wait", Delay(time.Hour))
+	_, err = canceled.Run(context.Background(), nil, WithRunTimeout(10*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,91 @@
+package lyra
+
+import (
+	"reflect"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// dagSnapshot is the immutable view of the DAG shared by every run of a
+// Lyra instance. It is built on the first run, after which the builder is
+// frozen, so runs never lock the builder or repeat its analysis.
+type dagSnapshot struct {
+	tasks        map[string]*compiledTask
+	deps         map[string][]string
+	strictDeps   map[string][]string
+	stages       [][]string
+	secrets      map[string]struct{}
+	leaves       map[string]struct{}
+	requirements map[string][]inputRequirement
+	// quorumReaders maps the sources of tasks with a quorum to those
+	// tasks.
+	quorumReaders map[string][]string
+	// slots maps the tasks to the indexes of their result slots.
+	slots map[string]int
+	// idempotency is the default IdempotencyStore of the runs of the
+	// snapshot.
+	idempotency IdempotencyStore
+	// bulkheads are shared by the runs of the snapshot.
+	bulkheads map[string][]chan struct{}
+}
+
+// freeze stops further changes to the DAG and returns its snapshot,
+// building it on the first call.
+func (l *Lyra) freeze() (*dagSnapshot, error) {
+	l.snapshotOnce.Do(func() {
+		l.mu.Lock()
+		l.frozen = true
+		tasks, err := wireTasks(l.tasks)
+		match := l.fieldMatcher
+		tagLimits := l.tagLimits
+		l.mu.Unlock()
+		if err != nil {
+			l.snapshotErr = err
+			return
+		}
+		withQuorumInputs(tasks)
+		if err := checkQuorums(tasks); err != nil {
+			l.snapshotErr = err
+			return
+		}
+
+		deps := dependencies(tasks)
+		stages, err := buildStages(deps)
+		if err != nil {
+			l.snapshotErr = err
+			return
+		}
+		prioritizeStages(stages, deps, l.taskPriorities())
+
+		compiled := compileTasks(tasks, match)
+		l.snapshot = &dagSnapshot{
+			tasks:         compiled,
+			deps:          deps,
+			strictDeps:    strictDependencies(tasks),
+			stages:        stages,
+			secrets:       l.secretKeys(),
+			leaves:        leafTasks(deps),
+			requirements:  l.inputRequirements(),
+			slots:         slotIndexes(stages),
+			quorumReaders: quorumReaders(compiled),
+			bulkheads:     bulkheadSlots(tasks, tagLimits),
+			idempotency:   &memoryIdempotencyStore{maxEntries: defaultIdempotencyEntries},
+		}
+	})
+	return l.snapshot, l.snapshotErr
+}
+
+// compileTasks compiles the argument resolvers of every task, selecting
+// fields with match.
+func compileTasks(tasks map[string]*internal.Task, match FieldMatcher) map[string]*compiledTask {
+	outputTypes := make(map[string]reflect.Type, len(tasks))
+	for taskID, task := range tasks {
+		outputTypes[taskID] = task.GetOutputParams()
+	}
+
+	compiled := make(map[string]*compiledTask, len(tasks))
+	for taskID, task := range tasks {
+		compiled[taskID] = compileTask(task, outputTypes, match)
+	}
+	return compiled
+}
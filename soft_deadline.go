@@ -0,0 +1,46 @@
+package lyra
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithSoftDeadline warns when the task runs longer than d without canceling
+// it, to surface hung external calls before the whole run times out. Once d
+// has passed, an EventTaskOverdue event is emitted and onExceed, if not
+// nil, is called in its own goroutine with the task's context and the time
+// the task has been running. Use TaskIDFromContext and RunIDFromContext to
+// identify the task.
+//
+// Example:
+//
+//	l.Do("charge", charge, lyra.Use("order"),
+//		lyra.WithSoftDeadline(5*time.Second, func(ctx context.Context, elapsed time.Duration) {
+//			taskID, _ := lyra.TaskIDFromContext(ctx)
+//			log.Printf("task %s still running after %s", taskID, elapsed)
+//		}))
+func WithSoftDeadline(d time.Duration, onExceed func(ctx context.Context, elapsed time.Duration)) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.SoftDeadline = d
+		o.OnSoftDeadline = onExceed
+	})
+}
+
+// watchSoftDeadline starts the soft deadline of the task, if it has one,
+// and returns the function stopping it.
+func watchSoftDeadline(ctx context.Context, stageIdx int, task *compiledTask, state *runState) (stop func()) {
+	opts := task.GetOptions()
+	if opts.SoftDeadline <= 0 {
+		return func() {}
+	}
+	start := time.Now()
+	timer := time.AfterFunc(opts.SoftDeadline, func() {
+		state.emit(Event{Type: EventTaskOverdue, Stage: stageIdx, TaskID: task.GetID()})
+		if opts.OnSoftDeadline != nil {
+			opts.OnSoftDeadline(ctx, time.Since(start))
+		}
+	})
+	return func() { timer.Stop() }
+}
@@ -0,0 +1,76 @@
+package lyra
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSoftDeadline(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		duration time.Duration
+		overdue  bool
+	}{
+		{
+			name:     "task exceeding the deadline",
+			duration: 50 * time.Millisecond,
+			overdue:  true,
+		},
+		{
+			name:     "task within the deadline",
+			duration: 0,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu      sync.Mutex
+				taskIDs []string
+				elapsed time.Duration
+			)
+			onExceed := func(ctx context.Context, d time.Duration) {
+				taskID, _ := TaskIDFromContext(ctx)
+				mu.Lock()
+				defer mu.Unlock()
+				taskIDs = append(taskIDs, taskID)
+				elapsed = d
+			}
+			l := New().Do("slow", func(ctx context.Context) (string, error) {
+				time.Sleep(tc.duration)
+				return "done", nil
+			}, WithSoftDeadline(10*time.Millisecond, onExceed))
+
+			run := l.RunAsync(context.Background(), nil)
+			var types []EventType
+			for e := range run.Events() {
+				types = append(types, e.Type)
+			}
+			result, err := run.Wait()
+
+			require.NoError(t, err)
+			value, err := result.Get("slow")
+			require.NoError(t, err)
+			require.Equal(t, "done", value) // the task is never canceled
+
+			mu.Lock()
+			defer mu.Unlock()
+			if tc.overdue {
+				require.Equal(t, []string{"slow"}, taskIDs)
+				require.GreaterOrEqual(t, elapsed, 10*time.Millisecond)
+				require.Contains(t, types, EventTaskOverdue)
+			} else {
+				require.Empty(t, taskIDs)
+				require.NotContains(t, types, EventTaskOverdue)
+			}
+		})
+	}
+}
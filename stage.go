@@ -0,0 +1,77 @@
+package lyra
+
+import "time"
+
+// StageSummary describes a stage of a run: a set of tasks executed
+// together once every earlier stage finished.
+type StageSummary struct {
+	// Index is the position of the stage in the run, starting at 0; it
+	// matches TaskSummary.Stage and Event.Stage.
+	Index int
+	// TaskIDs lists the tasks of the stage in dispatch order; it must not
+	// be modified.
+	TaskIDs []string
+	// Start is when the stage started; zero for stages that never started.
+	Start time.Time
+	// Duration is how long the stage ran; zero for stages that never
+	// started.
+	Duration time.Duration
+	// Err is the error of the stage, if any.
+	Err error
+}
+
+// WithStageHooks calls onStart when a stage of the run starts and onEnd
+// when it finished, so coarse-grained phases of a pipeline can be timed and
+// logged as units. Either hook may be nil. Hooks are called in stage order
+// on the execution path, so they should return quickly.
+//
+// Example:
+//
+//	l.Run(ctx, inputs, lyra.WithStageHooks(
+//		func(stageIdx int, taskIDs []string) {
+//			log.Printf("stage %d started: %v", stageIdx, taskIDs)
+//		},
+//		func(s lyra.StageSummary) {
+//			log.Printf("stage %d finished in %s", s.Index, s.Duration)
+//		}))
+func WithStageHooks(onStart func(stageIdx int, taskIDs []string), onEnd func(StageSummary)) RunOption {
+	return func(cfg *runConfig) {
+		cfg.onStageStart = onStart
+		cfg.onStageEnd = onEnd
+	}
+}
+
+// newStageSummaries returns the summaries of stages that did not start.
+func newStageSummaries(stages [][]string) []StageSummary {
+	summaries := make([]StageSummary, len(stages))
+	for i, stage := range stages {
+		summaries[i] = StageSummary{Index: i, TaskIDs: stage}
+	}
+	return summaries
+}
+
+// startStage records that the stage started and calls the start hook.
+func (s *runState) startStage(stageIdx int) {
+	s.mu.Lock()
+	s.stageSummaries[stageIdx].Start = time.Now()
+	s.mu.Unlock()
+
+	if s.cfg.onStageStart != nil {
+		s.cfg.onStageStart(stageIdx, s.stages[stageIdx])
+	}
+}
+
+// finishStage records that the stage finished with err and calls the end
+// hook.
+func (s *runState) finishStage(stageIdx int, err error) {
+	s.mu.Lock()
+	stage := &s.stageSummaries[stageIdx]
+	stage.Duration = time.Since(stage.Start)
+	stage.Err = err
+	summary := *stage
+	s.mu.Unlock()
+
+	if s.cfg.onStageEnd != nil {
+		s.cfg.onStageEnd(summary)
+	}
+}
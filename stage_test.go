@@ -0,0 +1,48 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStageHooks(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetch", func(ctx context.Context) (int, error) { return 1, nil }).
+		Do("parse", func(ctx context.Context, v int) (int, error) { return v, nil }, Use("fetch")).
+		Do("send", func(ctx context.Context, v int) error { return errTaskFailed }, Use("parse")).
+		Do("archive", func(ctx context.Context, v int) (int, error) { return v, nil }, Use("parse")).
+		Do("report", func(ctx context.Context, v int) error { return nil }, Use("archive"))
+
+	var started []int
+	var ended []StageSummary
+	_, err := l.Run(context.Background(), nil, WithStageHooks(
+		func(stageIdx int, taskIDs []string) {
+			started = append(started, stageIdx)
+		},
+		func(s StageSummary) {
+			ended = append(ended, s)
+		}))
+	require.ErrorIs(t, err, errTaskFailed)
+
+	require.Equal(t, []int{0, 1, 2}, started)
+	require.Len(t, ended, 3)
+	for i, s := range ended {
+		require.Equal(t, i, s.Index)
+		require.False(t, s.Start.IsZero())
+	}
+	require.Equal(t, []string{"fetch"}, ended[0].TaskIDs)
+	require.NoError(t, ended[1].Err)
+	require.ElementsMatch(t, []string{"send", "archive"}, ended[2].TaskIDs)
+	require.ErrorIs(t, ended[2].Err, errTaskFailed)
+
+	stages := l.RecentRuns()[0].Stages
+	require.Len(t, stages, 4)
+	require.Equal(t, ended, stages[:3])
+	require.Equal(t, []string{"report"}, stages[3].TaskIDs)
+	require.True(t, stages[3].Start.IsZero())
+	require.Zero(t, stages[3].Duration)
+}
@@ -0,0 +1,78 @@
+package lyra
+
+// TaskStatus is the execution state of a task within a run.
+type TaskStatus int
+
+const (
+	// TaskPending means the task has not started yet.
+	TaskPending TaskStatus = iota
+	// TaskRunning means the task function is executing.
+	TaskRunning
+	// TaskSucceeded means the task completed without error.
+	TaskSucceeded
+	// TaskFailed means the task returned an error or its inputs could not be resolved.
+	TaskFailed
+	// TaskSkipped means the task never started because the run failed first.
+	TaskSkipped
+	// TaskCanceled means the task was canceled, on its own or as the
+	// dependent of a canceled task, or never started because the run was
+	// canceled first.
+	TaskCanceled
+)
+
+// String returns the name of the status.
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskPending:
+		return "pending"
+	case TaskRunning:
+		return "running"
+	case TaskSucceeded:
+		return "succeeded"
+	case TaskFailed:
+		return "failed"
+	case TaskSkipped:
+		return "skipped"
+	case TaskCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// isFinal reports whether the status can no longer change.
+func (s TaskStatus) isFinal() bool {
+	return s != TaskPending && s != TaskRunning
+}
+
+// RunStatus is the execution state of a run started with Lyra.RunAsync.
+type RunStatus int
+
+const (
+	// RunRunning means the run has not finished yet.
+	RunRunning RunStatus = iota
+	// RunSucceeded means every task completed and the run returned no error.
+	RunSucceeded
+	// RunFailed means the run returned an error, including errors
+	// detected before any task executed.
+	RunFailed
+	// RunCanceled means the run stopped because it was canceled with
+	// Run.Cancel or through its context.
+	RunCanceled
+)
+
+// String returns the name of the status.
+func (s RunStatus) String() string {
+	switch s {
+	case RunRunning:
+		return "running"
+	case RunSucceeded:
+		return "succeeded"
+	case RunFailed:
+		return "failed"
+	case RunCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
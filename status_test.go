@@ -0,0 +1,51 @@
+package lyra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskStatusString(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		status   TaskStatus
+		expected string
+		final    bool
+	}{
+		{TaskPending, "pending", false},
+		{TaskRunning, "running", false},
+		{TaskSucceeded, "succeeded", true},
+		{TaskFailed, "failed", true},
+		{TaskSkipped, "skipped", true},
+		{TaskCanceled, "canceled", true},
+		{TaskStatus(-1), "unknown", true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.status.String())
+			require.Equal(t, tc.final, tc.status.isFinal())
+		})
+	}
+}
+
+func TestRunStatusString(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		status   RunStatus
+		expected string
+	}{
+		{RunRunning, "running"},
+		{RunSucceeded, "succeeded"},
+		{RunFailed, "failed"},
+		{RunCanceled, "canceled"},
+		{RunStatus(-1), "unknown"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.status.String())
+		})
+	}
+}
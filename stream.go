@@ -0,0 +1,60 @@
+package lyra
+
+import "context"
+
+// TaskResult is the outcome of one task, delivered by RunStreamResults.
+type TaskResult struct {
+	// TaskID is the task the result is about; empty for the final error of
+	// a failed run.
+	TaskID string
+	// Value is the output of the task; nil for tasks returning only an
+	// error and for failures.
+	Value any
+	// Err is the error of a failed task, or of the run for the final
+	// result of a failed run.
+	Err error
+}
+
+// RunStreamResults runs the DAG like Run, but delivers the outcome of every
+// task on the returned channel as soon as the task completes or fails, so
+// callers can flush early data, for example as server-sent events, while
+// slower branches are still running. Values are delivered as returned by
+// the tasks, without redaction.
+//
+// If the run fails, a last TaskResult with an empty TaskID carries the error
+// Run would have returned. The channel is closed once the run has finished.
+// It is buffered for every task, so the run never waits for the reader.
+//
+// Errors detected before any task executes, such as build errors and invalid
+// runtime inputs, are returned directly.
+//
+// Example:
+//
+//	results, err := l.RunStreamResults(ctx, inputs)
+//	if err != nil {
+//		return err
+//	}
+//	for r := range results {
+//		fmt.Fprintf(w, "event: %s\ndata: %v\n\n", r.TaskID, r.Value)
+//		flusher.Flush()
+//	}
+func (l *Lyra) RunStreamResults(
+	ctx context.Context,
+	runInputs map[string]any,
+	opts ...RunOption,
+) (<-chan TaskResult, error) {
+	state, err := l.prepare(runInputs, newRunConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	stream := make(chan TaskResult, len(state.deps)+1)
+	state.stream = stream
+
+	go func() {
+		defer close(stream)
+		if _, err := l.execute(ctx, state); err != nil {
+			stream <- TaskResult{Err: err}
+		}
+	}()
+	return stream, nil
+}
@@ -0,0 +1,68 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestRunStreamResults(t *testing.T) {
+	t.Parallel()
+
+	fastRead := make(chan struct{})
+	l := New().
+		Do("fast", func(ctx context.Context) (string, error) { return "fast", nil }).
+		Do("slow", func(ctx context.Context) (string, error) {
+			<-fastRead // only finishes once the fast result was streamed
+			return "slow", nil
+		}).
+		Do("notify", func(ctx context.Context, _ string) error { return nil }, Use("slow"))
+
+	stream, err := l.RunStreamResults(context.Background(), nil)
+	require.NoError(t, err)
+
+	first := <-stream
+	require.Equal(t, TaskResult{TaskID: "fast", Value: "fast"}, first)
+	close(fastRead)
+
+	var rest []TaskResult
+	for r := range stream {
+		rest = append(rest, r)
+	}
+	require.Equal(t, []TaskResult{{TaskID: "slow", Value: "slow"}, {TaskID: "notify"}}, rest)
+}
+
+func TestRunStreamResultsFailure(t *testing.T) {
+	t.Parallel()
+
+	errFailed := stderr.New("failed") //nolint:err113 // test case.
+	l := New().Do("broken", func(ctx context.Context) error { return errFailed })
+
+	stream, err := l.RunStreamResults(context.Background(), nil)
+	require.NoError(t, err)
+
+	var results []TaskResult
+	for r := range stream {
+		results = append(results, r)
+	}
+	require.Len(t, results, 2)
+	require.Equal(t, "broken", results[0].TaskID)
+	require.ErrorIs(t, results[0].Err, errFailed)
+	require.Empty(t, results[1].TaskID)
+	require.ErrorIs(t, results[1].Err, errFailed)
+}
+
+func TestRunStreamResultsInvalidInputs(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("greet", func(ctx context.Context, name string) error { return nil }, UseRun("name"))
+
+	stream, err := l.RunStreamResults(context.Background(), nil)
+
+	require.ErrorIs(t, err, errors.ErrMissingRunInput)
+	require.Nil(t, stream)
+}
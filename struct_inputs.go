@@ -0,0 +1,100 @@
+package lyra
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+// inputTag is the struct tag naming the runtime input key of a field.
+const inputTag = "lyra"
+
+// StructInputs converts a struct, or a pointer to one, into a runtime input
+// map, so callers can describe the inputs of a DAG with a typed struct
+// instead of string keys.
+//
+// Each exported field becomes one input. The key is taken from the `lyra`
+// struct tag and defaults to the field name:
+//
+//	type CheckoutInputs struct {
+//		UserID  int    `lyra:"userID"`
+//		Coupon  string `lyra:"coupon,omitempty"` // omitted when empty
+//		Verbose bool   `lyra:"-"`                // never an input
+//		Region  string                           // key "Region"
+//	}
+//
+// Fields of embedded structs without a tag are promoted, like in
+// encoding/json. Unexported fields are ignored.
+//
+// Returns ErrInvalidRunInputs if v is not a struct or is a nil pointer.
+func StructInputs(v any) (map[string]any, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, errors.Wrapf(errors.ErrInvalidRunInputs, "nil %s", value.Type())
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, errors.Wrapf(errors.ErrInvalidRunInputs, "expected a struct, got %T", v)
+	}
+
+	inputs := make(map[string]any, value.NumField())
+	collectStructInputs(value, inputs)
+	return inputs, nil
+}
+
+// RunStruct executes the DAG like Run, taking the runtime inputs from the
+// fields of a struct as described by StructInputs.
+//
+// Example:
+//
+//	results, err := l.RunStruct(ctx, CheckoutInputs{UserID: 123})
+func (l *Lyra) RunStruct(ctx context.Context, inputs any, opts ...RunOption) (*Result, error) {
+	runInputs, err := StructInputs(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return l.Run(ctx, runInputs, opts...)
+}
+
+func collectStructInputs(value reflect.Value, inputs map[string]any) {
+	structType := value.Type()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		tag, hasTag := field.Tag.Lookup(inputTag)
+		if tag == "-" {
+			continue
+		}
+
+		name, flags, _ := strings.Cut(tag, ",")
+		fieldValue := value.Field(i)
+
+		if field.Anonymous && !hasTag {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectStructInputs(embedded, inputs)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if flags == "omitempty" && fieldValue.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		inputs[name] = fieldValue.Interface()
+	}
+}
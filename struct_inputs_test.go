@@ -0,0 +1,126 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+type auditInputs struct {
+	Actor string `lyra:"actor"`
+}
+
+type TraceInputs struct {
+	TraceID string `lyra:"traceID"`
+}
+
+type checkoutInputs struct {
+	auditInputs
+	*TraceInputs
+
+	UserID  int    `lyra:"userID"`
+	Coupon  string `lyra:"coupon,omitempty"`
+	Verbose bool   `lyra:"-"`
+	Region  string
+	secret  string
+}
+
+func TestStructInputs(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		value       any
+		expected    map[string]any
+		expectedErr error
+	}{
+		{
+			name: "tags, defaults and skipped fields",
+			value: checkoutInputs{
+				auditInputs: auditInputs{Actor: "admin"},
+				UserID:      123,
+				Verbose:     true,
+				Region:      "eu",
+				secret:      "hidden",
+			},
+			expected: map[string]any{
+				"actor":  "admin",
+				"userID": 123,
+				"Region": "eu",
+			},
+		},
+		{
+			name: "pointer with omitempty set and embedded pointer",
+			value: &checkoutInputs{
+				TraceInputs: &TraceInputs{TraceID: "t-1"},
+				Coupon:      "SAVE10",
+			},
+			expected: map[string]any{
+				"actor":   "",
+				"traceID": "t-1",
+				"userID":  0,
+				"coupon":  "SAVE10",
+				"Region":  "",
+			},
+		},
+		{
+			name:     "empty struct",
+			value:    struct{}{},
+			expected: map[string]any{},
+		},
+		{
+			name:        "nil pointer",
+			value:       (*checkoutInputs)(nil),
+			expectedErr: errors.ErrInvalidRunInputs,
+		},
+		{
+			name:        "not a struct",
+			value:       map[string]any{"userID": 1},
+			expectedErr: errors.ErrInvalidRunInputs,
+		},
+		{
+			name:        "nil",
+			value:       nil,
+			expectedErr: errors.ErrInvalidRunInputs,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			inputs, err := StructInputs(tc.value)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				require.Nil(t, inputs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, inputs)
+		})
+	}
+}
+
+func TestRunStruct(t *testing.T) {
+	t.Parallel()
+
+	type inputs struct {
+		Name     string `lyra:"name"`
+		Greeting string `lyra:"greeting"`
+	}
+
+	l := New().
+		Do("greet", func(ctx context.Context, greeting, name string) (string, error) {
+			return greeting + " " + name, nil
+		}, UseRun("greeting"), UseRun("name"))
+
+	result, err := l.RunStruct(context.Background(), inputs{Name: "Alice", Greeting: "Hello"})
+	require.NoError(t, err)
+	greeting, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Alice", greeting)
+
+	result, err = l.RunStruct(context.Background(), "not a struct")
+	require.ErrorIs(t, err, errors.ErrInvalidRunInputs)
+	require.Nil(t, result)
+}
@@ -0,0 +1,146 @@
+package lyra
+
+import (
+	stderr "errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// TaskBuilder registers a task step by step, as an alternative to Do that
+// names every part of the task. Create one with Lyra.Task.
+//
+// The task is added to the DAG once Fn is called and is updated by every
+// later call, so the methods may be called in any order. While the task is
+// incomplete, for example when its function has more parameters than
+// inputs given so far, Run returns the error that building it reports.
+//
+// A TaskBuilder is not safe for concurrent use.
+type TaskBuilder struct {
+	l      *Lyra
+	id     string
+	fn     any
+	inputs []internal.InputSpec
+	task   *internal.Task
+	failed bool
+}
+
+// Task returns a builder for the task taskID, configured with chained
+// calls instead of the variadic arguments of Do.
+//
+// Example:
+//
+//	l.Task("fetchUser").
+//		Fn(fetchUser).
+//		Needs(lyra.UseRun("userID")).
+//		Retry(3).
+//		Timeout(2 * time.Second).
+//		Tag("io")
+func (l *Lyra) Task(taskID string) *TaskBuilder {
+	return &TaskBuilder{l: l, id: taskID}
+}
+
+// Fn sets the function of the task, as the fn argument of Do.
+func (b *TaskBuilder) Fn(fn any) *TaskBuilder {
+	b.fn = fn
+	return b.build()
+}
+
+// Needs adds input specs binding the parameters of the function, in order,
+// as the input specs given to Do.
+func (b *TaskBuilder) Needs(inputs ...internal.InputSpec) *TaskBuilder {
+	return b.With(inputs...)
+}
+
+// With adds task options, such as WithHedge, that have no method of their
+// own.
+func (b *TaskBuilder) With(opts ...TaskOption) *TaskBuilder {
+	b.inputs = append(b.inputs, opts...)
+	return b.build()
+}
+
+// Retry is a shorthand for With(WithRetry(retries)).
+func (b *TaskBuilder) Retry(retries int) *TaskBuilder {
+	return b.With(WithRetry(retries))
+}
+
+// Timeout is a shorthand for With(WithTimeout(d)).
+func (b *TaskBuilder) Timeout(d time.Duration) *TaskBuilder {
+	return b.With(WithTimeout(d))
+}
+
+// Tag is a shorthand for With(WithTag(tags...)).
+func (b *TaskBuilder) Tag(tags ...string) *TaskBuilder {
+	return b.With(WithTag(tags...))
+}
+
+// Priority is a shorthand for With(WithPriority(n)).
+func (b *TaskBuilder) Priority(n int) *TaskBuilder {
+	return b.With(WithPriority(n))
+}
+
+// Describe is a shorthand for With(WithDescription(description)).
+func (b *TaskBuilder) Describe(description string) *TaskBuilder {
+	return b.With(WithDescription(description))
+}
+
+// build adds the task to the DAG, or replaces the version added by an
+// earlier call, once its function is known.
+func (b *TaskBuilder) build() *TaskBuilder {
+	if b.fn == nil || b.failed {
+		return b
+	}
+
+	l := b.l
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.frozen {
+		b.failed = true
+		l.error = errors.Wrapf(errors.ErrDAGFrozen, "failed to add task %q", b.id)
+		return b
+	}
+	if existing, exists := l.tasks[b.id]; exists && existing != b.task {
+		b.failed = true
+		l.error = errors.Wrapf(errors.ErrDuplicateTask, "failed to add task %q", b.id)
+		return b
+	}
+
+	inputs := b.inputs
+	if l.autoWire {
+		inputs = internal.PadAutoSpecs(b.fn, inputs)
+	}
+	task, err := internal.NewTask(b.id, b.fn, inputs)
+	if err != nil {
+		if b.task != nil {
+			delete(l.tasks, b.id)
+			b.task = nil
+		}
+		if l.builderErrs == nil {
+			l.builderErrs = make(map[string]error)
+		}
+		l.builderErrs[b.id] = errors.Wrapf(err, "failed to add task %q", b.id)
+		return b
+	}
+	delete(l.builderErrs, b.id)
+	l.tasks[b.id] = task
+	b.task = task
+	return b
+}
+
+// buildError returns the errors recorded while building the DAG, joined.
+// The caller must hold l.mu.
+func (l *Lyra) buildError() error {
+	if l.error != nil || len(l.builderErrs) == 0 {
+		return l.error
+	}
+	errs := make([]error, 0, len(l.builderErrs))
+	for _, taskID := range slices.Sorted(maps.Keys(l.builderErrs)) {
+		errs = append(errs, l.builderErrs[taskID])
+	}
+	//nolint:wrapcheck // stderr points to standard errors.
+	return stderr.Join(errs...)
+}
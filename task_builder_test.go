@@ -0,0 +1,111 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestTaskBuilder(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	l := New()
+	l.Task("fetchUser").
+		Fn(func(ctx context.Context, userID int) (string, error) {
+			attempts++
+			if attempts < 3 {
+				return "", errTaskFailed
+			}
+			return "user", nil
+		}).
+		Needs(UseRun("userID")).
+		Retry(3).
+		Timeout(time.Second).
+		Tag("io").
+		Describe("fetches the user")
+	l.Task("greet").
+		Needs(Use("fetchUser")).
+		Priority(5).
+		Fn(func(ctx context.Context, name string) (string, error) {
+			return "hello " + name, nil
+		})
+
+	graph, err := l.Graph()
+	require.NoError(t, err)
+	require.Equal(t, []string{"io"}, graph.Nodes[0].Tags)
+	require.Equal(t, "fetches the user", graph.Nodes[0].Description)
+
+	result, err := l.Run(context.Background(), map[string]any{"userID": 1})
+	require.NoError(t, err)
+	value, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, "hello user", value)
+	require.Equal(t, 3, attempts)
+
+	task := l.tasks["fetchUser"].GetOptions()
+	require.Equal(t, 3, task.Retries)
+	require.Equal(t, time.Second, task.Timeout)
+	require.Equal(t, 5, l.tasks["greet"].GetOptions().Priority)
+}
+
+func TestTaskBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name  string
+		build func(l *Lyra)
+		err   error
+	}{
+		{
+			name: "missing inputs",
+			build: func(l *Lyra) {
+				l.Task("task").Fn(func(ctx context.Context, _ int) error { return nil })
+			},
+			err: errors.ErrTaskParamCountMismatch,
+		},
+		{
+			name: "invalid function",
+			build: func(l *Lyra) {
+				l.Task("task").Fn("not a function")
+			},
+			err: errors.ErrMustBeFunction,
+		},
+		{
+			name: "duplicate task",
+			build: func(l *Lyra) {
+				l.Do("task", func(ctx context.Context) error { return nil })
+				l.Task("task").Fn(func(ctx context.Context) error { return nil })
+			},
+			err: errors.ErrDuplicateTask,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New()
+			tc.build(l)
+			_, err := l.Run(context.Background(), nil)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestTaskBuilderAfterFreeze(t *testing.T) {
+	t.Parallel()
+
+	l := New()
+	task := l.Task("task").Fn(func(ctx context.Context) error { return nil })
+	_, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	task.Retry(1)
+	_, err = l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrDAGFrozen)
+}
@@ -0,0 +1,80 @@
+package lyra
+
+import (
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// TaskOption configures a task registered with Lyra.Do.
+//
+// Task options share their type with the input specs returned by Use and
+// UseRun, so both can be mixed in the variadic arguments of Do. Options are
+// not bound to function parameters and may appear anywhere in the list.
+//
+// Example:
+//
+//	l.Do("fetchUser", fetchUser, lyra.UseRun("userID"), lyra.WithPriority(10))
+type TaskOption = internal.InputSpec
+
+// WithPriority sets the scheduling priority of the task.
+//
+// When more tasks are ready than the run's concurrency limit allows (see
+// WithConcurrency), tasks with a higher priority are dispatched first. Ties
+// are broken in favor of tasks on the longest chain of dependents, then by
+// task ID. The default priority is 0; negative values are allowed.
+func WithPriority(n int) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Priority = n
+	})
+}
+
+// WithRetry attempts the task again, up to retries times, when it returns an
+// error. Tasks use AttemptFromContext to tell attempts apart. Errors marked
+// with Permanent, or otherwise classified as not retryable (see
+// RetryableError), fail the task right away. Attempts follow each other
+// immediately unless a delay is set with WithBackoff.
+//
+// Retries stop early when the task's context is done, and are shared with
+// every other task against the run's budget set with WithRetryBudget.
+func WithRetry(retries int) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Retries = retries
+	})
+}
+
+// WithTag labels the task, for example with its owning team, so that
+// events, exports and run reports can be grouped by tag. It can be given
+// several times; tags are kept in order.
+//
+// Example:
+//
+//	l.Do("invoice", invoice, lyra.Use("order"), lyra.WithTag("team:billing"))
+func WithTag(tags ...string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Tags = append(o.Tags, tags...)
+	})
+}
+
+// WithMeta attaches the metadata key to the task with value, replacing any
+// earlier value of key. Like tags, metadata is exposed in events, exports
+// and run reports and does not affect execution.
+func WithMeta(key, value string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		if o.Meta == nil {
+			o.Meta = make(map[string]string)
+		}
+		o.Meta[key] = value
+	})
+}
+
+// WithDescription documents what the task does. The description is shown
+// in the exports of the DAG (see Lyra.Graph) and by the debug handler.
+//
+// Example:
+//
+//	l.Do("fetchUser", fetchUser, lyra.UseRun("userID"),
+//		lyra.WithDescription("fetches the user profile from the CRM"))
+func WithDescription(description string) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Description = description
+	})
+}
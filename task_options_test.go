@@ -0,0 +1,55 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+func TestWithPriority(t *testing.T) {
+	t.Parallel()
+
+	spec := WithPriority(7)
+	require.Equal(t, internal.TaskOptionSpec, spec.Type)
+
+	var options internal.TaskOptions
+	spec.Option(&options)
+	require.Equal(t, 7, options.Priority)
+}
+
+func TestWithTagAndMeta(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("invoice", func(ctx context.Context) (int, error) {
+			return 1, nil
+		}, WithTag("team:billing"), WithMeta("owner", "alice"), WithTag("pii"), WithMeta("owner", "bob")).
+		Do("notify", func(ctx context.Context, _ int) error {
+			return nil
+		}, Use("invoice"))
+
+	graph, err := l.Graph()
+	require.NoError(t, err)
+	require.Equal(t, []string{"team:billing", "pii"}, graph.Nodes[0].Tags)
+	require.Equal(t, map[string]string{"owner": "bob"}, graph.Nodes[0].Meta)
+	require.Nil(t, graph.Nodes[1].Tags)
+
+	run := l.RunAsync(context.Background(), nil)
+	for _, e := range eventsOfType(collectEvents(run), EventTaskFinished) {
+		if e.TaskID == "invoice" {
+			require.Equal(t, []string{"team:billing", "pii"}, e.Tags)
+			require.Equal(t, map[string]string{"owner": "bob"}, e.Meta)
+		} else {
+			require.Nil(t, e.Tags)
+		}
+	}
+	_, err = run.Wait()
+	require.NoError(t, err)
+
+	summary := l.RecentRuns()[0]
+	require.Equal(t, []string{"team:billing", "pii"}, summary.Tasks[0].Tags)
+	require.Equal(t, map[string]string{"owner": "bob"}, summary.Tasks[0].Meta)
+}
@@ -0,0 +1,161 @@
+// Package exectask builds lyra task functions running external commands,
+// for pipelines orchestrating command-line tools.
+//
+// Arguments are text/template strings executed with the task input as dot,
+// and the result of a task holds the captured output of the command:
+//
+//	l.Do("convert", exectask.Command[Job]("ffmpeg", []string{"-i", "{{.Input}}", "{{.Output}}"}),
+//		lyra.UseRun("job"))
+//
+// The command is killed when the task's context is done.
+package exectask
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Output is the result of a command task.
+type Output struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExitError is returned when the command exits with a non-zero code.
+type ExitError struct {
+	Command string
+	Output  Output
+	Err     *exec.ExitError
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s: exit code %d", e.Command, e.Output.ExitCode)
+	if stderr := strings.TrimSpace(e.Output.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+// Unwrap returns the *exec.ExitError.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Option configures a command task.
+type Option func(*config)
+
+type config struct {
+	dir   string
+	env   []string
+	grace time.Duration
+}
+
+// WithDir runs the command in dir instead of the current directory.
+func WithDir(dir string) Option {
+	return func(c *config) {
+		c.dir = dir
+	}
+}
+
+// WithEnv adds the "key=value" entries to the environment of the command,
+// which otherwise inherits the one of the process.
+func WithEnv(env ...string) Option {
+	return func(c *config) {
+		c.env = append(c.env, env...)
+	}
+}
+
+// WithGracePeriod interrupts the command when the task's context is done
+// and only kills it if it is still running after d, so it can clean up.
+// Interrupts are not supported on Windows, where the command is killed.
+func WithGracePeriod(d time.Duration) Option {
+	return func(c *config) {
+		c.grace = d
+	}
+}
+
+// Command returns a task function running name with the arguments built by
+// executing each of args as a template with the task input. Tasks built
+// from an invalid template fail when they run.
+func Command[In any](name string, args []string, opts ...Option) func(ctx context.Context, in In) (Output, error) {
+	templates := make([]*template.Template, len(args))
+	var parseErr error
+	for i, arg := range args {
+		templates[i], parseErr = template.New("arg").Option("missingkey=error").Parse(arg)
+		if parseErr != nil {
+			parseErr = fmt.Errorf("parse argument %d: %w", i, parseErr)
+			break
+		}
+	}
+	run := newRunner(name, opts)
+
+	return func(ctx context.Context, in In) (Output, error) {
+		if parseErr != nil {
+			return Output{}, parseErr
+		}
+		built := make([]string, len(templates))
+		for i, tmpl := range templates {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, in); err != nil {
+				return Output{}, fmt.Errorf("build argument %d: %w", i, err)
+			}
+			built[i] = b.String()
+		}
+		return run(ctx, built)
+	}
+}
+
+// Run returns a task function running name with fixed args.
+//
+// Example:
+//
+//	l.Do("migrate", exectask.Run("migrate", "-path", "db/migrations", "up"))
+func Run(name string, args ...string) func(ctx context.Context) (Output, error) {
+	run := newRunner(name, nil)
+	return func(ctx context.Context) (Output, error) {
+		return run(ctx, args)
+	}
+}
+
+func newRunner(name string, opts []Option) func(ctx context.Context, args []string) (Output, error) {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, args []string) (Output, error) {
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = cfg.dir
+		if len(cfg.env) > 0 {
+			cmd.Env = append(os.Environ(), cfg.env...)
+		}
+		if cfg.grace > 0 {
+			cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+			cmd.WaitDelay = cfg.grace
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+		err := cmd.Run()
+		out := Output{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cmd.ProcessState.ExitCode()}
+		if ctxErr := context.Cause(ctx); ctxErr != nil && err != nil {
+			return out, fmt.Errorf("%s: %w", name, ctxErr)
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return out, &ExitError{Command: name, Output: out, Err: exitErr}
+		}
+		if err != nil {
+			return out, err //nolint:wrapcheck // *exec.Error already names the command.
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,93 @@
+package exectask
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+type Job struct {
+	Name string
+}
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestCommandInDAG(t *testing.T) {
+	t.Parallel()
+	requireShell(t)
+
+	l := lyra.New().
+		Do("greet", Command[Job]("sh", []string{"-c", `echo "hello $1 from $GREETER"; echo warn >&2`, "sh", "{{.Name}}"},
+			WithEnv("GREETER=lyra")), lyra.UseRun("job")).
+		Do("shout", func(ctx context.Context, out string) (string, error) {
+			return out + "!", nil
+		}, lyra.Use("greet", "Stdout"))
+
+	result, err := l.Run(context.Background(), map[string]any{"job": Job{Name: "alice"}})
+
+	require.NoError(t, err)
+	greet, err := result.Get("greet")
+	require.NoError(t, err)
+	require.Equal(t, Output{Stdout: "hello alice from lyra\n", Stderr: "warn\n"}, greet)
+	shout, err := result.Get("shout")
+	require.NoError(t, err)
+	require.Equal(t, "hello alice from lyra\n!", shout)
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+	requireShell(t)
+
+	out, err := Run("sh", "-c", "echo broken >&2; exit 3")(context.Background())
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 3, out.ExitCode)
+	require.Equal(t, "sh: exit code 3: broken", err.Error())
+
+	_, err = Run("lyra-no-such-command")(context.Background())
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	_, err = Command[Job]("echo", []string{"{{.Missing}}"})(context.Background(), Job{})
+	require.ErrorContains(t, err, "build argument 0")
+
+	_, err = Command[Job]("echo", []string{"{{"})(context.Background(), Job{})
+	require.ErrorContains(t, err, "parse argument 0")
+}
+
+func TestCommandCanceled(t *testing.T) {
+	t.Parallel()
+	requireShell(t)
+
+	tcs := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "killed"},
+		{name: "interrupted", opts: []Option{WithGracePeriod(time.Second)}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := Command[Job]("sleep", []string{"10"}, tc.opts...)(ctx, Job{})
+
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			require.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package httptask builds lyra task functions performing HTTP requests, so
+// common fetch steps do not each need a bespoke function.
+//
+// The URL of a request is a text/template executed with the task input as
+// dot, and JSON responses are decoded into the task result:
+//
+//	l.Do("fetchUser", httptask.Get[int, User]("https://crm.example.com/users/{{.}}"),
+//		lyra.UseRun("userID"))
+//
+// Templates can escape values with the path and query functions:
+//
+//	httptask.Get[Search, []Item]("https://api.example.com/items?q={{query .Term}}")
+package httptask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// maxErrorBody is the number of response body bytes kept in a StatusError.
+const maxErrorBody = 1024
+
+// StatusError is returned when the server answers with a status code
+// outside of the 2xx range.
+type StatusError struct {
+	Method string
+	URL    string
+	Code   int
+	// Body holds the start of the response body.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.URL, e.Code, e.Body)
+}
+
+// Option configures a request task.
+type Option func(*config)
+
+type config struct {
+	client   *http.Client
+	header   http.Header
+	jsonBody bool
+}
+
+// WithClient sends the requests with client instead of http.DefaultClient.
+func WithClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithHeader adds the header key with value to every request.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithJSONBody sends the task input encoded as JSON as the request body.
+func WithJSONBody() Option {
+	return func(c *config) {
+		c.jsonBody = true
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"path":  url.PathEscape,
+	"query": url.QueryEscape,
+}
+
+// Request returns a task function sending a method request to the URL built
+// by executing urlTemplate with the task input, and decoding the JSON
+// response into Out. Responses with an empty body leave Out at its zero
+// value. Tasks built from an invalid template fail when they run.
+func Request[In, Out any](method, urlTemplate string, opts ...Option) func(ctx context.Context, in In) (Out, error) {
+	cfg := &config{client: http.DefaultClient, header: make(http.Header)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	tmpl, parseErr := template.New("url").Funcs(templateFuncs).Option("missingkey=error").Parse(urlTemplate)
+
+	return func(ctx context.Context, in In) (Out, error) {
+		var out Out
+		if parseErr != nil {
+			return out, fmt.Errorf("parse URL template: %w", parseErr)
+		}
+		var rawURL strings.Builder
+		if err := tmpl.Execute(&rawURL, in); err != nil {
+			return out, fmt.Errorf("build URL: %w", err)
+		}
+
+		var body io.Reader
+		if cfg.jsonBody {
+			data, err := json.Marshal(in)
+			if err != nil {
+				return out, fmt.Errorf("encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, rawURL.String(), body)
+		if err != nil {
+			return out, fmt.Errorf("create request: %w", err)
+		}
+		req.Header = cfg.header.Clone()
+		req.Header.Set("Accept", "application/json")
+		if cfg.jsonBody {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := cfg.client.Do(req)
+		if err != nil {
+			return out, err //nolint:wrapcheck // *url.Error already names the request.
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+			return out, &StatusError{Method: method, URL: req.URL.Redacted(), Code: resp.StatusCode, Body: string(data)}
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && !errors.Is(err, io.EOF) {
+			return out, fmt.Errorf("decode response of %s %s: %w", method, req.URL.Redacted(), err)
+		}
+		return out, nil
+	}
+}
+
+// Get is Request with the GET method.
+func Get[In, Out any](urlTemplate string, opts ...Option) func(ctx context.Context, in In) (Out, error) {
+	return Request[In, Out](http.MethodGet, urlTemplate, opts...)
+}
+
+// Post is Request with the POST method, sending the task input as a JSON
+// body.
+func Post[In, Out any](urlTemplate string, opts ...Option) func(ctx context.Context, in In) (Out, error) {
+	return Request[In, Out](http.MethodPost, urlTemplate, append([]Option{WithJSONBody()}, opts...)...)
+}
@@ -0,0 +1,105 @@
+package httptask
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Search struct {
+	Team string
+	Term string
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "7" {
+			http.Error(w, "no such user", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(User{ID: 7, Name: "Alice"})
+	})
+	mux.HandleFunc("GET /teams/{team}/search", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]string{r.PathValue("team"), r.URL.Query().Get("q"), r.Header.Get("X-Token")})
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		var u User
+		_ = json.NewDecoder(r.Body).Decode(&u)
+		u.ID = 8
+		_ = json.NewEncoder(w).Encode(u)
+	})
+	mux.HandleFunc("DELETE /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetInDAG(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	l := lyra.New().
+		Do("fetchUser", Get[int, User](server.URL+"/users/{{.}}"), lyra.UseRun("userID")).
+		Do("greeting", func(ctx context.Context, name string) (string, error) {
+			return "Hello " + name, nil
+		}, lyra.Use("fetchUser", "Name"))
+
+	result, err := l.Run(context.Background(), map[string]any{"userID": 7})
+
+	require.NoError(t, err)
+	greeting, err := result.Get("greeting")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Alice", greeting)
+}
+
+func TestRequest(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+
+	search := Get[Search, []string](server.URL+"/teams/{{path .Team}}/search?q={{query .Term}}", WithHeader("X-Token", "t0k"))
+	found, err := search(context.Background(), Search{Team: "a/b", Term: "x&y"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b", "x&y", "t0k"}, found)
+
+	created, err := Post[User, User](server.URL+"/users")(context.Background(), User{Name: "Bob"})
+	require.NoError(t, err)
+	require.Equal(t, User{ID: 8, Name: "Bob"}, created)
+
+	deleted, err := Request[int, any](http.MethodDelete, server.URL+"/users/{{.}}")(context.Background(), 8)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+}
+
+func TestRequestErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+
+	_, err := Get[int, User](server.URL+"/users/{{.}}")(context.Background(), 1)
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusNotFound, statusErr.Code)
+	require.Equal(t, "no such user\n", statusErr.Body)
+
+	_, err = Get[int, User](server.URL+"/users/{{")(context.Background(), 1)
+	require.ErrorContains(t, err, "parse URL template")
+
+	_, err = Get[Search, User](server.URL+"/users/{{.Missing}}")(context.Background(), Search{})
+	require.ErrorContains(t, err, "build URL")
+}
@@ -0,0 +1,112 @@
+// Package sqltask coordinates transactional steps inside a lyra DAG: the
+// tasks of a run share one *sql.Tx, committed when the run succeeds and
+// rolled back when it fails.
+//
+//	scope := sqltask.NewScope(db, nil)
+//	l := lyra.New().
+//		Do("debit", sqltask.Exec[Transfer]("UPDATE accounts SET balance = balance - $1 WHERE id = $2",
+//			func(t Transfer) []any { return []any{t.Amount, t.From} }), lyra.UseRun("transfer")).
+//		Do("credit", sqltask.Exec[Transfer]("UPDATE accounts SET balance = balance + $1 WHERE id = $2",
+//			func(t Transfer) []any { return []any{t.Amount, t.To} }), lyra.UseRun("transfer"))
+//	result, err := scope.Run(ctx, l, map[string]any{"transfer": t})
+//
+// Statements of tasks running concurrently are serialized on the single
+// connection of the transaction.
+package sqltask
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// ErrNoTransaction is returned by the tasks of this package when they run
+// outside of Scope.Run.
+var ErrNoTransaction = errors.New("sqltask: no transaction in context")
+
+type txKey struct{}
+
+// Scope runs DAGs inside a transaction of a database.
+type Scope struct {
+	db   *sql.DB
+	opts *sql.TxOptions
+}
+
+// NewScope returns a Scope beginning transactions on db with opts, which
+// may be nil for the driver defaults.
+func NewScope(db *sql.DB, opts *sql.TxOptions) *Scope {
+	return &Scope{db: db, opts: opts}
+}
+
+// Run begins a transaction, runs l with it available to the tasks, and
+// commits it if the run succeeds. If the run fails the transaction is
+// rolled back and the run error returned.
+func (s *Scope) Run(
+	ctx context.Context,
+	l *lyra.Lyra,
+	runInputs map[string]any,
+	opts ...lyra.RunOption,
+) (*lyra.Result, error) {
+	tx, err := s.db.BeginTx(ctx, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	result, err := l.Run(context.WithValue(ctx, txKey{}, tx), runInputs, opts...)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("rollback transaction: %w", rollbackErr))
+		}
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// TxFromContext returns the transaction of the run owning ctx.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// Task adapts fn into a task function receiving the transaction of the run.
+//
+// Example:
+//
+//	l.Do("loadOrder", sqltask.Task(func(ctx context.Context, tx *sql.Tx, id int) (Order, error) {
+//		var o Order
+//		err := tx.QueryRowContext(ctx, "SELECT id, total FROM orders WHERE id = $1", id).Scan(&o.ID, &o.Total)
+//		return o, err
+//	}), lyra.UseRun("orderID"))
+func Task[In, Out any](fn func(ctx context.Context, tx *sql.Tx, in In) (Out, error)) func(ctx context.Context, in In) (Out, error) {
+	return func(ctx context.Context, in In) (Out, error) {
+		tx, ok := TxFromContext(ctx)
+		if !ok {
+			var zero Out
+			return zero, ErrNoTransaction
+		}
+		return fn(ctx, tx, in)
+	}
+}
+
+// Exec returns a task function executing query with the arguments returned
+// by args for the task input. The task result is the number of affected
+// rows.
+func Exec[In any](query string, args func(in In) []any) func(ctx context.Context, in In) (int64, error) {
+	return Task(func(ctx context.Context, tx *sql.Tx, in In) (int64, error) {
+		res, err := tx.ExecContext(ctx, query, args(in)...)
+		if err != nil {
+			return 0, fmt.Errorf("exec %q: %w", query, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("rows affected by %q: %w", query, err)
+		}
+		return affected, nil
+	})
+}
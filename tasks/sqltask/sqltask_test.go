@@ -0,0 +1,146 @@
+package sqltask
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderr "errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+var errExec = stderr.New("constraint violated")
+
+// fakeDB records the statements and transaction boundaries it receives.
+type fakeDB struct {
+	mu     sync.Mutex
+	log    []string
+	failOn string
+}
+
+func (db *fakeDB) record(entry string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.log = append(db.log, entry)
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+
+//nolint:staticcheck // driver.Conn requires Begin.
+func (c fakeConn) Begin() (driver.Tx, error) {
+	c.db.record("begin")
+	return fakeTx{c.db}, nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx fakeTx) Commit() error   { tx.db.record("commit"); return nil }
+func (tx fakeTx) Rollback() error { tx.db.record("rollback"); return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+//nolint:staticcheck // driver.Stmt requires Exec.
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.query == s.db.failOn {
+		return nil, errExec
+	}
+	s.db.record(fmt.Sprint(s.query, args))
+	return driver.RowsAffected(1), nil
+}
+
+//nolint:staticcheck // driver.Stmt requires Query.
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+type Transfer struct {
+	From, To string
+	Amount   int64
+}
+
+func newTransferDAG() *lyra.Lyra {
+	return lyra.New().
+		Do("debit", Exec("debit", func(t Transfer) []any {
+			return []any{t.From, t.Amount}
+		}), lyra.UseRun("transfer")).
+		Do("credit", Exec("credit", func(t Transfer) []any {
+			return []any{t.To, t.Amount}
+		}), lyra.UseRun("transfer")).
+		Do("total", Task(func(ctx context.Context, tx *sql.Tx, affected int64) (int64, error) {
+			return affected, nil
+		}), lyra.Use("debit"))
+}
+
+func TestScopeRun(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name       string
+		failOn     string
+		err        error
+		statements []string
+		end        string
+	}{
+		{
+			name:       "commit on success",
+			statements: []string{"credit[bob 5]", "debit[alice 5]"},
+			end:        "commit",
+		},
+		{
+			name:       "rollback on failure",
+			failOn:     "credit",
+			err:        errExec,
+			statements: []string{"debit[alice 5]"},
+			end:        "rollback",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeDB{failOn: tc.failOn}
+			db := sql.OpenDB(fake)
+			t.Cleanup(func() { _ = db.Close() })
+
+			result, err := NewScope(db, nil).Run(context.Background(), newTransferDAG(),
+				map[string]any{"transfer": Transfer{From: "alice", To: "bob", Amount: 5}})
+
+			require.ErrorIs(t, err, tc.err)
+			require.Len(t, fake.log, len(tc.statements)+2)
+			require.Equal(t, "begin", fake.log[0])
+			require.ElementsMatch(t, tc.statements, fake.log[1:len(fake.log)-1])
+			require.Equal(t, tc.end, fake.log[len(fake.log)-1])
+			if tc.err == nil {
+				total, err := result.Get("total")
+				require.NoError(t, err)
+				require.Equal(t, int64(1), total)
+			}
+		})
+	}
+}
+
+func TestTaskWithoutScope(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTransferDAG().Run(context.Background(), map[string]any{"transfer": Transfer{}})
+
+	require.ErrorIs(t, err, ErrNoTransaction)
+}
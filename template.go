@@ -0,0 +1,161 @@
+package lyra
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// UseTemplate binds the string rendered by the text/template text against
+// the results of other tasks and the runtime inputs, removing tasks that
+// only format values.
+//
+// The template's dot is a map keyed by task ID and runtime input key. The
+// task depends on every referenced key that names a task; every other key
+// is a required runtime input. The bound parameter must accept a string.
+// Rendering fails on missing keys, and an invalid template makes Do fail.
+//
+// Example:
+//
+//	l.Do("notify", notify, lyra.UseTemplate("Hello {{.fetchUser.Name}}, order {{.orderID}}"))
+func UseTemplate(text string) internal.InputSpec {
+	spec := internal.InputSpec{Type: internal.TemplateInputSpec, Source: text}
+	tmpl, err := template.New("input").Option("missingkey=error").Parse(text)
+	if err != nil {
+		spec.Err = errors.Wrapf(err, "invalid template")
+		return spec
+	}
+	spec.Template = tmpl
+	spec.Refs = templateRefs(tmpl)
+	return spec
+}
+
+// templateRefs returns the sorted top-level keys read by the template.
+func templateRefs(tmpl *template.Template) []string {
+	var refs []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			refs = appendNodeRefs(refs, t.Root, true)
+		}
+	}
+	slices.Sort(refs)
+	return slices.Compact(refs)
+}
+
+// appendNodeRefs appends the keys read by node. Fields are only keys while
+// dot is the root data, that is outside of range and with blocks; $ always
+// refers to the root.
+//
+//revive:disable-next-line:cyclomatic // one case per node type.
+func appendNodeRefs(refs []string, node parse.Node, rootDot bool) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return refs
+		}
+		for _, child := range n.Nodes {
+			refs = appendNodeRefs(refs, child, rootDot)
+		}
+	case *parse.ActionNode:
+		refs = appendNodeRefs(refs, n.Pipe, rootDot)
+	case *parse.PipeNode:
+		if n == nil {
+			return refs
+		}
+		for _, cmd := range n.Cmds {
+			refs = appendNodeRefs(refs, cmd, rootDot)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			refs = appendNodeRefs(refs, arg, rootDot)
+		}
+	case *parse.ChainNode:
+		refs = appendNodeRefs(refs, n.Node, rootDot)
+	case *parse.FieldNode:
+		if rootDot {
+			refs = append(refs, n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			refs = append(refs, n.Ident[1])
+		}
+	case *parse.IfNode:
+		refs = appendBranchRefs(refs, &n.BranchNode, rootDot, rootDot)
+	case *parse.RangeNode:
+		refs = appendBranchRefs(refs, &n.BranchNode, rootDot, false)
+	case *parse.WithNode:
+		refs = appendBranchRefs(refs, &n.BranchNode, rootDot, false)
+	case *parse.TemplateNode:
+		refs = appendNodeRefs(refs, n.Pipe, rootDot)
+	}
+	return refs
+}
+
+// appendBranchRefs appends the keys read by the pipeline of a branch, with
+// the outer dot, and by its list, with the dot of the block. The else list
+// keeps the outer dot.
+func appendBranchRefs(refs []string, n *parse.BranchNode, rootDot, listRootDot bool) []string {
+	refs = appendNodeRefs(refs, n.Pipe, rootDot)
+	refs = appendNodeRefs(refs, n.List, listRootDot)
+	return appendNodeRefs(refs, n.ElseList, rootDot)
+}
+
+// specDependencies returns the task IDs spec reads, given the IDs of the
+// tasks of the DAG.
+func specDependencies(spec internal.InputSpec, tasks map[string]*internal.Task) []string {
+	switch spec.Type {
+	case internal.TaskResultInputSpec, internal.FieldsInputSpec:
+		return []string{spec.Source}
+	case internal.TemplateInputSpec:
+		var deps []string
+		for _, ref := range spec.Refs {
+			if _, ok := tasks[ref]; ok {
+				deps = append(deps, ref)
+			}
+		}
+		return deps
+	case internal.TaggedInputSpec:
+		var deps []string
+		for _, binding := range spec.Bindings {
+			deps = append(deps, specDependencies(binding.Spec, tasks)...)
+		}
+		return deps
+	default:
+		return nil
+	}
+}
+
+// taskDependencies returns the task IDs task reads, in spec order, given
+// the tasks of the DAG.
+func taskDependencies(task *internal.Task, tasks map[string]*internal.Task) []string {
+	specs, _ := task.GetInputParams()
+	deps := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		deps = append(deps, specDependencies(spec, tasks)...)
+	}
+	return deps
+}
+
+// templateArg renders the template of spec against the keys it reads.
+func templateArg(taskID string, spec internal.InputSpec, param int) argResolver {
+	return func(results *Result) (reflect.Value, error) {
+		data := make(map[string]any, len(spec.Refs))
+		for _, ref := range spec.Refs {
+			value, err := getSource(results, taskID, internal.InputSpec{Source: ref})
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			data[ref] = value
+		}
+		var b strings.Builder
+		if err := spec.Template.Execute(&b, data); err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "parameter %d", param)
+		}
+		return reflect.ValueOf(b.String()), nil
+	}
+}
@@ -0,0 +1,97 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestUseTemplateRefs(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		text string
+		refs []string
+	}{
+		{text: "static", refs: nil},
+		{text: "Hello {{.fetchUser.Name}}, order {{.orderID}}", refs: []string{"fetchUser", "orderID"}},
+		{text: `{{if .vip}}VIP {{end}}{{printf "%05d" .orderID}}`, refs: []string{"orderID", "vip"}},
+		{text: "{{range .items}}{{.Name}}{{$.sep}}{{end}}", refs: []string{"items", "sep"}},
+		{text: "{{with .user}}{{.Name}}{{else}}{{.fallback}}{{end}}", refs: []string{"fallback", "user"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.text, func(t *testing.T) {
+			t.Parallel()
+
+			spec := UseTemplate(tc.text)
+
+			require.NoError(t, spec.Err)
+			require.Equal(t, tc.refs, spec.Refs)
+		})
+	}
+}
+
+func TestUseTemplate(t *testing.T) {
+	t.Parallel()
+
+	l := New().
+		Do("fetchUser", func(ctx context.Context, id int) (User, error) {
+			return User{ID: id, Name: "Alice"}, nil
+		}, UseRun("userID")).
+		Do("notify", func(ctx context.Context, message string) (string, error) {
+			return message, nil
+		}, UseTemplate("Hello {{.fetchUser.Name}}, order {{.orderID}}"))
+
+	graph, err := l.Graph()
+	require.NoError(t, err)
+	require.Equal(t, []string{"fetchUser"}, graph.Nodes[1].Dependencies)
+
+	result, err := l.Run(context.Background(), map[string]any{"userID": 1, "orderID": 42})
+	require.NoError(t, err)
+	message, err := result.Get("notify")
+	require.NoError(t, err)
+	require.Equal(t, "Hello Alice, order 42", message)
+
+	_, err = l.Run(context.Background(), map[string]any{"userID": 1})
+	require.ErrorIs(t, err, errors.ErrMissingRunInput)
+}
+
+func TestUseTemplateInvalid(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		fn   any
+		text string
+		err  error
+	}{
+		{
+			name: "parse error",
+			fn:   func(ctx context.Context, s string) error { return nil },
+			text: "{{.name",
+		},
+		{
+			name: "non-string parameter",
+			fn:   func(ctx context.Context, n int) error { return nil },
+			text: "{{.name}}",
+			err:  errors.ErrInvalidParamType,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New().Do("task", tc.fn, UseTemplate(tc.text)).Run(context.Background(), map[string]any{"name": "x"})
+
+			require.Error(t, err)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package lyra
+
+import (
+	"context"
+	stderr "errors"
+	"time"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+	"github.com/sourabh-kumar2/lyra/internal"
+)
+
+// WithTimeout cancels every attempt of the task that runs longer than d.
+// The task sees the cancellation through its context; if it then fails,
+// its error wraps ErrTaskTimeout. A timed out attempt is retried like any
+// other failure (see WithRetry), each retry getting d again.
+//
+// Example:
+//
+//	l.Do("fetchUser", fetchUser, lyra.UseRun("userID"),
+//		lyra.WithTimeout(2*time.Second), lyra.WithRetry(3))
+func WithTimeout(d time.Duration) TaskOption {
+	return internal.NewOptionSpec(func(o *internal.TaskOptions) {
+		o.Timeout = d
+	})
+}
+
+// callWithTimeout calls the task with its context canceled after the
+// task's timeout, if it has one.
+func callWithTimeout(
+	ctx context.Context,
+	stageIdx int,
+	task *compiledTask,
+	state *runState,
+) (output any, hasOutput bool, err error) {
+	timeout := task.GetOptions().Timeout
+	if timeout <= 0 {
+		return callHedged(ctx, stageIdx, task, state)
+	}
+
+	attemptCtx, cancel := context.WithTimeoutCause(ctx, timeout, errors.ErrTaskTimeout)
+	defer cancel()
+	output, hasOutput, err = callHedged(attemptCtx, stageIdx, task, state)
+	timedOut := ctx.Err() == nil && stderr.Is(context.Cause(attemptCtx), errors.ErrTaskTimeout)
+	if err != nil && timedOut && !stderr.Is(err, errors.ErrTaskTimeout) {
+		err = errors.Wrapf(err, "%w after %s", errors.ErrTaskTimeout, timeout)
+	}
+	return output, hasOutput, err
+}
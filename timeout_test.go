@@ -0,0 +1,47 @@
+package lyra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra/errors"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	l := New().Do("fetch", func(ctx context.Context) (int, error) {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		assert.NoError(t, ctx.Err())
+		return attempts, nil
+	}, WithTimeout(10*time.Millisecond), WithRetry(1))
+
+	result, err := l.Run(context.Background(), nil)
+	require.NoError(t, err)
+	value, err := result.Get("fetch")
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestWithTimeoutExceeded(t *testing.T) {
+	t.Parallel()
+
+	l := New().Do("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithTimeout(10*time.Millisecond))
+
+	_, err := l.Run(context.Background(), nil)
+	require.ErrorIs(t, err, errors.ErrTaskTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, TaskFailed, l.RecentRuns()[0].Tasks[0].Status)
+}
@@ -1,6 +1,8 @@
 package lyra
 
 import (
+	"strings"
+
 	"github.com/sourabh-kumar2/lyra/internal"
 )
 
@@ -53,3 +55,53 @@ func UseRun(source string, fieldPath ...string) internal.InputSpec {
 	it.Type = internal.RuntimeInputSpec
 	return it
 }
+
+// UseFields creates an InputSpec assembling a struct parameter from several
+// fields of another task's result, so the consumer neither couples to the
+// whole result type nor takes one parameter per field.
+//
+// fields maps the names of fields of the parameter struct to field paths in
+// the result of source, using dot notation for nested fields. Fields of the
+// parameter that are not mapped keep their zero value. The parameter may
+// also be a pointer to a struct.
+//
+// Example:
+//
+//	type Recipient struct {
+//		Name string
+//		City string
+//	}
+//
+//	l.Do("notify", func(ctx context.Context, r Recipient) error { ... },
+//		lyra.UseFields("fetchUser", map[string]string{"Name": "Name", "City": "Address.City"}))
+func UseFields(source string, fields map[string]string) internal.InputSpec {
+	projection := make(map[string][]string, len(fields))
+	for name, path := range fields {
+		projection[name] = strings.Split(path, ".")
+	}
+	return internal.InputSpec{
+		Type:       internal.FieldsInputSpec,
+		Source:     source,
+		Projection: projection,
+	}
+}
+
+// UseTags creates an InputSpec populating a struct parameter from the struct
+// tags of its fields, a structured alternative to long positional parameter
+// lists. The `lyra` tag binds a field to a task result and the `lyrarun` tag
+// to a runtime input, both with optional nested field access in dot
+// notation. Untagged fields keep their zero value, and the parameter may
+// also be a pointer to a struct.
+//
+// Example:
+//
+//	type NotifyParams struct {
+//		Name     string `lyra:"fetchUser.Name"`
+//		City     string `lyra:"fetchUser.Address.City"`
+//		Template string `lyrarun:"template"`
+//	}
+//
+//	l.Do("notify", func(ctx context.Context, p NotifyParams) error { ... }, lyra.UseTags())
+func UseTags() internal.InputSpec {
+	return internal.InputSpec{Type: internal.TaggedInputSpec}
+}
@@ -1,10 +1,12 @@
 package lyra
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourabh-kumar2/lyra/errors"
 	"github.com/sourabh-kumar2/lyra/internal"
 )
 
@@ -127,3 +129,165 @@ func TestUseRun(t *testing.T) {
 		})
 	}
 }
+
+func TestUseFields(t *testing.T) {
+	t.Parallel()
+
+	type Recipient struct {
+		Name  string
+		City  string
+		Notes string
+	}
+
+	fetchUser := func(ctx context.Context) (User, error) {
+		return User{Name: "Alice", Address: Address{City: "Paris"}}, nil
+	}
+	fields := map[string]string{"Name": "Name", "City": "Address.City"}
+
+	tcs := []struct {
+		name     string
+		fn       any
+		fields   map[string]string
+		expected any
+		err      error
+	}{
+		{
+			name:     "struct",
+			fn:       func(ctx context.Context, r Recipient) (Recipient, error) { return r, nil },
+			fields:   fields,
+			expected: Recipient{Name: "Alice", City: "Paris"},
+		},
+		{
+			name:     "pointer to struct",
+			fn:       func(ctx context.Context, r *Recipient) (Recipient, error) { return *r, nil },
+			fields:   fields,
+			expected: Recipient{Name: "Alice", City: "Paris"},
+		},
+		{
+			name:   "unknown parameter field",
+			fn:     func(ctx context.Context, r Recipient) (Recipient, error) { return r, nil },
+			fields: map[string]string{"Country": "Address.City"},
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name:   "non-struct parameter",
+			fn:     func(ctx context.Context, name string) (string, error) { return name, nil },
+			fields: fields,
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name:   "mismatched field type",
+			fn:     func(ctx context.Context, r Recipient) (Recipient, error) { return r, nil },
+			fields: map[string]string{"Name": "Address"},
+			err:    errors.ErrInvalidParamType,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().
+				Do("fetchUser", fetchUser).
+				Do("notify", tc.fn, UseFields("fetchUser", tc.fields))
+
+			result, err := l.Run(context.Background(), nil)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				notify, err := result.Get("notify")
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, notify)
+			}
+		})
+	}
+}
+
+func TestUseTags(t *testing.T) {
+	t.Parallel()
+
+	type Params struct {
+		Name     string `lyra:"fetchUser.Name"`
+		City     string `lyra:"fetchUser.Address.City"`
+		Greeting string `lyrarun:"greeting"`
+		Notes    string
+	}
+	type Both struct {
+		Name string `lyra:"fetchUser.Name" lyrarun:"name"`
+	}
+	type Untagged struct {
+		Name string
+	}
+
+	fetchUser := func(ctx context.Context) (User, error) {
+		return User{Name: "Alice", Address: Address{City: "Paris"}}, nil
+	}
+	expected := Params{Name: "Alice", City: "Paris", Greeting: "Hello"}
+
+	tcs := []struct {
+		name     string
+		fn       any
+		inputs   map[string]any
+		expected any
+		err      error
+	}{
+		{
+			name:     "struct",
+			fn:       func(ctx context.Context, p Params) (Params, error) { return p, nil },
+			inputs:   map[string]any{"greeting": "Hello"},
+			expected: expected,
+		},
+		{
+			name:     "pointer to struct",
+			fn:       func(ctx context.Context, p *Params) (Params, error) { return *p, nil },
+			inputs:   map[string]any{"greeting": "Hello"},
+			expected: expected,
+		},
+		{
+			name:   "missing runtime input",
+			fn:     func(ctx context.Context, p Params) (Params, error) { return p, nil },
+			inputs: map[string]any{},
+			err:    errors.ErrMissingRunInput,
+		},
+		{
+			name:   "mismatched field type",
+			fn:     func(ctx context.Context, p Params) (Params, error) { return p, nil },
+			inputs: map[string]any{"greeting": 1},
+			err:    errors.ErrInvalidParamType,
+		},
+		{
+			name: "both tags",
+			fn:   func(ctx context.Context, b Both) error { return nil },
+			err:  errors.ErrInvalidParamType,
+		},
+		{
+			name: "no tagged fields",
+			fn:   func(ctx context.Context, u Untagged) error { return nil },
+			err:  errors.ErrInvalidParamType,
+		},
+		{
+			name: "non-struct parameter",
+			fn:   func(ctx context.Context, name string) error { return nil },
+			err:  errors.ErrInvalidParamType,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := New().
+				Do("notify", tc.fn, UseTags()).
+				Do("fetchUser", fetchUser)
+
+			result, err := l.Run(context.Background(), tc.inputs)
+
+			require.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				notify, err := result.Get("notify")
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, notify)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package zaplog adapts a zap logger to lyra.Logger without making lyra
+// depend on zap.
+//
+// Example:
+//
+//	logger, _ := zap.NewProduction()
+//	l.Run(ctx, inputs, lyra.WithLogger(zaplog.New(logger.Sugar())))
+package zaplog
+
+import (
+	"github.com/sourabh-kumar2/lyra"
+)
+
+// SugaredLogger is the subset of *zap.SugaredLogger used by the adapter.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+}
+
+// New returns a lyra.Logger writing structured entries to logger.
+func New(logger SugaredLogger) lyra.Logger {
+	return adapter{logger: logger}
+}
+
+type adapter struct {
+	logger SugaredLogger
+}
+
+func (a adapter) Debug(msg string, keysAndValues ...any) {
+	a.logger.Debugw(msg, keysAndValues...)
+}
+
+func (a adapter) Info(msg string, keysAndValues ...any) {
+	a.logger.Infow(msg, keysAndValues...)
+}
+
+func (a adapter) Error(msg string, keysAndValues ...any) {
+	a.logger.Errorw(msg, keysAndValues...)
+}
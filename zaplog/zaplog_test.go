@@ -0,0 +1,50 @@
+package zaplog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourabh-kumar2/lyra"
+)
+
+type entry struct {
+	level         string
+	msg           string
+	keysAndValues []any
+}
+
+type fakeSugaredLogger struct {
+	entries []entry
+}
+
+func (f *fakeSugaredLogger) Debugw(msg string, keysAndValues ...any) {
+	f.entries = append(f.entries, entry{"debug", msg, keysAndValues})
+}
+
+func (f *fakeSugaredLogger) Infow(msg string, keysAndValues ...any) {
+	f.entries = append(f.entries, entry{"info", msg, keysAndValues})
+}
+
+func (f *fakeSugaredLogger) Errorw(msg string, keysAndValues ...any) {
+	f.entries = append(f.entries, entry{"error", msg, keysAndValues})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeSugaredLogger{}
+	l := lyra.New().Do("task", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	_, err := l.Run(context.Background(), nil, lyra.WithRunID("run-1"), lyra.WithLogger(New(fake)))
+
+	require.NoError(t, err)
+	require.Contains(t, fake.entries, entry{
+		level:         "debug",
+		msg:           "lyra: task_finished",
+		keysAndValues: []any{"run_id", "run-1", "stage", 0, "task_id", "task"},
+	})
+}